@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"fish-generate/internal/config"
+	"fish-generate/internal/data"
+)
+
+// EvalCase is one stored generation context replayed through the fish pipeline
+type EvalCase struct {
+	ID         string            `json:"id"`
+	Task       string            `json:"task,omitempty"`   // fish_from_context (default) or fish_from_news
+	Reason     string            `json:"reason,omitempty"` // Generation reason passed to fish_from_context
+	News       *data.NewsItem    `json:"news,omitempty"`
+	MergedNews []*data.NewsItem  `json:"merged_news,omitempty"`
+	Weather    *data.WeatherInfo `json:"weather,omitempty"`
+	Bitcoin    *data.CryptoPrice `json:"bitcoin,omitempty"`
+	Gold       *data.GoldPrice   `json:"gold,omitempty"`
+	Signals    []string          `json:"signals,omitempty"` // Summaries of other sources, e.g. "OIL PRICE: $81.20 per barrel (-0.40% change)"
+}
+
+// headlines returns every headline the case gives the model
+func (c *EvalCase) headlines() []string {
+	var headlines []string
+	if c.News != nil && c.News.Headline != "" {
+		headlines = append(headlines, c.News.Headline)
+	}
+	for _, news := range c.MergedNews {
+		if news != nil && news.Headline != "" {
+			headlines = append(headlines, news.Headline)
+		}
+	}
+	return headlines
+}
+
+// contextData builds the context map the data manager passes to fish_from_context
+func (c *EvalCase) contextData() map[string]interface{} {
+	contextData := make(map[string]interface{})
+	if c.News != nil {
+		contextData["news"] = c.News
+	}
+	if len(c.MergedNews) > 0 {
+		contextData["merged_news"] = c.MergedNews
+	}
+	if c.Weather != nil {
+		contextData["weather"] = c.Weather
+	}
+	if c.Bitcoin != nil {
+		contextData["bitcoin"] = c.Bitcoin
+	}
+	if c.Gold != nil {
+		contextData["gold"] = c.Gold
+	}
+	if len(c.Signals) > 0 {
+		contextData["signals"] = c.Signals
+	}
+	return contextData
+}
+
+// loadCorpus reads a JSON array of cases
+func loadCorpus(path string) ([]*EvalCase, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %v", err)
+	}
+
+	var cases []*EvalCase
+	if err := json.Unmarshal(raw, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse corpus %s: %v", path, err)
+	}
+	for i, c := range cases {
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", i+1)
+		}
+		switch c.Task {
+		case "":
+			c.Task = data.LLMTaskFishFromContext
+		case data.LLMTaskFishFromContext:
+		case data.LLMTaskFishFromNews:
+			if c.News == nil {
+				return nil, fmt.Errorf("case %s: %s needs a news item", c.ID, c.Task)
+			}
+		default:
+			return nil, fmt.Errorf("case %s: unsupported task '%s'", c.ID, c.Task)
+		}
+	}
+	return cases, nil
+}
+
+// loadBannedTerms reads one term per line, skipping blank lines and # comments
+func loadBannedTerms(path string) ([]string, error) {
+	if path == "" {
+		return defaultBannedTerms, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read banned terms: %v", err)
+	}
+	var terms []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			terms = append(terms, line)
+		}
+	}
+	return terms, nil
+}
+
+func main() {
+	corpusPath := flag.String("corpus", "cmd/fish-eval/corpus.example.json", "JSON file of generation contexts to replay")
+	outPrefix := flag.String("out", "fish-eval-report", "Report path without extension; .json and .md are written")
+	label := flag.String("label", "", "Name of this run in the report (default: provider and model)")
+	template := flag.String("template", "", "Prompt template for fish_from_context cases (default: fish_from_context)")
+	promptDir := flag.String("prompts", "", "Directory of prompt templates overriding the built-in ones")
+	model := flag.String("model", "", "Model overriding LLM_MODEL for this run")
+	repairAttempts := flag.Int("repair", 0, "Corrective re-prompts after an invalid response; 0 scores the first answer")
+	bannedPath := flag.String("banned", "", "File of banned terms, one per line (default: a built-in list)")
+	comparePath := flag.String("compare", "", "JSON report of an earlier run to compare against")
+	flag.Parse()
+
+	// Provider settings come from the same environment as the service
+	config.LoadEnv(".env")
+	conf := config.NewConfig()
+
+	cases, err := loadCorpus(*corpusPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	bannedTerms, err := loadBannedTerms(*bannedPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var baseline *EvalReport
+	if *comparePath != "" {
+		if baseline, err = loadReport(*comparePath); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	prompts := data.DefaultPromptLibrary()
+	if *promptDir != "" {
+		if prompts, err = data.NewPromptLibrary(*promptDir); err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+	}
+
+	// One client per task so each uses its own provider settings
+	clients := make(map[string]*data.GeminiClient)
+	var provider, modelName string
+	for _, task := range []string{data.LLMTaskFishFromContext, data.LLMTaskFishFromNews} {
+		llm, err := data.NewLLMTask(task, conf.GeminiAPIKey)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if *model != "" {
+			llm.Options.Model = *model
+		}
+		llm.Prompts = prompts
+		if task == data.LLMTaskFishFromContext {
+			provider, modelName = llm.Provider.Name(), llm.Options.Model
+		}
+
+		client := data.NewGeminiClientWithLLM(llm)
+		client.SetRepairAttempts(*repairAttempts)
+		defer client.Close()
+		clients[task] = client
+	}
+
+	if *label == "" {
+		*label = provider + "/" + modelName
+	}
+	log.Printf("Evaluating %d cases with %s", len(cases), *label)
+
+	scorer := newScorer(bannedTerms)
+	report := &EvalReport{
+		Label:          *label,
+		Provider:       provider,
+		Model:          modelName,
+		Template:       *template,
+		RepairAttempts: *repairAttempts,
+		Corpus:         *corpusPath,
+		StartedAt:      time.Now().UTC(),
+	}
+
+	ctx := context.Background()
+	for _, c := range cases {
+		start := time.Now()
+		var fish *data.FishGenerationResponse
+		if c.Task == data.LLMTaskFishFromNews {
+			fish, err = clients[c.Task].GenerateFishFromNews(ctx, c.News)
+		} else {
+			reason := c.Reason
+			if reason == "" {
+				reason = "Offline evaluation"
+			}
+			fish, err = clients[c.Task].GenerateUniqueFishFromContext(ctx, c.contextData(), reason, *template)
+		}
+
+		result := scorer.score(c, fish, err)
+		result.LatencyMS = time.Since(start).Milliseconds()
+		report.Cases = append(report.Cases, result)
+
+		if result.Valid {
+			log.Printf("%s: %s (score %.2f)", c.ID, result.Name, result.Score)
+		} else {
+			log.Printf("%s: invalid (%s)", c.ID, result.FailureKind)
+		}
+	}
+	report.Summary = summarize(report.Cases)
+
+	if err := writeReports(*outPrefix, report, baseline); err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("Score %.2f (valid %.0f%%) across %d cases; report written to %s.json and %s.md\n",
+		report.Summary.Score, report.Summary.ValidRate*100, report.Summary.Cases, *outPrefix, *outPrefix)
+}
+
+// failureKind returns the FishGenerationError kind of a failed generation
+func failureKind(err error) string {
+	var genErr *data.FishGenerationError
+	if errors.As(err, &genErr) {
+		return genErr.Kind
+	}
+	return "error"
+}
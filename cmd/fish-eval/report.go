@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EvalReport is the result of one evaluation run. Reports of different runs over the
+// same corpus can be compared with -compare.
+type EvalReport struct {
+	Label          string        `json:"label"`
+	Provider       string        `json:"provider"`
+	Model          string        `json:"model"`
+	Template       string        `json:"template,omitempty"`
+	RepairAttempts int           `json:"repair_attempts"`
+	Corpus         string        `json:"corpus"`
+	StartedAt      time.Time     `json:"started_at"`
+	Summary        EvalSummary   `json:"summary"`
+	Cases          []*CaseResult `json:"cases"`
+}
+
+// EvalSummary aggregates the case results of a run
+type EvalSummary struct {
+	Cases           int            `json:"cases"`
+	ValidRate       float64        `json:"valid_rate"`
+	FailureKinds    map[string]int `json:"failure_kinds,omitempty"`
+	AvgLengthScore  float64        `json:"avg_length_score"`     // Over valid fish
+	UniqueNameRate  float64        `json:"unique_name_rate"`     // Over valid fish
+	AvgSourceRef    float64        `json:"avg_source_reference"` // Over valid fish with headlines
+	BannedContent   int            `json:"banned_content"`       // Valid fish with at least one banned term
+	AvgFieldLengths map[string]int `json:"avg_field_lengths,omitempty"`
+	AvgLatencyMS    int64          `json:"avg_latency_ms"`
+	Score           float64        `json:"score"` // Mean case score, counting invalid responses as zero
+}
+
+// summarize aggregates case results
+func summarize(cases []*CaseResult) EvalSummary {
+	summary := EvalSummary{Cases: len(cases), FailureKinds: make(map[string]int), AvgFieldLengths: make(map[string]int)}
+	if len(cases) == 0 {
+		return summary
+	}
+
+	var valid, unique, withSource int
+	var lengthTotal, sourceTotal, scoreTotal float64
+	var latencyTotal int64
+	fieldTotals := make(map[string]int)
+	for _, result := range cases {
+		scoreTotal += result.Score
+		latencyTotal += result.LatencyMS
+		if !result.Valid {
+			summary.FailureKinds[result.FailureKind]++
+			continue
+		}
+
+		valid++
+		lengthTotal += result.LengthScore
+		if result.NameUnique {
+			unique++
+		}
+		if result.SourceReference != nil {
+			withSource++
+			sourceTotal += *result.SourceReference
+		}
+		if len(result.BannedTerms) > 0 {
+			summary.BannedContent++
+		}
+		for field, length := range result.FieldLengths {
+			fieldTotals[field] += length
+		}
+	}
+
+	summary.ValidRate = float64(valid) / float64(len(cases))
+	summary.Score = scoreTotal / float64(len(cases))
+	summary.AvgLatencyMS = latencyTotal / int64(len(cases))
+	if valid > 0 {
+		summary.AvgLengthScore = lengthTotal / float64(valid)
+		summary.UniqueNameRate = float64(unique) / float64(valid)
+		for field, total := range fieldTotals {
+			summary.AvgFieldLengths[field] = total / valid
+		}
+	}
+	if withSource > 0 {
+		summary.AvgSourceRef = sourceTotal / float64(withSource)
+	}
+	return summary
+}
+
+// loadReport reads the JSON report of an earlier run
+func loadReport(path string) (*EvalReport, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report to compare against: %v", err)
+	}
+	var report EvalReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %v", path, err)
+	}
+	return &report, nil
+}
+
+// writeReports writes the report as <prefix>.json and <prefix>.md
+func writeReports(prefix string, report *EvalReport, baseline *EvalReport) error {
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(prefix), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %v", err)
+	}
+	if err := os.WriteFile(prefix+".json", raw, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	if err := os.WriteFile(prefix+".md", []byte(markdownReport(report, baseline)), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	return nil
+}
+
+// markdownReport renders the summary, the comparison with the baseline and every case
+func markdownReport(report *EvalReport, baseline *EvalReport) string {
+	var b strings.Builder
+	s := report.Summary
+
+	fmt.Fprintf(&b, "# Fish generation evaluation: %s\n\n", report.Label)
+	fmt.Fprintf(&b, "- Provider: %s, model: %s\n", report.Provider, report.Model)
+	template := report.Template
+	if template == "" {
+		template = "fish_from_context"
+	}
+	fmt.Fprintf(&b, "- Template: %s, repair attempts: %d\n", template, report.RepairAttempts)
+	fmt.Fprintf(&b, "- Corpus: %s (%d cases), run at %s\n\n", report.Corpus, s.Cases, report.StartedAt.Format(time.RFC3339))
+
+	b.WriteString("## Summary\n\n")
+	if baseline != nil {
+		fmt.Fprintf(&b, "| Metric | %s | %s | Change |\n|---|---|---|---|\n", report.Label, baseline.Label)
+	} else {
+		b.WriteString("| Metric | Value |\n|---|---|\n")
+	}
+	bs := EvalSummary{}
+	if baseline != nil {
+		bs = baseline.Summary
+	}
+	rows := []struct {
+		name           string
+		value, base    float64
+		percent, count bool
+	}{
+		{"Score", s.Score, bs.Score, false, false},
+		{"Valid responses", s.ValidRate, bs.ValidRate, true, false},
+		{"Length within target", s.AvgLengthScore, bs.AvgLengthScore, true, false},
+		{"Unique names", s.UniqueNameRate, bs.UniqueNameRate, true, false},
+		{"Source reference", s.AvgSourceRef, bs.AvgSourceRef, true, false},
+		{"Fish with banned content", float64(s.BannedContent), float64(bs.BannedContent), false, true},
+		{"Average latency (ms)", float64(s.AvgLatencyMS), float64(bs.AvgLatencyMS), false, true},
+	}
+	for _, row := range rows {
+		format := func(value float64) string {
+			switch {
+			case row.percent:
+				return fmt.Sprintf("%.1f%%", value*100)
+			case row.count:
+				return fmt.Sprintf("%.0f", value)
+			default:
+				return fmt.Sprintf("%.3f", value)
+			}
+		}
+		if baseline != nil {
+			change := row.value - row.base
+			if row.percent {
+				change *= 100
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %+.1f |\n", row.name, format(row.value), format(row.base), change)
+		} else {
+			fmt.Fprintf(&b, "| %s | %s |\n", row.name, format(row.value))
+		}
+	}
+
+	if len(s.FailureKinds) > 0 {
+		b.WriteString("\n## Failures\n\n")
+		kinds := make([]string, 0, len(s.FailureKinds))
+		for kind := range s.FailureKinds {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Fprintf(&b, "- %s: %d\n", kind, s.FailureKinds[kind])
+		}
+	}
+
+	b.WriteString("\n## Cases\n\n| Case | Name | Score | Lengths | Unique | Source | Banned |\n|---|---|---|---|---|---|---|\n")
+	for _, result := range report.Cases {
+		if !result.Valid {
+			fmt.Fprintf(&b, "| %s | invalid (%s) | 0.00 | | | | |\n", result.ID, result.FailureKind)
+			continue
+		}
+		unique := "yes"
+		if !result.NameUnique {
+			unique = "no, like " + markdownEscape(result.DuplicateOf)
+		}
+		source := "n/a"
+		if result.SourceReference != nil {
+			source = fmt.Sprintf("%.0f%%", *result.SourceReference*100)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %.2f | %.0f%% | %s | %s | %s |\n", result.ID, markdownEscape(result.Name),
+			result.Score, result.LengthScore*100, unique, source, strings.Join(result.BannedTerms, ", "))
+	}
+	return b.String()
+}
+
+// markdownEscape keeps a value from breaking a table row
+func markdownEscape(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
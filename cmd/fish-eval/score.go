@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"fish-generate/internal/data"
+)
+
+// lengthTargets are the lengths, in characters, a well-sized fish field falls within.
+// They are narrower than the schema bounds, which only reject unusable answers.
+var lengthTargets = []struct {
+	Field    string
+	Min, Max int
+}{
+	{"name", 6, 40},
+	{"description", 80, 400},
+	{"appearance", 40, 300},
+	{"effect", 20, 200},
+	{"existence_reason", 30, 300},
+}
+
+// defaultBannedTerms flags content unsuitable for the game or leaked from the model itself
+var defaultBannedTerms = []string{
+	"fuck", "shit", "bitch", "nazi", "rape", "porn", "suicide", "terrorist",
+	"as an ai", "language model", "i cannot", "lorem ipsum",
+}
+
+// sourceStopWords are headline words too common to show the description used the headline
+var sourceStopWords = map[string]bool{
+	"about": true, "after": true, "again": true, "against": true, "amid": true, "before": true,
+	"being": true, "could": true, "their": true, "there": true, "these": true, "which": true,
+	"while": true, "would": true, "where": true, "other": true, "over": true, "under": true,
+	"says": true, "said": true, "with": true, "from": true, "into": true, "will": true,
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// CaseResult is the score of one replayed case
+type CaseResult struct {
+	ID              string         `json:"id"`
+	Task            string         `json:"task"`
+	Valid           bool           `json:"valid"`
+	FailureKind     string         `json:"failure_kind,omitempty"`
+	Error           string         `json:"error,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	PromptVersion   string         `json:"prompt_version,omitempty"`
+	FieldLengths    map[string]int `json:"field_lengths,omitempty"`
+	LengthScore     float64        `json:"length_score"`           // Share of fields within their length target
+	NameUnique      bool           `json:"name_unique"`            // No earlier fish in the run has the same or a similar name
+	DuplicateOf     string         `json:"duplicate_of,omitempty"` // Name of the earlier fish it collides with
+	SourceReference *float64       `json:"source_reference"`       // Share of headline keywords the fish mentions; null without headlines
+	BannedTerms     []string       `json:"banned_terms,omitempty"`
+	Score           float64        `json:"score"` // Mean of the checks above; zero for invalid responses
+	LatencyMS       int64          `json:"latency_ms"`
+}
+
+// scorer scores the fish of one run, remembering earlier names for the uniqueness check
+type scorer struct {
+	bannedTerms []string
+	catalog     *runCatalog
+	guard       *data.SpeciesGuard
+}
+
+// runCatalog is the catalog of fish generated so far in the run
+type runCatalog struct {
+	species []data.SpeciesSummary
+}
+
+// GetSpeciesSummaries returns the fish generated so far
+func (c *runCatalog) GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error) {
+	return c.species, nil
+}
+
+// newScorer creates a scorer that flags the given terms
+func newScorer(bannedTerms []string) *scorer {
+	catalog := &runCatalog{}
+	return &scorer{
+		bannedTerms: bannedTerms,
+		catalog:     catalog,
+		guard:       data.NewSpeciesGuard(catalog, data.DefaultSpeciesGuardSettings()),
+	}
+}
+
+// score checks one generated fish, or records why the generation failed
+func (s *scorer) score(c *EvalCase, fish *data.FishGenerationResponse, err error) *CaseResult {
+	result := &CaseResult{ID: c.ID, Task: c.Task}
+	if err != nil {
+		result.FailureKind = failureKind(err)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Valid = true
+	result.Name = fish.Name
+	result.PromptVersion = fish.PromptVersion
+	fields := fishFields(fish)
+	checks := []float64{}
+
+	// Field lengths
+	result.FieldLengths = make(map[string]int)
+	within := 0
+	for _, target := range lengthTargets {
+		length := utf8.RuneCountInString(fields[target.Field])
+		result.FieldLengths[target.Field] = length
+		if length >= target.Min && length <= target.Max {
+			within++
+		}
+	}
+	result.LengthScore = float64(within) / float64(len(lengthTargets))
+	checks = append(checks, result.LengthScore)
+
+	// Name uniqueness within the run; similar descriptions alone do not count
+	record := &data.FishRecord{Name: fish.Name, Description: fish.Description}
+	match, _ := s.guard.Check(context.Background(), record)
+	result.NameUnique = match == nil || match.Reason == data.SpeciesMatchDescription
+	if !result.NameUnique {
+		result.DuplicateOf = match.Species.Name
+	}
+	s.catalog.species = append(s.catalog.species, data.SpeciesSummary{ID: c.ID, Name: fish.Name, Description: fish.Description})
+	checks = append(checks, boolScore(result.NameUnique))
+
+	// Source reference
+	text := strings.ToLower(strings.Join([]string{fields["description"], fields["appearance"], fields["effect"], fields["existence_reason"], fields["origin_context"]}, " "))
+	if keywords := headlineKeywords(c.headlines()); len(keywords) > 0 {
+		mentioned := 0
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				mentioned++
+			}
+		}
+		reference := float64(mentioned) / float64(len(keywords))
+		result.SourceReference = &reference
+		checks = append(checks, reference)
+	}
+
+	// Banned content, in every field including the name
+	allText := strings.ToLower(strings.Join([]string{text, fields["name"], fields["color"], fields["diet"], fields["habitat"]}, " "))
+	for _, term := range s.bannedTerms {
+		if strings.Contains(allText, strings.ToLower(term)) {
+			result.BannedTerms = append(result.BannedTerms, term)
+		}
+	}
+	checks = append(checks, boolScore(len(result.BannedTerms) == 0))
+
+	total := 0.0
+	for _, check := range checks {
+		total += check
+	}
+	result.Score = total / float64(len(checks))
+	return result
+}
+
+// fishFields maps the text fields of a fish response by their JSON names
+func fishFields(fish *data.FishGenerationResponse) map[string]string {
+	return map[string]string{
+		"name":             fish.Name,
+		"description":      fish.Description,
+		"appearance":       fish.Appearance,
+		"color":            fish.Color,
+		"diet":             fish.Diet,
+		"habitat":          fish.Habitat,
+		"effect":           fish.Effect,
+		"existence_reason": fish.ExistenceReason,
+		"origin_context":   fish.OriginContext,
+	}
+}
+
+// headlineKeywords returns the distinct lowercase words of at least five letters in the headlines
+func headlineKeywords(headlines []string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, headline := range headlines {
+		for _, word := range wordPattern.FindAllString(strings.ToLower(headline), -1) {
+			if utf8.RuneCountInString(word) < 5 || sourceStopWords[word] || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// boolScore turns a passed check into 1 and a failed one into 0
+func boolScore(passed bool) float64 {
+	if passed {
+		return 1
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -20,6 +21,8 @@ import (
 func main() {
 	// Parse command line flags
 	testMode := flag.Bool("test", false, "Run in test mode with shorter collection intervals")
+	migrateCmd := flag.String("migrate", "", "Manage MongoDB schema migrations and exit: list, apply or dry-run")
+	flag.Usage = printUsage
 	flag.Parse()
 
 	// Load environment variables from .env file if it exists
@@ -28,28 +31,70 @@ func main() {
 	// Create configuration
 	conf := config.NewConfig()
 
-	// Initialize MongoDB if configured
+	// Run a migration command instead of the service if requested
+	if *migrateCmd != "" {
+		if err := runMigrateCommand(conf, *migrateCmd); err != nil {
+			log.Fatalf("Migration command failed: %v", err)
+		}
+		return
+	}
+
+	// Initialize the configured storage backend
 	var mongoStorage *storage.MongoDB
+	var sqliteStorage *storage.SQLiteDB
+	var memoryStorage *storage.MemoryDB
 	var storageAdapter storage.StorageAdapter
 
-	if conf.MongoURI != "" {
+	backend, err := conf.GetStorageBackend()
+	if err != nil {
+		log.Fatalf("Invalid storage configuration: %v", err)
+	}
+
+	// A backend chosen with STORAGE_BACKEND must start; only the default falls back to memory
+	switch backend {
+	case "mongodb":
 		mongoURI := conf.GetMongoURI()
 		mongoDB := conf.GetMongoDB()
 
 		log.Printf("Connecting to MongoDB at %s...", mongoURI)
-		var err error
 		mongoStorage, err = storage.NewMongoDB(mongoURI, mongoDB)
 		if err != nil {
+			if conf.StorageBackend != "" {
+				log.Fatalf("MongoDB initialization failed: %v", err)
+			}
 			log.Printf("Warning: MongoDB connection failed: %v", err)
 		} else {
 			storageAdapter = storage.NewMongoDBAdapter(mongoStorage)
 			log.Println("MongoDB connection established successfully")
-			log.Printf("Daily fish generation limit: %d fish", fish.DailyFishLimit)
 		}
-	} else {
-		log.Println("MongoDB not configured. Data will not be persisted.")
+
+	case "sqlite":
+		sqlitePath := conf.GetSQLitePath()
+
+		log.Printf("Opening SQLite database at %s...", sqlitePath)
+		sqliteStorage, err = storage.NewSQLiteDB(sqlitePath)
+		if err != nil {
+			log.Fatalf("SQLite initialization failed: %v", err)
+		}
+		storageAdapter = storage.NewMongoDBAdapter(sqliteStorage)
+		log.Println("SQLite storage ready")
 	}
 
+	// Fall back to in-memory storage so the API and generators keep working
+	if storageAdapter == nil {
+		memoryStorage, err = storage.NewMemoryDB(conf.MemorySnapshotPath)
+		if err != nil {
+			log.Fatalf("Failed to initialize in-memory storage: %v", err)
+		}
+		storageAdapter = storage.NewMongoDBAdapter(memoryStorage)
+		if conf.MemorySnapshotPath != "" {
+			log.Printf("Using in-memory storage with snapshots at %s", conf.MemorySnapshotPath)
+		} else {
+			log.Println("Using in-memory storage; data will not be persisted.")
+		}
+	}
+	log.Printf("Daily fish generation limit: %d fish", fish.DailyFishLimit)
+
 	// Create context that is canceled when the program receives an interrupt signal
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -63,52 +108,195 @@ func main() {
 		cancel()
 	}()
 
-	// Configure data collection intervals
+	// Record the tokens and cost of every LLM call and enforce the daily budget
+	llmUsage := data.NewLLMUsageTracker(storageAdapter, data.LLMUsageSettings{
+		DailyTokenBudget:        conf.LLMDailyTokenBudget,
+		PromptPricePerMillion:   conf.LLMPromptPricePerMTok,
+		ResponsePricePerMillion: conf.LLMResponsePricePerMTok,
+	})
+	if conf.LLMDailyTokenBudget > 0 {
+		log.Printf("LLM daily token budget: %d", conf.LLMDailyTokenBudget)
+	}
+
+	// Load the prompt templates; files in PROMPT_TEMPLATE_DIR are reloaded when edited
+	prompts, err := data.NewPromptLibrary(conf.PromptTemplateDir)
+	if err != nil {
+		log.Fatalf("Failed to load prompt templates: %v", err)
+	}
+	if conf.PromptTemplateDir != "" {
+		log.Printf("Using prompt templates from %s", conf.PromptTemplateDir)
+	}
+
+	// Split fish generation across the prompt variants of EXPERIMENTS_FILE
+	var experimentDefinitions []data.PromptExperiment
+	if conf.ExperimentsFile != "" {
+		experimentDefinitions, err = data.LoadPromptExperiments(conf.ExperimentsFile)
+		if err != nil {
+			log.Fatalf("Failed to load prompt experiments: %v", err)
+		}
+	}
+	experiments := data.NewPromptExperiments(experimentDefinitions, storageAdapter)
+	for _, template := range experiments.Templates() {
+		if err := prompts.Preload(template); err != nil {
+			log.Fatalf("Failed to load prompt experiment template: %v", err)
+		}
+	}
+	if experiment, ok := experiments.Active(); ok {
+		log.Printf("Prompt experiment %s is running with %d variants", experiment.Name, len(experiment.Variants))
+	}
+
+	// Keep generated fish from duplicating species already in the catalog
+	var speciesGuard *data.SpeciesGuard
+	if conf.SpeciesGuardEnabled {
+		guardSettings := data.DefaultSpeciesGuardSettings()
+		guardSettings.RenameAttempts = conf.SpeciesRenameAttempts
+		guardSettings.NameSimilarity = conf.SpeciesNameSimilarity
+		guardSettings.NameTokenOverlap = conf.SpeciesNameTokenOverlap
+		guardSettings.DescriptionSimilarity = conf.SpeciesDescriptionSimilarity
+		speciesGuard = data.NewSpeciesGuard(storageAdapter, guardSettings)
+		log.Printf("Species uniqueness guard enabled (rename attempts: %d)", guardSettings.RenameAttempts)
+	}
+
+	// Create the LLM provider for each generation task
+	llmTasks := make(map[string]data.LLMTask)
+	for _, task := range []string{data.LLMTaskFishFromNews, data.LLMTaskFishFromContext, data.LLMTaskTranslation} {
+		llm, err := data.NewLLMTask(task, conf.GeminiAPIKey)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("LLM for %s: %s (model: %s)", task, llm.Provider.Name(), llm.Options.Model)
+
+		// Reuse stored responses for identical requests, e.g. retries and recovered queue items
+		if conf.LLMCacheEnabled {
+			llm.Provider = data.NewCachingProvider(llm.Provider, storageAdapter, data.LLMCacheSettings{
+				TTL:        conf.GetLLMCacheTTL(),
+				MaxEntries: conf.LLMCacheMaxEntries,
+			})
+		}
+		llm.Usage = llmUsage
+		llm.Prompts = prompts
+		llmTasks[task] = llm
+	}
+	if conf.LLMCacheEnabled {
+		log.Printf("LLM response cache enabled (TTL: %v, max entries: %d)", conf.GetLLMCacheTTL(), conf.LLMCacheMaxEntries)
+	}
+
+	// Quarantine generated fish with unsafe content for admin review
+	var moderator *data.ContentModerator
+	if conf.ModerationEnabled {
+		rules := data.DefaultModerationRules()
+		if conf.ModerationRulesFile != "" {
+			rules, err = data.LoadModerationRules(conf.ModerationRulesFile)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+		var reviewer data.ContentReviewer
+		if conf.ModerationSelfCheck {
+			reviewer = data.NewGeminiClientWithLLM(llmTasks[data.LLMTaskFishFromContext])
+		}
+		moderator, err = data.NewContentModerator(rules, reviewer)
+		if err != nil {
+			log.Fatalf("Failed to load moderation rules: %v", err)
+		}
+		log.Printf("Content moderation enabled (%d blocklist terms, %d patterns, self-check: %v)",
+			len(rules.Blocklist), len(rules.Patterns), conf.ModerationSelfCheck)
+	}
+
+	// Record or replay the collectors' API responses
+	var collectorTransport http.RoundTripper
+	if conf.HTTPFixturesMode != "" {
+		collectorTransport, err = data.NewFixtureTransport(conf.HTTPFixturesMode, conf.GetHTTPFixturesDir())
+		if err != nil {
+			log.Fatalf("Invalid HTTP_FIXTURES_MODE: %v", err)
+		}
+		log.Printf("Collector HTTP fixtures: %s (%s)", conf.HTTPFixturesMode, conf.GetHTTPFixturesDir())
+	}
+
+	// Count the calls to rate-limited APIs in storage; replayed responses cost nothing
+	quotas := make(map[string]data.APIQuota)
+	for name, settings := range conf.Collectors {
+		if settings.DailyQuota > 0 || settings.MonthlyQuota > 0 {
+			quotas[name] = data.APIQuota{Daily: settings.DailyQuota, Monthly: settings.MonthlyQuota, Burst: settings.QuotaBurst}
+		}
+	}
+	var apiQuotas *data.APIQuotaManager
+	if conf.HTTPFixturesMode != data.FixtureModeReplay {
+		apiQuotas = data.NewAPIQuotaManager(storageAdapter, quotas, conf.CollectorQuotaMargin)
+	}
+
+	// Create the enabled data collectors, each behind retries and a circuit breaker
+	resilience := data.ResilienceSettings{
+		MaxAttempts:      conf.CollectorMaxAttempts,
+		FailureThreshold: conf.CollectorBreakerThreshold,
+		OpenTimeout:      conf.GetCollectorBreakerTimeout(),
+	}
+	collectorConfigs := make(map[string]data.CollectorConfig, len(conf.Collectors))
+	for name, settings := range conf.Collectors {
+		collectorConfigs[name] = data.CollectorConfig{
+			Enabled:    settings.Enabled,
+			Interval:   settings.GetInterval(),
+			APIKey:     settings.APIKey,
+			HTTP:       data.HTTPOptions{BaseURL: settings.BaseURL, Transport: apiQuotas.Transport(name, collectorTransport)},
+			Resilience: resilience,
+		}
+	}
+	collectors, err := data.DefaultCollectorRegistry().Build(collectorConfigs)
+	if err != nil {
+		log.Fatalf("Invalid COLLECTORS: %v", err)
+	}
+	for _, collector := range collectors {
+		log.Printf("Collector %s enabled (interval: %v)", collector.Name, collector.Interval)
+		if quota, ok := apiQuotas.Quota(collector.Name); ok {
+			log.Printf("Collector %s API quota: %d per day, %d per month, burst %d (0 for no limit)", collector.Name, quota.Daily, quota.Monthly, quota.Burst)
+		}
+	}
+
+	// Configure data collection
 	collectionSettings := data.CollectionSettings{
-		WeatherInterval:    conf.GetWeatherInterval(),
-		PriceInterval:      conf.GetPriceInterval(),
-		NewsInterval:       conf.GetNewsInterval(),
-		GenerationCooldown: conf.GetGenerationCooldown(),
-		TestMode:           *testMode || conf.TestMode,
-		GeminiApiKey:       conf.GeminiAPIKey,
+		Collectors:          collectors,
+		GenerationCooldown:  conf.GetGenerationCooldown(),
+		TestMode:            *testMode || conf.TestMode,
+		GeminiApiKey:        conf.GeminiAPIKey,
+		FishLLM:             llmTasks[data.LLMTaskFishFromContext],
+		LLMUsage:            llmUsage,
+		Experiments:         experiments,
+		SpeciesGuard:        speciesGuard,
+		Moderator:           moderator,
+		ReviewGeneratedFish: conf.ReviewGeneratedFish,
 	}
 
 	// Create data manager
-	dataManager := data.NewDataManager(
-		collectionSettings,
-		storageAdapter,
-		conf.OpenWeatherKey,
-		conf.NewsAPIKey,
-		conf.MetalPriceKey,
-		conf.GeminiAPIKey,
-	)
+	dataManager := data.NewDataManager(collectionSettings, storageAdapter, conf.GeminiAPIKey)
 
 	// Start data collection
 	dataManager.Start(ctx)
 
 	// Create fish generation service options
 	serviceOpts := fish.ServiceOptions{
-		GeminiAPIKey: conf.GeminiAPIKey,
-		UseAI:        conf.UseAI,
-		TestMode:     *testMode || conf.TestMode,
+		GeminiAPIKey:        conf.GeminiAPIKey,
+		UseAI:               conf.UseAI,
+		LLM:                 llmTasks[data.LLMTaskFishFromNews],
+		TestMode:            *testMode || conf.TestMode,
+		SpeciesGuard:        speciesGuard,
+		Moderator:           moderator,
+		ReviewGeneratedFish: conf.ReviewGeneratedFish,
 	}
 
 	// Create the fish service
 	fishService := fish.NewService(dataManager.GetCollectors(), serviceOpts)
 
 	// Create a simplified fish generation service wrapper
-	var fishGenService *fish.FishGenerationService
+	genServiceOpts := serviceOpts
 	if storageAdapter != nil {
 		wrapper, err := fish.NewStorageWrapper(storageAdapter)
 		if err != nil {
 			log.Printf("Warning: could not create storage wrapper for fish generation service: %v", err)
-			fishGenService = fish.NewFishGenerationService(conf.GeminiAPIKey, nil, dataManager)
 		} else {
-			fishGenService = fish.NewFishGenerationService(conf.GeminiAPIKey, wrapper, dataManager)
+			genServiceOpts.StorageAdapter = wrapper
 		}
-	} else {
-		fishGenService = fish.NewFishGenerationService(conf.GeminiAPIKey, nil, dataManager)
 	}
+	fishGenService := fish.NewFishGenerationService(genServiceOpts, dataManager)
 
 	// Start the fish generation service
 	if *testMode || conf.TestMode {
@@ -119,6 +307,13 @@ func main() {
 		go fishGenService.Run(ctx)
 	}
 
+	// Parse the languages fish are translated into and served in
+	translationLocales, err := data.ParseLocales(conf.TranslationLocales)
+	if err != nil {
+		log.Printf("Invalid TRANSLATION_LOCALES, using %s: %v", data.DefaultTranslationLocales, err)
+		translationLocales, _ = data.ParseLocales(data.DefaultTranslationLocales)
+	}
+
 	// Initialize translation service if storage is available
 	if storageAdapter != nil {
 		// Load translation settings from config
@@ -126,6 +321,8 @@ func main() {
 			Enabled:  conf.EnableTranslation,
 			Interval: time.Duration(conf.TranslationInterval) * time.Minute,
 			ApiKey:   conf.GeminiAPIKey,
+			Locales:  translationLocales,
+			LLM:      llmTasks[data.LLMTaskTranslation],
 		}
 
 		if translationSettings.Enabled {
@@ -154,6 +351,11 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 		Storage:      storageAdapter,
 		DataManager:  dataManager,
+		Languages:    data.LocaleCodes(translationLocales),
+		AdminKeys:    conf.AdminAPIKeys,
+		LLMUsage:     llmUsage,
+		Experiments:  experiments,
+		APIQuotas:    apiQuotas,
 	})
 
 	// Start the API server in a goroutine
@@ -181,19 +383,86 @@ func main() {
 	// Stop the fish service
 	fishService.Stop(ctx)
 
+	// Close the storage backend
+	if mongoStorage != nil {
+		if err := mongoStorage.Close(shutdownCtx); err != nil {
+			log.Printf("Error closing MongoDB: %v", err)
+		}
+	}
+	if sqliteStorage != nil {
+		if err := sqliteStorage.Close(shutdownCtx); err != nil {
+			log.Printf("Error closing SQLite database: %v", err)
+		}
+	}
+	if memoryStorage != nil {
+		if err := memoryStorage.Close(shutdownCtx); err != nil {
+			log.Printf("Error saving in-memory storage: %v", err)
+		}
+	}
+
 	log.Println("Shutdown complete")
 }
 
+// runMigrateCommand lists, applies or previews MongoDB schema migrations
+func runMigrateCommand(conf *config.Config, command string) error {
+	if conf.MongoURI == "" {
+		return fmt.Errorf("MONGO_URI must be set to manage MongoDB migrations")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	mongoStorage, err := storage.ConnectMongoDB(conf.GetMongoURI(), conf.GetMongoDB())
+	if err != nil {
+		return err
+	}
+	defer mongoStorage.Close(ctx)
+
+	switch command {
+	case "list":
+		statuses, err := mongoStorage.MigrationStatuses(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d  %-32s %-30s %s\n", status.Version, status.Name, state, status.Description)
+		}
+		return nil
+
+	case "apply", "dry-run":
+		dryRun := command == "dry-run"
+		report, err := mongoStorage.ApplyMigrations(ctx, dryRun)
+		for _, line := range report {
+			fmt.Println(line)
+		}
+		if err != nil {
+			return err
+		}
+		if len(report) == 0 {
+			fmt.Println("No pending migrations")
+		} else if dryRun {
+			fmt.Printf("%d pending migrations (dry run, nothing applied)\n", len(report))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (use list, apply or dry-run)", command)
+	}
+}
+
+// printUsage describes the flags and environment variables, for -help and unknown flags
 func printUsage() {
 	fmt.Println("Fish Generator - A tool for generating random fish")
 	fmt.Println("\nUsage:")
-	fmt.Println("  fish-generate [command] [options]")
-	fmt.Println("\nCommands:")
-	fmt.Println("  generate     Start the fish generation service")
-	fmt.Println("  test         Run in test mode (faster fish generation)")
-	fmt.Println("  config       Show current configuration")
+	fmt.Println("  fish-generate [options]")
 	fmt.Println("\nOptions:")
 	fmt.Println("  -help        Show this help message")
+	fmt.Println("  -test        Run in test mode with shorter collection intervals")
+	fmt.Println("  -migrate     Manage MongoDB schema migrations and exit: list, apply or dry-run")
 	fmt.Println("\nEnvironment Variables:")
 	fmt.Println("  GEMINI_API_KEY        API key for Gemini (required for AI generation)")
 	fmt.Println("  USE_AI                Set to 'true' to enable AI-based generation")
@@ -201,14 +470,49 @@ func printUsage() {
 	fmt.Println("  OPENWEATHER_API_KEY   API key for OpenWeather data")
 	fmt.Println("  NEWSAPI_KEY           API key for News API")
 	fmt.Println("  METALPRICE_API_KEY    API key for Metal Price API")
+	fmt.Println("  EIA_API_KEY           API key for EIA oil prices")
 	fmt.Println("  MONGO_URI             MongoDB connection URI")
 	fmt.Println("  MONGO_DB              MongoDB database name")
 	fmt.Println("  MONGO_USER            MongoDB username")
 	fmt.Println("  MONGO_PASSWORD        MongoDB password")
-	fmt.Println("  WEATHER_INTERVAL      Weather collection interval in hours (default: 3)")
-	fmt.Println("  PRICE_INTERVAL        Price collection interval in hours (default: 12)")
-	fmt.Println("  NEWS_INTERVAL         News collection interval in hours (default: 0.5)")
+	fmt.Println("  STORAGE_BACKEND       Storage backend: mongodb, sqlite or memory (default: mongodb if MONGO_URI is set)")
+	fmt.Println("  SQLITE_PATH           SQLite database file (default: data/fish_generator.db)")
+	fmt.Println("  MEMORY_SNAPSHOT_PATH  JSON snapshot file for in-memory storage when MONGO_URI is unset")
+	fmt.Println("  COLLECTORS            Comma-separated data collectors to run (default: weather,bitcoin,gold,astronomy,news; also: oil)")
+	fmt.Println("  COLLECTOR_<NAME>_INTERVAL  Collection interval in hours of one collector (default: the variables below, or hourly)")
+	fmt.Println("  WEATHER_INTERVAL      Default interval in hours of the weather collector (default: 3)")
+	fmt.Println("  PRICE_INTERVAL        Default interval in hours of the bitcoin, gold and oil collectors (default: 12)")
+	fmt.Println("  NEWS_INTERVAL         Default interval in hours of the news collector (default: 0.5)")
+	fmt.Println("  COLLECTOR_<NAME>_API_KEY   API key of one collector, overriding the API key variables above")
+	fmt.Println("  COLLECTOR_<NAME>_BASE_URL  Scheme and host replacing those of one collector's API")
+	fmt.Println("  COLLECTOR_MAX_ATTEMPTS  Attempts per collection before it fails (default: 3)")
+	fmt.Println("  COLLECTOR_BREAKER_THRESHOLD  Failed collections in a row that open a collector's circuit breaker (default: 5)")
+	fmt.Println("  COLLECTOR_BREAKER_TIMEOUT  Minutes an open circuit breaker waits before a trial collection (default: 10)")
+	fmt.Println("  COLLECTOR_<NAME>_DAILY_QUOTA  API calls per UTC day of one collector (default: weather 1000, news 100; 0 for no limit)")
+	fmt.Println("  COLLECTOR_<NAME>_MONTHLY_QUOTA  API calls per UTC month of one collector (default: gold 100; 0 for no limit)")
+	fmt.Println("  COLLECTOR_<NAME>_QUOTA_BURST  API calls one collector may make back to back (default: weather 20, others 1)")
+	fmt.Println("  COLLECTOR_QUOTA_MARGIN  Share of every API quota left unused (default: 0.1)")
+	fmt.Println("  HTTP_FIXTURES_MODE    'record' saves the collectors' API responses, 'replay' serves them back offline")
+	fmt.Println("  HTTP_FIXTURES_DIR     Directory of recorded API responses (default: testdata/http)")
 	fmt.Println("  GENERATION_COOLDOWN   Minutes between fish generations (default: 15)")
+	fmt.Println("  LLM_CACHE             Set to '0' to disable the LLM response cache")
+	fmt.Println("  LLM_CACHE_TTL_HOURS   Hours a cached LLM response is reused (default: 168)")
+	fmt.Println("  LLM_CACHE_MAX_ENTRIES Maximum number of cached LLM responses (default: 5000)")
+	fmt.Println("  LLM_DAILY_TOKEN_BUDGET Prompt plus response tokens allowed per UTC day (default: 0, unlimited)")
+	fmt.Println("  LLM_PROMPT_PRICE_PER_MTOK   USD per million prompt tokens, for cost reports")
+	fmt.Println("  LLM_RESPONSE_PRICE_PER_MTOK USD per million response tokens, for cost reports")
+	fmt.Println("  ADMIN_API_KEYS        Comma-separated name:key pairs allowed to call /api/admin endpoints")
+	fmt.Println("  PROMPT_TEMPLATE_DIR   Directory of prompt templates overriding the built-in ones, reloaded when edited")
+	fmt.Println("  EXPERIMENTS_FILE      JSON file of prompt experiments splitting fish generation across prompt variants")
+	fmt.Println("  SPECIES_GUARD         Set to 0 to save fish without checking for duplicate species (default: 1)")
+	fmt.Println("  SPECIES_RENAME_ATTEMPTS  New names requested for a duplicate fish before it becomes a variant (default: 2)")
+	fmt.Println("  SPECIES_NAME_SIMILARITY  Name similarity from 0 to 1 treated as a duplicate (default: 0.85)")
+	fmt.Println("  SPECIES_NAME_TOKEN_OVERLAP  Name word overlap from 0 to 1 treated as a duplicate (default: 0.75)")
+	fmt.Println("  SPECIES_DESCRIPTION_SIMILARITY  Description word overlap from 0 to 1 treated as a variant (default: 0.6)")
+	fmt.Println("  MODERATION            Set to 0 to publish generated fish without content moderation (default: 1)")
+	fmt.Println("  MODERATION_RULES_FILE JSON file of blocklist terms and patterns added to the built-in moderation rules")
+	fmt.Println("  MODERATION_SELF_CHECK Set to 1 to have the LLM review fish the moderation rules let through (default: 0)")
+	fmt.Println("  REVIEW_GENERATED_FISH Set to 1 to save generated fish as drafts until an admin approves them (default: 0)")
 }
 
 // Helper function to mask API keys for display
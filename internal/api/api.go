@@ -22,6 +22,11 @@ type Server struct {
 	router      *mux.Router
 	storage     storage.StorageAdapter
 	dataManager *data.DataManager
+	languages   []string
+	adminKeys   map[string]string
+	llmUsage    *data.LLMUsageTracker
+	experiments *data.PromptExperiments
+	apiQuotas   *data.APIQuotaManager
 }
 
 // Config holds the API server configuration
@@ -32,6 +37,11 @@ type Config struct {
 	IdleTimeout  time.Duration
 	Storage      storage.StorageAdapter
 	DataManager  *data.DataManager
+	Languages    []string                // Translation languages served besides English; defaults to Vietnamese
+	AdminKeys    map[string]string       // Admin API keys mapped to admin names; admin endpoints are disabled without any
+	LLMUsage     *data.LLMUsageTracker   // Token accounting reported by the admin endpoints
+	Experiments  *data.PromptExperiments // Prompt experiments whose catches are counted and reported; optional
+	APIQuotas    *data.APIQuotaManager   // Collector API quotas reported by the admin endpoints; optional
 }
 
 // DefaultConfig returns the default server configuration
@@ -41,6 +51,7 @@ func DefaultConfig() Config {
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		Languages:    []string{"vi"},
 	}
 }
 
@@ -48,6 +59,11 @@ func DefaultConfig() Config {
 func NewServer(cfg Config) *Server {
 	router := mux.NewRouter()
 
+	languages := cfg.Languages
+	if len(languages) == 0 {
+		languages = []string{"vi"}
+	}
+
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
@@ -61,24 +77,38 @@ func NewServer(cfg Config) *Server {
 		router:      router,
 		storage:     cfg.Storage,
 		dataManager: cfg.DataManager,
+		languages:   languages,
+		adminKeys:   cfg.AdminKeys,
+		llmUsage:    cfg.LLMUsage,
+		experiments: cfg.Experiments,
+		apiQuotas:   cfg.APIQuotas,
 	}
 }
 
 // Start initializes and starts the API server
 func (s *Server) Start() error {
 	// Initialize the fishing service
-	fishingService := service.NewFishingService(s.storage, s.dataManager)
+	fishingService := service.NewFishingService(s.storage, s.dataManager, s.experiments)
 
 	// Initialize the fishing handler
-	fishingHandler := handlers.NewFishingHandler(fishingService)
+	fishingHandler := handlers.NewFishingHandler(fishingService, s.languages)
+
+	// Initialize the species catalog
+	catalogHandler := handlers.NewCatalogHandler(service.NewCatalogService(s.storage), s.languages)
 
 	// Set up API routes
 	apiRouter := s.router.PathPrefix("/api").Subrouter()
 
-	// Health check endpoint
+	// Health check endpoint, with the health state of each data collector
 	s.router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		collectors := make(map[string]string)
+		if s.dataManager != nil {
+			for _, health := range s.dataManager.CollectorHealth() {
+				collectors[health.Name] = health.State
+			}
+		}
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "collectors": collectors})
 	})
 
 	// Apply middleware to all routes
@@ -100,10 +130,104 @@ func (s *Server) Start() error {
 		middleware.CORS(),
 	)
 
+	speciesListHandler := middleware.ApplyMiddleware(
+		catalogHandler.ListSpecies,
+		middleware.Logging(),
+		middleware.CORS(),
+	)
+
+	speciesHandler := middleware.ApplyMiddleware(
+		catalogHandler.GetSpecies,
+		middleware.Logging(),
+		middleware.CORS(),
+	)
+
 	// Register routes
 	apiRouter.HandleFunc("/fish", fishCatchHandler).Methods(http.MethodGet, http.MethodOptions)
 	apiRouter.HandleFunc("/regions", regionsHandler).Methods(http.MethodGet, http.MethodOptions)
 	apiRouter.HandleFunc("/conditions", conditionsHandler).Methods(http.MethodGet, http.MethodOptions)
+	apiRouter.HandleFunc("/species", speciesListHandler).Methods(http.MethodGet, http.MethodOptions)
+	apiRouter.HandleFunc("/species/{id}", speciesHandler).Methods(http.MethodGet, http.MethodOptions)
+
+	// Admin endpoints require one of the configured admin API keys
+	if len(s.adminKeys) > 0 {
+		adminHandler := handlers.NewAdminHandler(service.NewAdminService(s.storage, s.llmUsage, s.experiments, s.dataManager, s.apiQuotas))
+
+		llmUsageHandler := middleware.ApplyMiddleware(
+			adminHandler.GetLLMUsage,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		experimentsHandler := middleware.ApplyMiddleware(
+			adminHandler.GetExperiments,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		collectorsHandler := middleware.ApplyMiddleware(
+			adminHandler.GetCollectors,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		fishListHandler := middleware.ApplyMiddleware(
+			adminHandler.ListFish,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		approveHandler := middleware.ApplyMiddleware(
+			adminHandler.ApproveFish,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		rejectHandler := middleware.ApplyMiddleware(
+			adminHandler.RejectFish,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		retireHandler := middleware.ApplyMiddleware(
+			adminHandler.RetireFish,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		editHandler := middleware.ApplyMiddleware(
+			adminHandler.EditFish,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		auditHandler := middleware.ApplyMiddleware(
+			adminHandler.GetFishAudit,
+			middleware.AdminAuth(s.adminKeys),
+			middleware.Logging(),
+			middleware.CORS(),
+		)
+
+		apiRouter.HandleFunc("/admin/llm-usage", llmUsageHandler).Methods(http.MethodGet, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/experiments", experimentsHandler).Methods(http.MethodGet, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/collectors", collectorsHandler).Methods(http.MethodGet, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/fish", fishListHandler).Methods(http.MethodGet, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/fish/{id}/approve", approveHandler).Methods(http.MethodPost, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/fish/{id}/reject", rejectHandler).Methods(http.MethodPost, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/fish/{id}/retire", retireHandler).Methods(http.MethodPost, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/fish/{id}/edit", editHandler).Methods(http.MethodPost, http.MethodOptions)
+		apiRouter.HandleFunc("/admin/audit", auditHandler).Methods(http.MethodGet, http.MethodOptions)
+	} else {
+		log.Println("Admin endpoints are disabled; set ADMIN_API_KEYS to enable them")
+	}
 
 	log.Printf("API server starting on port %s", s.server.Addr)
 	return s.server.ListenAndServe()
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"fish-generate/internal/api/middleware"
+	apiService "fish-generate/internal/api/service"
+	"fish-generate/internal/data"
+	"fish-generate/internal/storage"
+)
+
+// maxUsageReportDays limits the range of a single LLM usage report
+const maxUsageReportDays = 366
+
+// Default and maximum number of audit entries returned at once
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// reviewStatuses are the statuses listed by ListFish when no status is requested
+var reviewStatuses = []string{data.FishStatusDraft, data.FishStatusQuarantined}
+
+// fishActionRequest is the optional body of the approve, reject and retire endpoints
+type fishActionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// fishEditRequest is the body of the edit endpoint
+type fishEditRequest struct {
+	apiService.FishEdit
+	Reason string `json:"reason"`
+}
+
+// AdminHandler handles authenticated administrative API requests
+type AdminHandler struct {
+	adminService *apiService.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *apiService.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// GetLLMUsage returns daily LLM token and cost totals.
+// The optional from and to parameters are inclusive UTC dates (YYYY-MM-DD); the default is the last 7 days.
+func (h *AdminHandler) GetLLMUsage(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year, month, day := time.Now().UTC().Date()
+	to := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -6)
+
+	var err error
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "to must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("from") == "" {
+			from = to.AddDate(0, 0, -6)
+		}
+	}
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "from must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+	}
+	if to.Before(from) {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) >= maxUsageReportDays*24*time.Hour {
+		http.Error(w, fmt.Sprintf("the range must not exceed %d days", maxUsageReportDays), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.adminService.LLMUsage(r.Context(), from, to)
+	if err != nil {
+		http.Error(w, "Failed to get LLM usage: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the report as JSON
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetExperiments returns per-variant metrics of the prompt experiments.
+// The optional experiment parameter limits the report to one experiment.
+func (h *AdminHandler) GetExperiments(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reports, err := h.adminService.Experiments(r.Context(), r.URL.Query().Get("experiment"))
+	if err != nil {
+		if errors.Is(err, apiService.ErrExperimentNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get experiments: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the reports as JSON
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"experiments": reports}); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetCollectors returns the health of every data collector, with its recent failures and
+// when an open circuit breaker lets collections through again, and the usage of the API quotas
+func (h *AdminHandler) GetCollectors(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Return the collectors as JSON
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"collectors": h.adminService.CollectorHealth(),
+		"quotas":     h.adminService.APIQuotas(r.Context()),
+	}); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListFish returns one page of fish awaiting review. It accepts the catalog query parameters
+// plus status, a comma-separated list of publish statuses or "all", which defaults to
+// draft and quarantined fish.
+func (h *AdminHandler) ListFish(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query, err := parseFishQuery(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query.Statuses = reviewStatuses
+	if value := params.Get("status"); value == "all" {
+		query.Statuses = []string{data.FishStatusDraft, data.FishStatusQuarantined, data.FishStatusPublished, data.FishStatusRetired}
+	} else if value != "" {
+		query.Statuses = nil
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if !data.ValidFishStatus(status) {
+				http.Error(w, fmt.Sprintf("unknown status %q", status), http.StatusBadRequest)
+				return
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	page, err := h.adminService.ListFish(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidFishQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to list fish: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the page as JSON
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ApproveFish publishes a draft or quarantined fish
+func (h *AdminHandler) ApproveFish(w http.ResponseWriter, r *http.Request) {
+	h.handleFishAction(w, r, "approve", h.adminService.ApproveFish)
+}
+
+// RejectFish retires a draft or quarantined fish without publishing it
+func (h *AdminHandler) RejectFish(w http.ResponseWriter, r *http.Request) {
+	h.handleFishAction(w, r, "reject", h.adminService.RejectFish)
+}
+
+// RetireFish withdraws a published fish
+func (h *AdminHandler) RetireFish(w http.ResponseWriter, r *http.Request) {
+	h.handleFishAction(w, r, "retire", h.adminService.RetireFish)
+}
+
+// handleFishAction applies a status change to the fish in the path and returns the updated fish.
+// The body may carry a reason, which is recorded in the audit trail.
+func (h *AdminHandler) handleFishAction(w http.ResponseWriter, r *http.Request, action string,
+	apply func(ctx context.Context, id, admin, reason string) (*data.FishRecord, error)) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow POST requests
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req fishActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	admin := middleware.AdminName(r.Context())
+	fish, err := apply(r.Context(), id, admin, req.Reason)
+	if err != nil {
+		writeFishChangeError(w, action, err)
+		return
+	}
+	log.Printf("Admin %s: %s fish %s (%s), now %s", admin, action, id, fish.Name, fish.Status)
+
+	// Return the updated fish as JSON
+	if err := json.NewEncoder(w).Encode(fish); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// EditFish changes the text fields of a fish. Fields left out of the body are not changed.
+func (h *AdminHandler) EditFish(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow POST requests
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req fishEditRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	admin := middleware.AdminName(r.Context())
+	fish, err := h.adminService.EditFish(r.Context(), id, admin, req.Reason, req.FishEdit)
+	if err != nil {
+		writeFishChangeError(w, "edit", err)
+		return
+	}
+	log.Printf("Fish %s (%s) edited by %s", id, fish.Name, admin)
+
+	// Return the updated fish as JSON
+	if err := json.NewEncoder(w).Encode(fish); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeFishChangeError maps the errors of the fish review actions to HTTP statuses
+func writeFishChangeError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, storage.ErrFishNotFound):
+		http.Error(w, "Fish not found", http.StatusNotFound)
+	case errors.Is(err, apiService.ErrInvalidStatusChange), errors.Is(err, storage.ErrFishStatusChanged):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, apiService.ErrInvalidFishEdit):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to "+action+" fish: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetFishAudit returns the admin actions taken on fish, newest first. The optional fish_id
+// parameter restricts the trail to one fish and limit defaults to 50.
+func (h *AdminHandler) GetFishAudit(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultAuditLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if n > maxAuditLimit {
+			n = maxAuditLimit
+		}
+		limit = n
+	}
+
+	entries, err := h.adminService.FishAudit(r.Context(), r.URL.Query().Get("fish_id"), limit)
+	if err != nil {
+		http.Error(w, "Failed to get audit trail: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the audit entries as JSON
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"entries": entries}); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
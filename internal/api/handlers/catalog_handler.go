@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiService "fish-generate/internal/api/service"
+	"fish-generate/internal/storage"
+)
+
+// CatalogHandler handles API requests for browsing fish species
+type CatalogHandler struct {
+	catalogService *apiService.CatalogService
+	languages      []string // Languages fish can be translated into
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogService *apiService.CatalogService, languages []string) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+		languages:      languages,
+	}
+}
+
+// ListSpecies returns a filtered, paginated page of fish species
+func (h *CatalogHandler) ListSpecies(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query, err := parseFishQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	language := negotiateLanguage(r, h.languages)
+	setLanguageHeaders(w, language)
+
+	page, err := h.catalogService.ListSpecies(r.Context(), query, language)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidFishQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to list species: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the page as JSON
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSpecies returns the full record of a single fish species
+func (h *CatalogHandler) GetSpecies(w http.ResponseWriter, r *http.Request) {
+	// Set content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Only allow GET requests
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	language := negotiateLanguage(r, h.languages)
+	setLanguageHeaders(w, language)
+
+	id := mux.Vars(r)["id"]
+	species, err := h.catalogService.GetSpecies(r.Context(), id, language)
+	if err != nil {
+		if errors.Is(err, storage.ErrFishNotFound) {
+			http.Error(w, "Species not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get species: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return the species as JSON
+	if err := json.NewEncoder(w).Encode(species); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseFishQuery builds a catalog query from request parameters
+func parseFishQuery(params url.Values) (storage.FishQuery, error) {
+	query := storage.FishQuery{
+		RegionID:        params.Get("region_id"),
+		DataSource:      params.Get("source"),
+		FavoriteWeather: params.Get("favorite_weather"),
+		SortBy:          storage.FishSortField(params.Get("sort")),
+		Descending:      true, // Newest, most valuable, largest or rarest first by default
+		Cursor:          params.Get("cursor"),
+	}
+
+	// Rarities may be repeated or comma-separated: ?rarity=Rare&rarity=Epic or ?rarity=Rare,Epic
+	for _, value := range params["rarity"] {
+		for _, rarity := range strings.Split(value, ",") {
+			if rarity = strings.TrimSpace(rarity); rarity != "" {
+				query.Rarities = append(query.Rarities, rarity)
+			}
+		}
+	}
+
+	switch strings.ToLower(params.Get("order")) {
+	case "", "desc":
+	case "asc":
+		query.Descending = false
+	default:
+		return query, fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	if ai := params.Get("ai"); ai != "" {
+		isAI, err := strconv.ParseBool(ai)
+		if err != nil {
+			return query, fmt.Errorf("ai must be true or false")
+		}
+		query.IsAIGenerated = &isAI
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return query, fmt.Errorf("limit must be a positive integer")
+		}
+		query.Limit = n
+	}
+
+	var err error
+	if query.GeneratedAfter, err = parseTimeParam(params, "generated_after"); err != nil {
+		return query, err
+	}
+	if query.GeneratedBefore, err = parseTimeParam(params, "generated_before"); err != nil {
+		return query, err
+	}
+
+	for name, target := range map[string]*float64{
+		"min_length": &query.MinLength,
+		"max_length": &query.MaxLength,
+		"min_weight": &query.MinWeight,
+		"max_weight": &query.MaxWeight,
+	} {
+		if value := params.Get(name); value != "" {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || n < 0 {
+				return query, fmt.Errorf("%s must be a non-negative number", name)
+			}
+			*target = n
+		}
+	}
+
+	return query, nil
+}
+
+// parseTimeParam parses an optional RFC 3339 time parameter
+func parseTimeParam(params url.Values, name string) (time.Time, error) {
+	value := params.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 time", name)
+	}
+	return t, nil
+}
@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	apiService "fish-generate/internal/api/service"
 )
@@ -12,12 +12,14 @@ import (
 // FishingHandler handles API requests related to fishing
 type FishingHandler struct {
 	fishingService *apiService.FishingService
+	languages      []string // Languages fish can be translated into
 }
 
 // NewFishingHandler creates a new fishing handler
-func NewFishingHandler(fishingService *apiService.FishingService) *FishingHandler {
+func NewFishingHandler(fishingService *apiService.FishingService, languages []string) *FishingHandler {
 	return &FishingHandler{
 		fishingService: fishingService,
+		languages:      languages,
 	}
 }
 
@@ -34,9 +36,15 @@ func (h *FishingHandler) CatchFish(w http.ResponseWriter, r *http.Request) {
 
 	// Parse query parameters
 	params := parseParams(r)
+	params.Language = negotiateLanguage(r, h.languages)
+	setLanguageHeaders(w, params.Language)
 
 	// Call the service to attempt a catch
 	result, err := h.fishingService.CatchFish(r.Context(), params)
+	if errors.Is(err, apiService.ErrUnknownRegion) {
+		http.Error(w, "Unknown region_id", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to process fishing request: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -70,7 +78,8 @@ func (h *FishingHandler) GetRegions(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetCurrentConditions returns current weather and conditions for a location
+// GetCurrentConditions returns the current weather and fishing conditions of a region, or of
+// the region matching a location
 func (h *FishingHandler) GetCurrentConditions(w http.ResponseWriter, r *http.Request) {
 	// Set content type
 	w.Header().Set("Content-Type", "application/json")
@@ -90,8 +99,16 @@ func (h *FishingHandler) GetCurrentConditions(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Get current conditions (simplified - would connect to weather API in production)
-	conditions := getCurrentConditions(location, regionID)
+	// Compute the conditions from the region's collected weather
+	conditions, err := h.fishingService.CurrentConditions(r.Context(), location, regionID)
+	if errors.Is(err, apiService.ErrUnknownRegion) {
+		http.Error(w, "Unknown region_id", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get conditions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Return the conditions as JSON
 	if err := json.NewEncoder(w).Encode(conditions); err != nil {
@@ -116,19 +133,6 @@ func parseParams(r *http.Request) apiService.FishingParams {
 		}
 	}
 
-	// Extract weather condition and temperature
-	weatherCondition := query.Get("weather")
-	if weatherCondition == "" {
-		weatherCondition = "clear" // Default
-	}
-
-	temperature := 20.0 // Default to 20°C
-	if tempStr := query.Get("temp"); tempStr != "" {
-		if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-			temperature = temp
-		}
-	}
-
 	// Extract user's fishing skill
 	fishingSkill := 50 // Default to 50 (average)
 	if skillStr := query.Get("skill"); skillStr != "" {
@@ -142,36 +146,14 @@ func parseParams(r *http.Request) apiService.FishingParams {
 	// Extract bait type
 	baitType := query.Get("bait")
 
-	// Determine time of day
-	timeOfDay := getTimeOfDay()
-	if todStr := query.Get("time_of_day"); todStr != "" {
-		timeOfDay = todStr
-	}
-
+	// Weather and time of day are not taken from the request: conditions are computed from
+	// the region's collected weather so players cannot choose them
 	return apiService.FishingParams{
-		RegionID:         regionID,
-		Location:         location,
-		Coordinates:      coordinates,
-		WeatherCondition: weatherCondition,
-		Temperature:      temperature,
-		FishingSkill:     fishingSkill,
-		BaitType:         baitType,
-		TimeOfDay:        timeOfDay,
-	}
-}
-
-// getTimeOfDay returns the current time of day
-func getTimeOfDay() string {
-	hour := time.Now().Hour()
-
-	if hour >= 5 && hour < 12 {
-		return "morning"
-	} else if hour >= 12 && hour < 17 {
-		return "afternoon"
-	} else if hour >= 17 && hour < 21 {
-		return "evening"
-	} else {
-		return "night"
+		RegionID:     regionID,
+		Location:     location,
+		Coordinates:  coordinates,
+		FishingSkill: fishingSkill,
+		BaitType:     baitType,
 	}
 }
 
@@ -224,22 +206,3 @@ func getRegionsWithDetails() interface{} {
 		},
 	}
 }
-
-// getCurrentConditions returns current fishing conditions for a location
-func getCurrentConditions(location, regionID string) interface{} {
-	// In a real implementation, this would fetch actual weather data
-	// For now, we'll return mock data
-	return map[string]interface{}{
-		"location":          location,
-		"region_id":         regionID,
-		"weather":           "partly cloudy",
-		"temperature":       22.5,
-		"wind_speed":        10.2,
-		"humidity":          65,
-		"fishing_quality":   "Good",
-		"suggested_baits":   []string{"Worms", "Small Fish", "Shrimp"},
-		"active_fish_types": []string{"Bass", "Trout", "Catfish"},
-		"time_of_day":       getTimeOfDay(),
-		"timestamp":         time.Now(),
-	}
-}
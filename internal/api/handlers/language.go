@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiService "fish-generate/internal/api/service"
+)
+
+// negotiateLanguage picks the response language from the lang query parameter or the
+// Accept-Language header, falling back to English when nothing requested is supported
+func negotiateLanguage(r *http.Request, supported []string) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if match := matchLanguage(lang, supported); match != "" {
+			return match
+		}
+		return apiService.DefaultLanguage
+	}
+
+	for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+		if tag == "*" {
+			break
+		}
+		if match := matchLanguage(tag, supported); match != "" {
+			return match
+		}
+	}
+
+	return apiService.DefaultLanguage
+}
+
+// setLanguageHeaders tells clients and caches which language the response is in
+func setLanguageHeaders(w http.ResponseWriter, language string) {
+	w.Header().Set("Content-Language", language)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// matchLanguage returns the supported language matching a tag such as "vi" or "vi-VN"
+func matchLanguage(tag string, supported []string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary := strings.SplitN(tag, "-", 2)[0]
+
+	if tag == apiService.DefaultLanguage || primary == apiService.DefaultLanguage {
+		return apiService.DefaultLanguage
+	}
+	for _, language := range supported {
+		if strings.EqualFold(language, tag) {
+			return language
+		}
+	}
+	for _, language := range supported {
+		if strings.EqualFold(language, primary) {
+			return language
+		}
+	}
+	return ""
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header, most preferred first
+func parseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag    string
+		weight float64
+	}
+
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		if weight <= 0 {
+			continue // q=0 means "not acceptable"
+		}
+
+		tags = append(tags, weightedTag{tag: tag, weight: weight})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].weight > tags[j].weight
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -130,3 +133,51 @@ func Authentication() Middleware {
 		}
 	}
 }
+
+// adminContextKey is the request context key holding the authenticated admin's name
+type adminContextKey struct{}
+
+// AdminAuth only lets through requests that carry one of the admin API keys as
+// "Authorization: Bearer <key>". keys maps each key to the name of its admin.
+func AdminAuth(keys map[string]string) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized: admin API key required as a Bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			name := ""
+			for key, admin := range keys {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+					name = admin
+				}
+			}
+			if name == "" {
+				http.Error(w, "Forbidden: invalid admin API key", http.StatusForbidden)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), adminContextKey{}, name)))
+		}
+	}
+}
+
+// bearerToken returns the token of a "Bearer <token>" Authorization header.
+// The scheme is matched case-insensitively; any other scheme or an empty token is rejected.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// AdminName returns the name of the admin who made the request, or "" outside AdminAuth
+func AdminName(ctx context.Context) string {
+	name, _ := ctx.Value(adminContextKey{}).(string)
+	return name
+}
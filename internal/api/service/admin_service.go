@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fish-generate/internal/data"
+	"fish-generate/internal/storage"
+)
+
+// AdminService provides operational reports and fish review for administrators
+type AdminService struct {
+	storage     storage.StorageAdapter
+	usage       *data.LLMUsageTracker
+	experiments *data.PromptExperiments
+	dataManager *data.DataManager
+	apiQuotas   *data.APIQuotaManager
+}
+
+// ErrExperimentNotFound is returned when a report is requested for an experiment that is not configured
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// ErrInvalidStatusChange is returned when an admin action does not apply to the current status of a fish
+var ErrInvalidStatusChange = errors.New("invalid status change")
+
+// ErrInvalidFishEdit is returned when an edit would leave a fish without a required field
+var ErrInvalidFishEdit = errors.New("invalid fish edit")
+
+// FishEdit holds the text fields of a fish an admin can change; nil fields are left as they are
+type FishEdit struct {
+	Name            *string `json:"name"`
+	Description     *string `json:"description"`
+	Appearance      *string `json:"appearance"`
+	Color           *string `json:"color"`
+	Habitat         *string `json:"habitat"`
+	Diet            *string `json:"diet"`
+	Effect          *string `json:"effect"`
+	FavoriteWeather *string `json:"favorite_weather"`
+	ExistenceReason *string `json:"existence_reason"`
+}
+
+// LLMUsageDay sums the LLM usage of one UTC day, with a breakdown per task and model
+type LLMUsageDay struct {
+	Date           string               `json:"date"`
+	Calls          int                  `json:"calls"`
+	CachedCalls    int                  `json:"cached_calls"`
+	FailedCalls    int                  `json:"failed_calls"`
+	PromptTokens   int                  `json:"prompt_tokens"`
+	ResponseTokens int                  `json:"response_tokens"`
+	TotalTokens    int                  `json:"total_tokens"`
+	CostUSD        float64              `json:"cost_usd"`
+	Breakdown      []data.LLMUsageTotal `json:"breakdown"`
+}
+
+// LLMUsageReport is the LLM usage over a range of days and today's budget status
+type LLMUsageReport struct {
+	From             string        `json:"from"`
+	To               string        `json:"to"`
+	DailyTokenBudget int           `json:"daily_token_budget"` // Zero means unlimited
+	TokensUsedToday  int           `json:"tokens_used_today"`
+	BudgetExhausted  bool          `json:"budget_exhausted"`
+	BudgetResetsAt   time.Time     `json:"budget_resets_at"`
+	Days             []LLMUsageDay `json:"days"`
+}
+
+// ExperimentVariantReport holds the metrics of one variant of a prompt experiment
+type ExperimentVariantReport struct {
+	Name                 string  `json:"name"`
+	Template             string  `json:"template"`
+	Weight               int     `json:"weight"` // Zero for variants that were removed from the definition
+	Generations          int     `json:"generations"`
+	ParseFailures        int     `json:"parse_failures"`
+	ParseFailureRate     float64 `json:"parse_failure_rate"` // Share of generations whose answer never passed validation
+	Fish                 int     `json:"fish"`               // Saved fish generated by the variant
+	AvgDescriptionLength float64 `json:"avg_description_length"`
+	UniqueNames          int     `json:"unique_names"`
+	NameUniqueness       float64 `json:"name_uniqueness"` // Unique names per saved fish, ignoring case
+	Catches              int     `json:"catches"`
+}
+
+// ExperimentReport holds the metrics of every variant of a prompt experiment
+type ExperimentReport struct {
+	Name     string                    `json:"name"`
+	Enabled  bool                      `json:"enabled"`
+	Active   bool                      `json:"active"` // Whether the experiment currently assigns variants
+	Variants []ExperimentVariantReport `json:"variants"`
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(storage storage.StorageAdapter, usage *data.LLMUsageTracker, experiments *data.PromptExperiments, dataManager *data.DataManager, apiQuotas *data.APIQuotaManager) *AdminService {
+	return &AdminService{
+		storage:     storage,
+		usage:       usage,
+		experiments: experiments,
+		dataManager: dataManager,
+		apiQuotas:   apiQuotas,
+	}
+}
+
+// CollectorHealth reports the health, failures and circuit breaker of every data collector
+func (s *AdminService) CollectorHealth() []data.CollectorHealth {
+	if s.dataManager == nil {
+		return []data.CollectorHealth{}
+	}
+	return s.dataManager.CollectorHealth()
+}
+
+// APIQuotas reports the calls each rate-limited collector has made in the current UTC day
+// and month, and when calls are held back
+func (s *AdminService) APIQuotas(ctx context.Context) []data.APIQuotaStatus {
+	return s.apiQuotas.Status(ctx)
+}
+
+// LLMUsage reports daily LLM usage for the UTC days from and to, inclusive
+func (s *AdminService) LLMUsage(ctx context.Context, from, to time.Time) (*LLMUsageReport, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	totals, err := s.storage.GetLLMUsageTotals(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LLMUsageReport{
+		From:             from.Format("2006-01-02"),
+		To:               to.Format("2006-01-02"),
+		DailyTokenBudget: s.usage.Settings().DailyTokenBudget,
+		TokensUsedToday:  s.usage.TokensUsedToday(ctx),
+		BudgetExhausted:  s.usage.BudgetExhausted(ctx),
+		BudgetResetsAt:   data.NextBudgetReset(time.Now()),
+		Days:             make([]LLMUsageDay, 0),
+	}
+
+	// Totals are sorted by date, so each day's rows are adjacent
+	for _, total := range totals {
+		if len(report.Days) == 0 || report.Days[len(report.Days)-1].Date != total.Date {
+			report.Days = append(report.Days, LLMUsageDay{Date: total.Date})
+		}
+		day := &report.Days[len(report.Days)-1]
+		day.Calls += total.Calls
+		day.CachedCalls += total.CachedCalls
+		day.FailedCalls += total.FailedCalls
+		day.PromptTokens += total.PromptTokens
+		day.ResponseTokens += total.ResponseTokens
+		day.TotalTokens += total.TotalTokens
+		day.CostUSD += total.CostUSD
+		day.Breakdown = append(day.Breakdown, total)
+	}
+
+	return report, nil
+}
+
+// Experiments reports per-variant metrics of every configured prompt experiment,
+// or only of the named one when name is not empty
+func (s *AdminService) Experiments(ctx context.Context, name string) ([]ExperimentReport, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	active, _ := s.experiments.Active()
+	reports := make([]ExperimentReport, 0)
+	for _, experiment := range s.experiments.Definitions() {
+		if name != "" && experiment.Name != name {
+			continue
+		}
+
+		report, err := s.experimentReport(ctx, experiment)
+		if err != nil {
+			return nil, err
+		}
+		report.Active = experiment.Name == active.Name
+		reports = append(reports, *report)
+	}
+
+	if name != "" && len(reports) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrExperimentNotFound, name)
+	}
+	return reports, nil
+}
+
+// experimentReport combines the stored counters of an experiment with metrics of its saved fish
+func (s *AdminService) experimentReport(ctx context.Context, experiment data.PromptExperiment) (*ExperimentReport, error) {
+	counts, err := s.storage.GetExperimentCounts(ctx, experiment.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExperimentReport{
+		Name:     experiment.Name,
+		Enabled:  experiment.Enabled,
+		Variants: make([]ExperimentVariantReport, 0, len(experiment.Variants)),
+	}
+
+	// Defined variants come first, followed by variants that only have recorded counters
+	for _, variant := range experiment.Variants {
+		template := variant.Template
+		if template == "" {
+			template = data.LLMTaskFishFromContext
+		}
+		report.Variants = append(report.Variants, ExperimentVariantReport{
+			Name:     variant.Name,
+			Template: template,
+			Weight:   variant.Weight,
+		})
+	}
+	for _, count := range counts {
+		if findVariantReport(report.Variants, count.Variant) == nil {
+			report.Variants = append(report.Variants, ExperimentVariantReport{Name: count.Variant})
+		}
+	}
+
+	for _, count := range counts {
+		variant := findVariantReport(report.Variants, count.Variant)
+		variant.Generations = count.Generations
+		variant.ParseFailures = count.ParseFailures
+		variant.Catches = count.Catches
+		if count.Generations > 0 {
+			variant.ParseFailureRate = float64(count.ParseFailures) / float64(count.Generations)
+		}
+	}
+
+	for i := range report.Variants {
+		if err := s.addFishMetrics(ctx, experiment.Name, &report.Variants[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// addFishMetrics pages through the saved fish of a variant to compute their description length and name uniqueness
+func (s *AdminService) addFishMetrics(ctx context.Context, experiment string, variant *ExperimentVariantReport) error {
+	names := make(map[string]bool)
+	descriptionLength := 0
+
+	// Count every fish the variant generated, whether or not it was published
+	query := storage.FishQuery{
+		Experiment: experiment,
+		Variant:    variant.Name,
+		Statuses:   []string{data.FishStatusDraft, data.FishStatusQuarantined, data.FishStatusPublished, data.FishStatusRetired},
+		Limit:      100,
+	}
+	for {
+		page, err := s.storage.QueryFish(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		for _, fish := range page.Fish {
+			variant.Fish++
+			descriptionLength += utf8.RuneCountInString(fish.Description)
+			names[strings.ToLower(strings.TrimSpace(fish.Name))] = true
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	variant.UniqueNames = len(names)
+	if variant.Fish > 0 {
+		variant.AvgDescriptionLength = float64(descriptionLength) / float64(variant.Fish)
+		variant.NameUniqueness = float64(variant.UniqueNames) / float64(variant.Fish)
+	}
+	return nil
+}
+
+// findVariantReport returns the report of the named variant, or nil
+func findVariantReport(variants []ExperimentVariantReport, name string) *ExperimentVariantReport {
+	for i := range variants {
+		if variants[i].Name == name {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// ListFish returns one page of fish in any publish status, for review
+func (s *AdminService) ListFish(ctx context.Context, query storage.FishQuery) (*storage.FishPage, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	return s.storage.QueryFish(ctx, query)
+}
+
+// ApproveFish publishes a draft or quarantined fish and clears its moderation flags
+func (s *AdminService) ApproveFish(ctx context.Context, id, admin, reason string) (*data.FishRecord, error) {
+	return s.changeStatus(ctx, id, admin, reason, data.FishAuditApprove, data.FishStatusPublished,
+		data.FishStatusDraft, data.FishStatusQuarantined)
+}
+
+// RejectFish retires a draft or quarantined fish without publishing it
+func (s *AdminService) RejectFish(ctx context.Context, id, admin, reason string) (*data.FishRecord, error) {
+	return s.changeStatus(ctx, id, admin, reason, data.FishAuditReject, data.FishStatusRetired,
+		data.FishStatusDraft, data.FishStatusQuarantined)
+}
+
+// RetireFish withdraws a published fish so it can no longer be caught or listed
+func (s *AdminService) RetireFish(ctx context.Context, id, admin, reason string) (*data.FishRecord, error) {
+	return s.changeStatus(ctx, id, admin, reason, data.FishAuditRetire, data.FishStatusRetired,
+		data.FishStatusPublished)
+}
+
+// changeStatus moves a fish in one of the from statuses to the to status and records the action
+func (s *AdminService) changeStatus(ctx context.Context, id, admin, reason, action, to string, from ...string) (*data.FishRecord, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	fish, err := s.storage.GetFishRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || fish.Status == status
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: cannot %s a %s fish", ErrInvalidStatusChange, action, fish.Status)
+	}
+
+	read := fish.Status
+	changes := []data.FishFieldChange{{Field: "status", Old: read, New: to}}
+	fish.Status = to
+	if action == data.FishAuditApprove && len(fish.ModerationFlags) > 0 {
+		changes = append(changes, data.FishFieldChange{Field: "moderation_flags", Old: describeModerationFlags(fish.ModerationFlags)})
+		fish.ModerationFlags = nil
+	}
+
+	// Only write if no other admin changed the status since it was read
+	if err := s.storage.UpdateFishData(ctx, fish, read); err != nil {
+		return nil, err
+	}
+	return fish, s.recordAudit(ctx, fish, admin, action, reason, changes)
+}
+
+// EditFish changes the text fields of a fish and records the old and new values
+func (s *AdminService) EditFish(ctx context.Context, id, admin, reason string, edit FishEdit) (*data.FishRecord, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	fish, err := s.storage.GetFishRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []data.FishFieldChange
+	for _, field := range []struct {
+		name     string
+		value    *string
+		required bool
+		current  *string
+	}{
+		{"name", edit.Name, true, &fish.Name},
+		{"description", edit.Description, true, &fish.Description},
+		{"appearance", edit.Appearance, false, &fish.Appearance},
+		{"color", edit.Color, false, &fish.Color},
+		{"habitat", edit.Habitat, false, &fish.Habitat},
+		{"diet", edit.Diet, false, &fish.Diet},
+		{"effect", edit.Effect, false, &fish.Effect},
+		{"favorite_weather", edit.FavoriteWeather, false, &fish.FavoriteWeather},
+		{"existence_reason", edit.ExistenceReason, false, &fish.ExistenceReason},
+	} {
+		if field.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*field.value)
+		if field.required && value == "" {
+			return nil, fmt.Errorf("%w: %s must not be empty", ErrInvalidFishEdit, field.name)
+		}
+		if value != *field.current {
+			changes = append(changes, data.FishFieldChange{Field: field.name, Old: *field.current, New: value})
+			*field.current = value
+		}
+	}
+	if len(changes) == 0 {
+		return fish, nil
+	}
+
+	if err := s.storage.UpdateFishData(ctx, fish, fish.Status); err != nil {
+		return nil, err
+	}
+	return fish, s.recordAudit(ctx, fish, admin, data.FishAuditEdit, reason, changes)
+}
+
+// FishAudit returns the audit trail of a fish, or of every fish when fishID is empty, newest first
+func (s *AdminService) FishAudit(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	return s.storage.GetFishAuditEntries(ctx, fishID, limit)
+}
+
+// recordAudit saves the audit entry of an action that has already been applied
+func (s *AdminService) recordAudit(ctx context.Context, fish *data.FishRecord, admin, action, reason string, changes []data.FishFieldChange) error {
+	entry := &data.FishAuditEntry{
+		FishID:   fish.ID,
+		FishName: fish.Name,
+		Admin:    admin,
+		Action:   action,
+		Changes:  changes,
+		Reason:   strings.TrimSpace(reason),
+		At:       time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if err := s.storage.SaveFishAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("fish %s was changed but its audit entry was not saved: %v", fish.ID, err)
+	}
+	return nil
+}
+
+// describeModerationFlags summarizes moderation flags as rule:term pairs
+func describeModerationFlags(flags []data.ModerationFlag) string {
+	parts := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		if flag.Term != "" {
+			parts = append(parts, flag.Rule+":"+flag.Term)
+		} else {
+			parts = append(parts, flag.Rule)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
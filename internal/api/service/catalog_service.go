@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fish-generate/internal/data"
+	"fish-generate/internal/storage"
+)
+
+// CatalogService provides read access to the catalog of generated fish species
+type CatalogService struct {
+	storage   storage.StorageAdapter
+	localizer *Localizer
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(storage storage.StorageAdapter) *CatalogService {
+	return &CatalogService{
+		storage:   storage,
+		localizer: NewLocalizer(storage),
+	}
+}
+
+// ListSpecies returns one page of species matching the query in the given language
+func (s *CatalogService) ListSpecies(ctx context.Context, query storage.FishQuery, language string) (*LocalizedFishPage, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	page, err := s.storage.QueryFish(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.localizer.LocalizePage(ctx, page, language), nil
+}
+
+// GetSpecies returns the full stored record of a single species in the given language
+func (s *CatalogService) GetSpecies(ctx context.Context, id, language string) (*LocalizedFish, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	record, err := s.storage.GetFishRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != data.FishStatusPublished {
+		// Drafts, quarantined and retired fish are only visible to admins
+		return nil, fmt.Errorf("%w: %s", storage.ErrFishNotFound, id)
+	}
+	return s.localizer.Localize(ctx, record, language), nil
+}
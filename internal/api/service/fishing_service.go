@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -13,48 +14,75 @@ import (
 	"fish-generate/internal/storage"
 )
 
+// ErrUnknownRegion is returned when a request names a region that does not exist
+var ErrUnknownRegion = errors.New("unknown region")
+
 // FishingService handles the fishing mechanics and retrieval of fish
 type FishingService struct {
 	storage     storage.StorageAdapter
 	dataManager *data.DataManager
+	localizer   *Localizer
+	experiments *data.PromptExperiments
 }
 
 // FishingParams contains parameters for a fishing request
 type FishingParams struct {
-	RegionID         string    // Optional region ID
-	Location         string    // Location name (city, ocean, etc.)
-	Coordinates      []float64 // [lat, lng]
-	WeatherCondition string    // Current weather condition
-	Temperature      float64   // Current temperature
-	FishingSkill     int       // User's fishing skill level (1-100)
-	BaitType         string    // Type of bait used
-	TimeOfDay        string    // "morning", "afternoon", "evening", "night"
+	RegionID     string    // Optional region ID
+	Location     string    // Location name (city, ocean, etc.)
+	Coordinates  []float64 // [lat, lng]
+	FishingSkill int       // User's fishing skill level (1-100)
+	BaitType     string    // Type of bait used
+	Language     string    // Language to return the fish in; defaults to English
 }
 
 // CatchResult represents the result of a fishing attempt
 type CatchResult struct {
-	Success      bool        `json:"success"`
-	Fish         *fish.Fish  `json:"fish,omitempty"`
-	Message      string      `json:"message"`
-	RarityFactor float64     `json:"rarity_factor"`
-	Conditions   *Conditions `json:"conditions"`
-	CatchTime    time.Time   `json:"catch_time"`
+	Success      bool           `json:"success"`
+	Fish         *LocalizedFish `json:"fish,omitempty"`
+	Message      string         `json:"message"`
+	RarityFactor float64        `json:"rarity_factor"`
+	Conditions   *Conditions    `json:"conditions"`
+	CatchTime    time.Time      `json:"catch_time"`
 }
 
-// Conditions represents the current fishing conditions
+// Conditions represents the current fishing conditions of a region, computed from its latest
+// collected weather and astronomy. Before any weather is collected for the region, Weather,
+// Wind and TemperatureRange are "unknown" and the other weather fields are zero; before any
+// astronomy is collected, the moon, sun and tide fields are empty.
 type Conditions struct {
-	Weather    string   `json:"weather"`
-	Region     string   `json:"region"`
-	RegionTags []string `json:"region_tags"`
-	TimeOfDay  string   `json:"time_of_day"`
-	Quality    int      `json:"quality"` // 1-10 rating of fishing conditions
+	RegionID         string     `json:"region_id"`
+	Region           string     `json:"region"`
+	RegionTags       []string   `json:"region_tags"`
+	Weather          string     `json:"weather"`           // Most common condition among the region's cities
+	Temperature      float64    `json:"temperature"`       // Mean temperature in °C
+	TemperatureRange string     `json:"temperature_range"` // "below", "within" or "above" the region's typical temperatures
+	WindSpeed        float64    `json:"wind_speed"`        // Strongest wind in km/h
+	Wind             string     `json:"wind"`              // "calm", "breezy" or "strong"
+	Humidity         int        `json:"humidity"`
+	IsExtreme        bool       `json:"is_extreme"`
+	WeatherUpdatedAt *time.Time `json:"weather_updated_at,omitempty"`
+	LocalTime        time.Time  `json:"local_time"`                  // In the region's time zone
+	TimeOfDay        string     `json:"time_of_day"`                 // "morning", "afternoon", "evening" or "night" in the region
+	MoonPhase        string     `json:"moon_phase,omitempty"`        // e.g. "waxing gibbous" or "full moon"
+	MoonIllumination float64    `json:"moon_illumination,omitempty"` // Illuminated fraction of the moon's disc, 0-1
+	FullMoon         bool       `json:"full_moon"`                   // Legendary fish are more likely under a full moon
+	Sunrise          *time.Time `json:"sunrise,omitempty"`           // In the region's time zone; absent when the sun does not rise or set
+	Sunset           *time.Time `json:"sunset,omitempty"`
+	Tide             string     `json:"tide,omitempty"`       // "rising", "high", "falling" or "low"
+	TideRange        string     `json:"tide_range,omitempty"` // "spring", "moderate" or "neap"
+	Quality          int        `json:"quality"`              // 1-10 rating of fishing conditions
+	FishingQuality   string     `json:"fishing_quality"`
+	ActiveSpecies    []string   `json:"active_species"` // Species of the region that favor the current weather
 }
 
-// NewFishingService creates a new fishing service
-func NewFishingService(storage storage.StorageAdapter, dataManager *data.DataManager) *FishingService {
+// NewFishingService creates a new fishing service. Catches of fish generated in a
+// prompt experiment are counted in experiments, which may be nil.
+func NewFishingService(storage storage.StorageAdapter, dataManager *data.DataManager, experiments *data.PromptExperiments) *FishingService {
 	return &FishingService{
 		storage:     storage,
 		dataManager: dataManager,
+		localizer:   NewLocalizer(storage),
+		experiments: experiments,
 	}
 }
 
@@ -72,10 +100,12 @@ func (s *FishingService) CatchFish(ctx context.Context, params FishingParams) (*
 		if err != nil {
 			return nil, fmt.Errorf("could not determine region: %v", err)
 		}
+	} else if _, ok := data.GetRegionByID(regionID); !ok {
+		return nil, ErrUnknownRegion
 	}
 
-	// Calculate fishing conditions and chances
-	conditions, rarityFactor := s.calculateFishingConditions(ctx, params, regionID)
+	// Calculate fishing conditions and chances from the region's collected weather
+	conditions, rarityFactor := s.calculateFishingConditions(ctx, regionID, time.Now())
 
 	// Determine if the fishing attempt is successful
 	successChance := s.calculateSuccessChance(params, conditions)
@@ -93,10 +123,10 @@ func (s *FishingService) CatchFish(ctx context.Context, params FishingParams) (*
 
 	// Determine which type of data source to use for fish selection
 	// based on conditions (weather, time, etc.)
-	dataSource := s.selectDataSource(params, conditions)
+	dataSource := s.selectDataSource(conditions)
 
 	// Get fish from the chosen data source matching the region
-	fish, err := s.getFishByConditions(ctx, regionID, dataSource, rarityFactor)
+	fish, err := s.getFishByConditions(ctx, regionID, dataSource, rarityFactor, conditions.FullMoon)
 	if err != nil {
 		log.Printf("Error getting fish: %v, trying any fish", err)
 		// Fallback to any fish if we couldn't find one matching specific conditions
@@ -105,10 +135,11 @@ func (s *FishingService) CatchFish(ctx context.Context, params FishingParams) (*
 			return nil, fmt.Errorf("failed to catch fish: %v", err)
 		}
 	}
+	s.experiments.RecordCatch(ctx, fish)
 
 	return &CatchResult{
 		Success:      true,
-		Fish:         fish,
+		Fish:         s.localizer.Localize(ctx, fish, params.Language),
 		Message:      getSuccessCatchMessage(fish),
 		RarityFactor: rarityFactor,
 		Conditions:   conditions,
@@ -116,6 +147,42 @@ func (s *FishingService) CatchFish(ctx context.Context, params FishingParams) (*
 	}, nil
 }
 
+// CurrentConditions returns the fishing conditions of a region, or of the region matching
+// the location, from the region's latest collected weather
+func (s *FishingService) CurrentConditions(ctx context.Context, location, regionID string) (*Conditions, error) {
+	if regionID == "" {
+		var err error
+		regionID, err = s.findRegionByLocation(location, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine region: %v", err)
+		}
+	} else if _, ok := data.GetRegionByID(regionID); !ok {
+		return nil, ErrUnknownRegion
+	}
+
+	conditions, _ := s.calculateFishingConditions(ctx, regionID, time.Now())
+	return conditions, nil
+}
+
+// RegionWeather returns the latest weather of a region, as kept by the data manager or,
+// without one, as stored; nil when none has been collected
+func (s *FishingService) RegionWeather(ctx context.Context, regionID string) *data.RegionalWeather {
+	if s.dataManager != nil {
+		if weather := s.dataManager.RegionWeather(regionID); weather != nil {
+			return weather
+		}
+	}
+	if s.storage == nil {
+		return nil
+	}
+	weather, err := s.storage.GetRegionWeather(ctx, regionID)
+	if err != nil {
+		log.Printf("Error getting weather of region %s: %v", regionID, err)
+		return nil
+	}
+	return weather
+}
+
 // findRegionByLocation determines the best region match for a given location
 func (s *FishingService) findRegionByLocation(location string, coordinates []float64) (string, error) {
 	// Get all regions
@@ -173,59 +240,147 @@ func (s *FishingService) findRegionByLocation(location string, coordinates []flo
 	return defaultRegion, nil
 }
 
-// calculateFishingConditions evaluates the current fishing conditions
-func (s *FishingService) calculateFishingConditions(ctx context.Context, params FishingParams, regionID string) (*Conditions, float64) {
-	// Get region info
-	regions := data.PredefinedRegions()
-	var regionName string
-	var regionTags []string
-
-	// Find region details
-	for _, r := range regions {
-		if r.ID == regionID {
-			regionName = r.Name
-			regionTags = r.Tags
-			break
-		}
+// calculateFishingConditions evaluates the fishing conditions of a region at a time from its
+// latest collected weather and astronomy
+func (s *FishingService) calculateFishingConditions(ctx context.Context, regionID string, now time.Time) (*Conditions, float64) {
+	region, _ := data.GetRegionByID(regionID)
+	localTime := now.In(region.Zone())
+	conditions := &Conditions{
+		RegionID:         regionID,
+		Region:           region.Name,
+		RegionTags:       region.Tags,
+		Weather:          "unknown",
+		TemperatureRange: "unknown",
+		Wind:             "unknown",
+		LocalTime:        localTime,
+		TimeOfDay:        getTimeOfDay(localTime),
+		ActiveSpecies:    []string{},
 	}
 
 	// Calculate quality of fishing conditions (1-10)
 	quality := 5 // Default average
 
-	// Adjust based on weather
-	weatherFactor := 0
-	if isGoodWeatherForFishing(params.WeatherCondition) {
-		weatherFactor = 2
-	} else if isBadWeatherForFishing(params.WeatherCondition) {
-		weatherFactor = -2
+	weather := s.RegionWeather(ctx, regionID)
+	if weather != nil {
+		updatedAt := weather.UpdatedAt
+		conditions.Weather = weather.Condition
+		conditions.Temperature = weather.TempC
+		conditions.WindSpeed = weather.WindKph
+		conditions.Humidity = weather.Humidity
+		conditions.IsExtreme = weather.IsExtreme
+		conditions.WeatherUpdatedAt = &updatedAt
+
+		// Adjust based on weather
+		if isGoodWeatherForFishing(weather.Condition) {
+			quality += 2
+		} else if isBadWeatherForFishing(weather.Condition) {
+			quality -= 2
+		}
+
+		// Fish bite best in the temperatures typical of their waters
+		switch {
+		case weather.TempC < region.Temperature.Min:
+			conditions.TemperatureRange = "below"
+			quality--
+		case weather.TempC > region.Temperature.Max:
+			conditions.TemperatureRange = "above"
+			quality--
+		default:
+			conditions.TemperatureRange = "within"
+			quality++
+		}
+
+		// Adjust based on wind
+		switch {
+		case weather.WindKph < 20:
+			conditions.Wind = "calm"
+			quality++
+		case weather.WindKph < 50:
+			conditions.Wind = "breezy"
+		default:
+			conditions.Wind = "strong"
+			quality--
+		}
+
+		if weather.IsExtreme {
+			quality--
+		}
+
+		conditions.ActiveSpecies = s.activeSpecies(ctx, regionID, weather.Condition)
+	}
+
+	if astronomy := s.astronomy(); astronomy != nil {
+		conditions.MoonPhase = astronomy.MoonPhase
+		conditions.MoonIllumination = astronomy.MoonIllumination
+		conditions.FullMoon = astronomy.FullMoon()
+		conditions.TideRange = astronomy.TideRange
+		if sky := astronomy.Region(regionID); sky != nil {
+			conditions.Sunrise = sky.Sunrise
+			conditions.Sunset = sky.Sunset
+			conditions.Tide = sky.Tide
+
+			// Fish feed on moving water
+			if sky.Tide == "rising" || sky.Tide == "falling" {
+				quality++
+			}
+		}
 	}
 
 	// Adjust based on time of day
-	timeFactor := 0
-	if params.TimeOfDay == "morning" || params.TimeOfDay == "evening" {
-		timeFactor = 1 // Dawn and dusk are good for fishing
-	} else if params.TimeOfDay == "night" {
-		timeFactor = -1 // Night is typically harder
+	if conditions.TimeOfDay == "morning" || conditions.TimeOfDay == "evening" {
+		quality++ // Dawn and dusk are good for fishing
+	} else if conditions.TimeOfDay == "night" && conditions.MoonIllumination < 0.5 {
+		quality-- // Night is typically harder, unless a bright moon lights the water
 	}
 
 	// Calculate final quality
-	quality += weatherFactor + timeFactor
 	if quality < 1 {
 		quality = 1
 	} else if quality > 10 {
 		quality = 10
 	}
+	conditions.Quality = quality
+	conditions.FishingQuality = getFishingQuality(quality)
 
 	// Calculate rarity factor (0.0-1.0) - higher quality means more chance of rare fish
 	rarityFactor := float64(quality) / 10.0
 
-	return &Conditions{
-		Weather:    params.WeatherCondition,
-		Region:     regionName,
-		RegionTags: regionTags,
-		TimeOfDay:  params.TimeOfDay,
-		Quality:    quality,
-	}, rarityFactor
+	return conditions, rarityFactor
+}
+
+// astronomy returns the latest collected moon, sun and tides, or nil without the astronomy
+// collector
+func (s *FishingService) astronomy() *data.Astronomy {
+	if s.dataManager == nil {
+		return nil
+	}
+	return s.dataManager.Astronomy()
+}
+
+// activeSpecies returns the names of up to five species of a region that favor the weather
+func (s *FishingService) activeSpecies(ctx context.Context, regionID, condition string) []string {
+	species := make([]string, 0)
+	if s.storage == nil {
+		return species
+	}
+	fishList, err := s.storage.GetFishByRegion(ctx, regionID, 100)
+	if err != nil {
+		log.Printf("Error getting fish of region %s: %v", regionID, err)
+		return species
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range fishList {
+		if len(species) == 5 {
+			break
+		}
+		if seen[f.Name] || !favorsWeather(f.FavoriteWeather, condition) {
+			continue
+		}
+		seen[f.Name] = true
+		species = append(species, f.Name)
+	}
+	return species
 }
 
 // calculateSuccessChance determines the chance of a successful catch
@@ -260,12 +415,12 @@ func (s *FishingService) calculateSuccessChance(params FishingParams, conditions
 }
 
 // selectDataSource determines which data source to use based on conditions
-func (s *FishingService) selectDataSource(params FishingParams, conditions *Conditions) string {
+func (s *FishingService) selectDataSource(conditions *Conditions) string {
 	// Default
 	dataSource := ""
 
 	// Select data source by weather conditions
-	if isExtremeWeather(params.WeatherCondition, params.Temperature) {
+	if conditions.IsExtreme {
 		dataSource = "weather" // Extreme weather affects fish type
 	} else if conditions.Quality >= 8 {
 		// For really good conditions, use more exciting sources
@@ -281,9 +436,9 @@ func (s *FishingService) selectDataSource(params FishingParams, conditions *Cond
 }
 
 // getFishByConditions finds a suitable fish based on region, data source, and rarity
-func (s *FishingService) getFishByConditions(ctx context.Context, regionID string, dataSource string, rarityFactor float64) (*fish.Fish, error) {
+func (s *FishingService) getFishByConditions(ctx context.Context, regionID string, dataSource string, rarityFactor float64, fullMoon bool) (*fish.Fish, error) {
 	// Determine rarity tier based on rarity factor
-	rarityLevel := determineRarity(rarityFactor)
+	rarityLevel := determineRarity(rarityFactor, fullMoon)
 
 	// Try to get a fish by data source and rarity
 	if dataSource != "" {
@@ -334,7 +489,7 @@ func (s *FishingService) getAnyFish(ctx context.Context, regionID string) (*fish
 // isGoodWeatherForFishing determines if current weather is good for fishing
 func isGoodWeatherForFishing(condition string) bool {
 	goodConditions := []string{
-		"partly cloudy", "cloudy", "overcast", "light rain", "drizzle", "mist",
+		"partly cloudy", "cloudy", "clouds", "overcast", "light rain", "drizzle", "mist",
 	}
 
 	condition = strings.ToLower(condition)
@@ -363,30 +518,77 @@ func isBadWeatherForFishing(condition string) bool {
 	return false
 }
 
-// isExtremeWeather checks if weather is extreme
-func isExtremeWeather(condition string, tempC float64) bool {
-	// Check for extreme conditions
-	extremeConditions := []string{
-		"thunderstorm", "hurricane", "tornado", "blizzard", "storm",
-	}
+// weatherKeywords maps OpenWeatherMap conditions to the words generated fish use for the
+// weather they favor
+var weatherKeywords = map[string][]string{
+	"clear":        {"clear", "sun"},
+	"clouds":       {"cloud", "overcast"},
+	"rain":         {"rain", "wet"},
+	"drizzle":      {"drizzle", "rain"},
+	"thunderstorm": {"storm", "thunder", "lightning"},
+	"snow":         {"snow", "ice", "icy", "frost"},
+	"mist":         {"mist", "fog"},
+	"fog":          {"fog", "mist"},
+	"haze":         {"haze", "fog"},
+}
 
+// favorsWeather reports whether a fish's favorite weather matches a weather condition
+func favorsWeather(favorite, condition string) bool {
+	favorite = strings.ToLower(favorite)
 	condition = strings.ToLower(condition)
-	for _, extreme := range extremeConditions {
-		if strings.Contains(condition, extreme) {
+	if favorite == "" || condition == "" {
+		return false
+	}
+	keywords, ok := weatherKeywords[condition]
+	if !ok {
+		keywords = []string{condition}
+	}
+	for _, keyword := range keywords {
+		if strings.Contains(favorite, keyword) {
 			return true
 		}
 	}
+	return false
+}
+
+// getTimeOfDay returns the time of day of a local time
+func getTimeOfDay(t time.Time) string {
+	hour := t.Hour()
 
-	// Check for extreme temperatures
-	if tempC > 35 || tempC < 0 {
-		return true
+	if hour >= 5 && hour < 12 {
+		return "morning"
+	} else if hour >= 12 && hour < 17 {
+		return "afternoon"
+	} else if hour >= 17 && hour < 21 {
+		return "evening"
+	} else {
+		return "night"
 	}
+}
 
-	return false
+// getFishingQuality describes a 1-10 conditions quality
+func getFishingQuality(quality int) string {
+	switch {
+	case quality >= 8:
+		return "Excellent"
+	case quality >= 6:
+		return "Good"
+	case quality >= 4:
+		return "Fair"
+	default:
+		return "Poor"
+	}
 }
 
+// fullMoonLegendaryChance is the extra chance of a Legendary catch under a full moon
+const fullMoonLegendaryChance = 0.05
+
 // determineRarity returns a rarity string based on rarity factor
-func determineRarity(rarityFactor float64) string {
+func determineRarity(rarityFactor float64, fullMoon bool) string {
+	if fullMoon && rand.Float64() < fullMoonLegendaryChance {
+		return string(fish.Legendary)
+	}
+
 	// Adjust chance based on rarity factor (higher factor = better chance for rare)
 	roll := rand.Float64()
 
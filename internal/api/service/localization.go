@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"fish-generate/internal/data"
+	"fish-generate/internal/storage"
+)
+
+// DefaultLanguage is the language fish are generated in
+const DefaultLanguage = "en"
+
+// LocalizedFish is the player-facing view of a fish record, with its text fields translated
+// where a translation exists. Publish status, moderation and prompt details stay internal.
+type LocalizedFish struct {
+	ID               string                `json:"id"`
+	Name             string                `json:"name"`
+	Description      string                `json:"description"`
+	Appearance       string                `json:"appearance,omitempty"`
+	Rarity           string                `json:"rarity"`
+	Length           float64               `json:"length"` // in meters
+	Weight           float64               `json:"weight"` // in kilograms
+	Value            float64               `json:"value"`  // in USD
+	Color            string                `json:"color"`
+	Habitat          string                `json:"habitat"`
+	Diet             string                `json:"diet"`
+	Effect           string                `json:"effect"`
+	FavoriteWeather  string                `json:"favorite_weather"`
+	CatchChance      float64               `json:"catch_chance"` // percentage, 0-100
+	ExistenceReason  string                `json:"existence_reason"`
+	OriginContext    string                `json:"origin_context,omitempty"`
+	GeneratedAt      time.Time             `json:"generated_at"`
+	IsAIGenerated    bool                  `json:"is_ai_generated"`
+	DataSource       string                `json:"data_source"`
+	RegionID         string                `json:"region_id,omitempty"`
+	GenerationReason string                `json:"generation_reason,omitempty"`
+	StatEffects      []data.FishStatEffect `json:"stat_effects"`
+	UsedArticles     []data.UsedArticle    `json:"used_articles"`
+	VariantOf        string                `json:"variant_of,omitempty"` // ID of the species this fish is a variant of
+	Language         string                `json:"language"`             // Requested language
+	FieldLanguages   map[string]string     `json:"field_languages"`      // Language each translatable field was served in
+}
+
+// LocalizedFishPage is one page of the fish catalog in the requested language
+type LocalizedFishPage struct {
+	Fish       []*LocalizedFish `json:"fish"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// Localizer overlays stored translations onto fish records
+type Localizer struct {
+	storage storage.StorageAdapter
+}
+
+// NewLocalizer creates a new localizer
+func NewLocalizer(storage storage.StorageAdapter) *Localizer {
+	return &Localizer{storage: storage}
+}
+
+// Localize returns the fish in the given language, falling back to English for
+// every field that has no translation
+func (l *Localizer) Localize(ctx context.Context, record *data.FishRecord, language string) *LocalizedFish {
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	localized := &LocalizedFish{
+		ID:               record.ID,
+		Name:             record.Name,
+		Description:      record.Description,
+		Appearance:       record.Appearance,
+		Rarity:           record.Rarity,
+		Length:           record.Length,
+		Weight:           record.Weight,
+		Value:            record.Value,
+		Color:            record.Color,
+		Habitat:          record.Habitat,
+		Diet:             record.Diet,
+		Effect:           record.Effect,
+		FavoriteWeather:  record.FavoriteWeather,
+		CatchChance:      record.CatchChance,
+		ExistenceReason:  record.ExistenceReason,
+		OriginContext:    record.OriginContext,
+		GeneratedAt:      record.GeneratedAt,
+		IsAIGenerated:    record.IsAIGenerated,
+		DataSource:       record.DataSource,
+		RegionID:         record.RegionID,
+		GenerationReason: record.GenerationReason,
+		StatEffects:      record.StatEffects,
+		UsedArticles:     record.UsedArticles,
+		VariantOf:        record.VariantOf,
+		Language:         language,
+		FieldLanguages:   make(map[string]string),
+	}
+
+	var translation *data.TranslatedFish
+	if language != DefaultLanguage && record.ID != "" && l.storage != nil {
+		var err error
+		translation, err = l.storage.GetTranslatedFish(ctx, record.ID, language)
+		if err != nil {
+			log.Printf("Failed to load %s translation of fish %s: %v", language, record.ID, err)
+		}
+	}
+	if translation == nil {
+		translation = &data.TranslatedFish{}
+	}
+
+	for _, field := range []struct {
+		name       string
+		value      *string
+		translated string
+	}{
+		{"name", &localized.Name, translation.Name},
+		{"description", &localized.Description, translation.Description},
+		{"appearance", &localized.Appearance, translation.Appearance},
+		{"color", &localized.Color, translation.Color},
+		{"diet", &localized.Diet, translation.Diet},
+		{"habitat", &localized.Habitat, translation.Habitat},
+		{"effect", &localized.Effect, translation.Effect},
+		{"favorite_weather", &localized.FavoriteWeather, translation.FavoriteWeather},
+		{"existence_reason", &localized.ExistenceReason, translation.ExistenceReason},
+	} {
+		if field.translated != "" {
+			*field.value = field.translated
+			localized.FieldLanguages[field.name] = language
+		} else {
+			localized.FieldLanguages[field.name] = DefaultLanguage
+		}
+	}
+
+	return localized
+}
+
+// LocalizePage localizes every fish on a catalog page
+func (l *Localizer) LocalizePage(ctx context.Context, page *storage.FishPage, language string) *LocalizedFishPage {
+	localized := &LocalizedFishPage{
+		Fish:       make([]*LocalizedFish, len(page.Fish)),
+		NextCursor: page.NextCursor,
+	}
+	for i, record := range page.Fish {
+		localized.Fish[i] = l.Localize(ctx, record, language)
+	}
+	return localized
+}
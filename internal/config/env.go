@@ -3,6 +3,7 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -25,17 +26,90 @@ type Config struct {
 	MongoUser     string
 	MongoPassword string
 
-	// Collection intervals (as floats to handle fractions of hours)
-	WeatherInterval    float64
-	PriceInterval      float64
-	NewsInterval       float64
+	// Storage backend selection: "mongodb", "sqlite" or "memory"
+	StorageBackend string
+	SQLitePath     string
+
+	// In-memory storage snapshot file, used when MongoDB is not configured
+	MemorySnapshotPath string
+
 	GenerationCooldown int // in minutes
 
+	// Data collectors by name, from COLLECTORS and the COLLECTOR_<NAME>_* variables
+	Collectors map[string]CollectorSettings
+
+	// Retries and circuit breaker of every collector
+	CollectorMaxAttempts      int // attempts per collection, including the first
+	CollectorBreakerThreshold int // failed collections in a row that open the breaker
+	CollectorBreakerTimeout   int // in minutes
+
+	// Share of every collector API quota that is never used, between 0 and 1
+	CollectorQuotaMargin float64
+
+	// Recording or replay of collector HTTP responses: "record", "replay" or empty for neither
+	HTTPFixturesMode string
+	HTTPFixturesDir  string
+
 	// Translation settings
 	EnableTranslation   bool
-	TranslationInterval int // in minutes
+	TranslationInterval int    // in minutes
+	TranslationLocales  string // comma-separated locale codes, e.g. "vi,ja,es"
+
+	// LLM response cache settings
+	LLMCacheEnabled    bool
+	LLMCacheTTL        int // in hours
+	LLMCacheMaxEntries int
+
+	// LLM usage accounting settings
+	LLMDailyTokenBudget     int     // zero means unlimited
+	LLMPromptPricePerMTok   float64 // USD per million prompt tokens
+	LLMResponsePricePerMTok float64 // USD per million response tokens
+
+	// Admin API keys, mapped to the name of the admin using them
+	AdminAPIKeys map[string]string
+
+	// Directory of prompt template files overriding the built-in prompts
+	PromptTemplateDir string
+
+	// JSON file of prompt experiments; no experiment runs when empty
+	ExperimentsFile string
+
+	// Species uniqueness guard settings
+	SpeciesGuardEnabled          bool
+	SpeciesRenameAttempts        int
+	SpeciesNameSimilarity        float64 // 0-1, minimum edit-distance similarity of duplicate names
+	SpeciesNameTokenOverlap      float64 // 0-1, minimum word overlap of duplicate names
+	SpeciesDescriptionSimilarity float64 // 0-1, minimum word overlap of duplicate descriptions
+
+	// Content moderation settings
+	ModerationEnabled   bool
+	ModerationRulesFile string // JSON file of blocklist terms and patterns added to the built-in rules
+	ModerationSelfCheck bool   // Ask the LLM to review fish the rules let through
+	ReviewGeneratedFish bool   // Save generated fish as drafts that an admin must approve
+}
+
+// CollectorSettings configures one data collector
+type CollectorSettings struct {
+	Enabled      bool
+	Interval     float64 // in hours
+	APIKey       string
+	BaseURL      string // Replaces the scheme and host of the collector's API
+	DailyQuota   int    // API calls per UTC day; zero for no limit
+	MonthlyQuota int    // API calls per UTC month; zero for no limit
+	QuotaBurst   int    // API calls allowed back to back ahead of the even pace
 }
 
+// defaultCollectorQuotas are the free-tier limits of the built-in collectors' APIs.
+// The weather burst covers one request per city of the fishing regions.
+var defaultCollectorQuotas = map[string]CollectorSettings{
+	"weather": {DailyQuota: 1000, QuotaBurst: 20},
+	"news":    {DailyQuota: 100, QuotaBurst: 1},
+	"gold":    {MonthlyQuota: 100, QuotaBurst: 1},
+}
+
+// defaultCollectors are the collectors enabled when COLLECTORS is not set
+const defaultCollectors = "weather,bitcoin,gold,astronomy,news"
+
 // LoadEnv loads environment variables from a .env file
 func LoadEnv(filePath string) error {
 	file, err := os.Open(filePath)
@@ -105,6 +179,87 @@ func NewConfig() *Config {
 		translationInterval = 2 // Default: translate one fish every 2 minutes
 	}
 
+	translationLocales := strings.TrimSpace(os.Getenv("TRANSLATION_LOCALES"))
+	if translationLocales == "" {
+		translationLocales = "vi" // Default: Vietnamese only
+	}
+
+	llmCacheTTL, err := strconv.Atoi(os.Getenv("LLM_CACHE_TTL_HOURS"))
+	if err != nil || llmCacheTTL <= 0 {
+		llmCacheTTL = 168 // Default: reuse responses for 7 days
+	}
+
+	llmCacheMaxEntries, err := strconv.Atoi(os.Getenv("LLM_CACHE_MAX_ENTRIES"))
+	if err != nil || llmCacheMaxEntries <= 0 {
+		llmCacheMaxEntries = 5000 // Default: keep the 5000 newest responses
+	}
+
+	llmDailyTokenBudget, err := strconv.Atoi(os.Getenv("LLM_DAILY_TOKEN_BUDGET"))
+	if err != nil || llmDailyTokenBudget < 0 {
+		llmDailyTokenBudget = 0 // Default: no budget
+	}
+
+	llmPromptPrice, err := strconv.ParseFloat(os.Getenv("LLM_PROMPT_PRICE_PER_MTOK"), 64)
+	if err != nil || llmPromptPrice < 0 {
+		llmPromptPrice = 0 // Default: cost is not estimated
+	}
+
+	llmResponsePrice, err := strconv.ParseFloat(os.Getenv("LLM_RESPONSE_PRICE_PER_MTOK"), 64)
+	if err != nil || llmResponsePrice < 0 {
+		llmResponsePrice = 0
+	}
+
+	speciesRenameAttempts, err := strconv.Atoi(os.Getenv("SPECIES_RENAME_ATTEMPTS"))
+	if err != nil || speciesRenameAttempts < 0 {
+		speciesRenameAttempts = 2 // Default: ask for two new names before marking a variant
+	}
+
+	speciesNameSimilarity, err := strconv.ParseFloat(os.Getenv("SPECIES_NAME_SIMILARITY"), 64)
+	if err != nil || speciesNameSimilarity <= 0 || speciesNameSimilarity > 1 {
+		speciesNameSimilarity = 0.85
+	}
+
+	speciesNameTokenOverlap, err := strconv.ParseFloat(os.Getenv("SPECIES_NAME_TOKEN_OVERLAP"), 64)
+	if err != nil || speciesNameTokenOverlap <= 0 || speciesNameTokenOverlap > 1 {
+		speciesNameTokenOverlap = 0.75
+	}
+
+	speciesDescriptionSimilarity, err := strconv.ParseFloat(os.Getenv("SPECIES_DESCRIPTION_SIMILARITY"), 64)
+	if err != nil || speciesDescriptionSimilarity < 0 || speciesDescriptionSimilarity > 1 {
+		speciesDescriptionSimilarity = 0.6
+	}
+
+	collectorMaxAttempts, err := strconv.Atoi(os.Getenv("COLLECTOR_MAX_ATTEMPTS"))
+	if err != nil || collectorMaxAttempts <= 0 {
+		collectorMaxAttempts = 3 // Default: retry a failed collection twice
+	}
+
+	collectorBreakerThreshold, err := strconv.Atoi(os.Getenv("COLLECTOR_BREAKER_THRESHOLD"))
+	if err != nil || collectorBreakerThreshold <= 0 {
+		collectorBreakerThreshold = 5
+	}
+
+	collectorBreakerTimeout, err := strconv.Atoi(os.Getenv("COLLECTOR_BREAKER_TIMEOUT"))
+	if err != nil || collectorBreakerTimeout <= 0 {
+		collectorBreakerTimeout = 10 // Default: 10 minutes
+	}
+
+	collectorQuotaMargin, err := strconv.ParseFloat(os.Getenv("COLLECTOR_QUOTA_MARGIN"), 64)
+	if err != nil || collectorQuotaMargin < 0 || collectorQuotaMargin >= 1 {
+		collectorQuotaMargin = 0.1 // Default: stop at 90% of each quota
+	}
+
+	// The older per-source variables are the defaults of the built-in collectors
+	collectors := parseCollectors(
+		map[string]float64{"weather": weatherInterval, "bitcoin": priceInterval, "gold": priceInterval, "oil": priceInterval, "news": newsInterval},
+		map[string]string{
+			"weather": os.Getenv("OPENWEATHER_API_KEY"),
+			"gold":    os.Getenv("METALPRICE_API_KEY"),
+			"oil":     os.Getenv("EIA_API_KEY"),
+			"news":    os.Getenv("NEWSAPI_KEY"),
+		},
+	)
+
 	return &Config{
 		GeminiAPIKey:   os.Getenv("GEMINI_API_KEY"),
 		UseAI:          os.Getenv("USE_AI") == "true" || os.Getenv("USE_AI") == "1",
@@ -120,34 +275,150 @@ func NewConfig() *Config {
 		MongoUser:     os.Getenv("MONGO_USER"),
 		MongoPassword: os.Getenv("MONGO_PASSWORD"),
 
-		// Collection intervals (store as float to preserve fractional hours)
-		WeatherInterval:    weatherInterval,
-		PriceInterval:      priceInterval,
-		NewsInterval:       newsInterval,
+		// Storage backend settings
+		StorageBackend:     strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_BACKEND"))),
+		SQLitePath:         os.Getenv("SQLITE_PATH"),
+		MemorySnapshotPath: os.Getenv("MEMORY_SNAPSHOT_PATH"),
+
 		GenerationCooldown: generationCooldown,
 
+		Collectors: collectors,
+
+		CollectorMaxAttempts:      collectorMaxAttempts,
+		CollectorBreakerThreshold: collectorBreakerThreshold,
+		CollectorBreakerTimeout:   collectorBreakerTimeout,
+		CollectorQuotaMargin:      collectorQuotaMargin,
+
+		HTTPFixturesMode: strings.ToLower(strings.TrimSpace(os.Getenv("HTTP_FIXTURES_MODE"))),
+		HTTPFixturesDir:  os.Getenv("HTTP_FIXTURES_DIR"),
+
 		// Translation settings
 		EnableTranslation:   os.Getenv("ENABLE_TRANSLATION") == "1",
 		TranslationInterval: translationInterval,
+		TranslationLocales:  translationLocales,
+
+		// LLM response cache settings
+		LLMCacheEnabled:    os.Getenv("LLM_CACHE") != "0" && os.Getenv("LLM_CACHE") != "false",
+		LLMCacheTTL:        llmCacheTTL,
+		LLMCacheMaxEntries: llmCacheMaxEntries,
+
+		// LLM usage accounting settings
+		LLMDailyTokenBudget:     llmDailyTokenBudget,
+		LLMPromptPricePerMTok:   llmPromptPrice,
+		LLMResponsePricePerMTok: llmResponsePrice,
+
+		AdminAPIKeys: parseAdminAPIKeys(os.Getenv("ADMIN_API_KEYS")),
+
+		PromptTemplateDir: strings.TrimSpace(os.Getenv("PROMPT_TEMPLATE_DIR")),
+
+		ExperimentsFile: strings.TrimSpace(os.Getenv("EXPERIMENTS_FILE")),
+
+		// Species uniqueness guard settings
+		SpeciesGuardEnabled:          os.Getenv("SPECIES_GUARD") != "0" && os.Getenv("SPECIES_GUARD") != "false",
+		SpeciesRenameAttempts:        speciesRenameAttempts,
+		SpeciesNameSimilarity:        speciesNameSimilarity,
+		SpeciesNameTokenOverlap:      speciesNameTokenOverlap,
+		SpeciesDescriptionSimilarity: speciesDescriptionSimilarity,
+
+		// Content moderation settings
+		ModerationEnabled:   os.Getenv("MODERATION") != "0" && os.Getenv("MODERATION") != "false",
+		ModerationRulesFile: strings.TrimSpace(os.Getenv("MODERATION_RULES_FILE")),
+		ModerationSelfCheck: os.Getenv("MODERATION_SELF_CHECK") == "1" || os.Getenv("MODERATION_SELF_CHECK") == "true",
+		ReviewGeneratedFish: os.Getenv("REVIEW_GENERATED_FISH") == "1" || os.Getenv("REVIEW_GENERATED_FISH") == "true",
 	}
 }
 
-// GetWeatherInterval returns the weather collection interval as a time.Duration
-func (c *Config) GetWeatherInterval() time.Duration {
-	// Convert hours to duration
-	return time.Duration(c.WeatherInterval * float64(time.Hour))
+// parseAdminAPIKeys parses comma-separated "name:key" pairs. Entries without a name or
+// a key are skipped with a warning, since the audit trail needs to know who acted.
+func parseAdminAPIKeys(value string) map[string]string {
+	keys := make(map[string]string)
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			log.Printf("Warning: ignoring ADMIN_API_KEYS entry %d, which is not a name:key pair", i+1)
+			continue
+		}
+		keys[strings.TrimSpace(parts[1])] = strings.TrimSpace(parts[0])
+	}
+	return keys
 }
 
-// GetPriceInterval returns the price collection interval as a time.Duration
-func (c *Config) GetPriceInterval() time.Duration {
-	// Convert hours to duration
-	return time.Duration(c.PriceInterval * float64(time.Hour))
+// parseCollectors reads the comma-separated COLLECTORS list and the COLLECTOR_<NAME>_INTERVAL
+// (hours), COLLECTOR_<NAME>_API_KEY, COLLECTOR_<NAME>_BASE_URL, COLLECTOR_<NAME>_DAILY_QUOTA,
+// COLLECTOR_<NAME>_MONTHLY_QUOTA and COLLECTOR_<NAME>_QUOTA_BURST overrides of each collector,
+// falling back to the given intervals and keys and the default quotas. Collectors without an
+// interval run hourly.
+func parseCollectors(intervals map[string]float64, apiKeys map[string]string) map[string]CollectorSettings {
+	enabled := strings.TrimSpace(os.Getenv("COLLECTORS"))
+	if enabled == "" {
+		enabled = defaultCollectors
+	}
+
+	collectors := make(map[string]CollectorSettings)
+	for name := range intervals {
+		collectors[name] = CollectorSettings{}
+	}
+	for _, name := range strings.Split(enabled, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			collectors[name] = CollectorSettings{Enabled: true}
+		}
+	}
+
+	for name, settings := range collectors {
+		settings.Interval = intervals[name]
+		settings.APIKey = apiKeys[name]
+
+		prefix := "COLLECTOR_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		if interval, err := strconv.ParseFloat(os.Getenv(prefix+"INTERVAL"), 64); err == nil && interval > 0 {
+			settings.Interval = interval
+		}
+		if key := os.Getenv(prefix + "API_KEY"); key != "" {
+			settings.APIKey = key
+		}
+		settings.BaseURL = strings.TrimSpace(os.Getenv(prefix + "BASE_URL"))
+
+		quota := defaultCollectorQuotas[name]
+		settings.DailyQuota = envInt(prefix+"DAILY_QUOTA", quota.DailyQuota)
+		settings.MonthlyQuota = envInt(prefix+"MONTHLY_QUOTA", quota.MonthlyQuota)
+		settings.QuotaBurst = envInt(prefix+"QUOTA_BURST", quota.QuotaBurst)
+		if settings.Interval <= 0 {
+			settings.Interval = 1
+		}
+		collectors[name] = settings
+	}
+	return collectors
+}
+
+// envInt returns a non-negative integer environment variable, or fallback when it is unset or invalid
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// GetInterval returns the collection interval as a time.Duration
+func (s CollectorSettings) GetInterval() time.Duration {
+	return time.Duration(s.Interval * float64(time.Hour))
 }
 
-// GetNewsInterval returns the news collection interval as a time.Duration
-func (c *Config) GetNewsInterval() time.Duration {
-	// Convert hours to duration
-	return time.Duration(c.NewsInterval * float64(time.Hour))
+// GetCollectorBreakerTimeout returns how long an open collector circuit breaker stays open as a time.Duration
+func (c *Config) GetCollectorBreakerTimeout() time.Duration {
+	return time.Duration(c.CollectorBreakerTimeout) * time.Minute
+}
+
+// GetHTTPFixturesDir returns the directory HTTP fixtures are recorded into and replayed from
+func (c *Config) GetHTTPFixturesDir() string {
+	if c.HTTPFixturesDir != "" {
+		return c.HTTPFixturesDir
+	}
+	return "testdata/http"
 }
 
 // GetGenerationCooldown returns the fish generation cooldown as a time.Duration
@@ -155,6 +426,11 @@ func (c *Config) GetGenerationCooldown() time.Duration {
 	return time.Duration(c.GenerationCooldown) * time.Minute
 }
 
+// GetLLMCacheTTL returns how long cached LLM responses are reused as a time.Duration
+func (c *Config) GetLLMCacheTTL() time.Duration {
+	return time.Duration(c.LLMCacheTTL) * time.Hour
+}
+
 // GetMongoURI returns the complete MongoDB connection URI
 func (c *Config) GetMongoURI() string {
 	// If a complete URI is provided, use it
@@ -178,3 +454,30 @@ func (c *Config) GetMongoDB() string {
 	}
 	return "fish_generator"
 }
+
+// GetStorageBackend returns the storage backend to use, or an error for an unknown STORAGE_BACKEND.
+// Without an explicit STORAGE_BACKEND, MongoDB is used when MONGO_URI is set and memory otherwise.
+func (c *Config) GetStorageBackend() (string, error) {
+	switch c.StorageBackend {
+	case "mongodb", "sqlite", "memory":
+		return c.StorageBackend, nil
+	case "mongo":
+		return "mongodb", nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown STORAGE_BACKEND %q (use mongodb, sqlite or memory)", c.StorageBackend)
+	}
+
+	if c.MongoURI != "" {
+		return "mongodb", nil
+	}
+	return "memory", nil
+}
+
+// GetSQLitePath returns the SQLite database file path
+func (c *Config) GetSQLitePath() string {
+	if c.SQLitePath != "" {
+		return c.SQLitePath
+	}
+	return "data/fish_generator.db"
+}
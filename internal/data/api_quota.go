@@ -0,0 +1,304 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Quota windows, both in UTC
+const (
+	QuotaWindowDay   = "day"
+	QuotaWindowMonth = "month"
+)
+
+// quotaUsageRetryDelay is how long calls are deferred when the stored usage of a window
+// cannot be loaded
+const quotaUsageRetryDelay = time.Minute
+
+// QuotaError is returned instead of calling an API when the call would exceed its quota,
+// get ahead of the even pace of calls across the window, or the calls already made in the
+// window are unknown
+type QuotaError struct {
+	Source    string
+	Window    string // QuotaWindowDay or QuotaWindowMonth
+	Exhausted bool   // The window's budget is spent, rather than the call being early
+	RetryAt   time.Time
+	Err       error // Why the window's usage could not be loaded, if that deferred the call
+}
+
+func (e *QuotaError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s API calls are deferred until %s: failed to load the %s quota usage: %v", e.Source, e.RetryAt.UTC().Format(time.RFC3339), e.Window, e.Err)
+	}
+	if e.Exhausted {
+		return fmt.Sprintf("%s API %s quota is exhausted until %s", e.Source, e.Window, e.RetryAt.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s API calls are deferred until %s to spread the %s quota", e.Source, e.RetryAt.UTC().Format(time.RFC3339), e.Window)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return e.Err
+}
+
+// APIQuota is the call budget of one source. Calls are spread evenly across each window:
+// by any point in it, the share of the budget matching the elapsed share of the window may
+// have been used, plus Burst calls.
+type APIQuota struct {
+	Daily   int // Calls per UTC day; zero for no daily limit
+	Monthly int // Calls per UTC month; zero for no monthly limit
+	Burst   int // Calls that may be made back to back, e.g. the requests of one collection; at least 1
+}
+
+// APIQuotaUsage is the number of calls made to a source in one quota window
+type APIQuotaUsage struct {
+	Source string `bson:"source" json:"source"`
+	Window string `bson:"window" json:"window"` // UTC day (2006-01-02) or month (2006-01)
+	Calls  int    `bson:"calls" json:"calls"`
+}
+
+// APIQuotaStore persists the number of calls made to each source per window
+type APIQuotaStore interface {
+	// IncrementAPIQuotaUsage adds one call to a source in a window, e.g. "2006-01-02" or "2006-01"
+	IncrementAPIQuotaUsage(ctx context.Context, source, window string) error
+	// GetAPIQuotaUsage returns the calls made to a source in a window
+	GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error)
+}
+
+// APIQuotaStatus is the usage of one source's quota as reported by the API
+type APIQuotaStatus struct {
+	Source       string     `json:"source"`
+	DailyLimit   int        `json:"daily_limit"` // Zero means unlimited
+	DailyCalls   int        `json:"daily_calls"`
+	MonthlyLimit int        `json:"monthly_limit"` // Zero means unlimited
+	MonthlyCalls int        `json:"monthly_calls"`
+	NextCallAt   *time.Time `json:"next_call_at,omitempty"` // Set while calls are deferred or the budget is spent
+}
+
+// quotaWindow is one day or month of a source's quota
+type quotaWindow struct {
+	name       string // QuotaWindowDay or QuotaWindowMonth
+	key        string // Stored window, e.g. "2006-01-02"
+	start, end time.Time
+	limit      int
+}
+
+// APIQuotaManager counts the calls made to each rate-limited source in storage, so budgets
+// survive restarts, and holds calls back when a budget is nearly spent or calls come faster
+// than the budget allows. A nil APIQuotaManager lets every call through.
+type APIQuotaManager struct {
+	store  APIQuotaStore
+	quotas map[string]APIQuota
+	margin float64                   // Share of each budget that is never used
+	counts map[string]map[string]int // Calls per source and stored window, for the current windows
+	mu     sync.Mutex
+}
+
+// NewAPIQuotaManager creates a manager for the quotas of the named sources. The margin, a
+// share between 0 and 1, is kept unused so calls made elsewhere with the same key do not
+// push a source over its provider's limit.
+func NewAPIQuotaManager(store APIQuotaStore, quotas map[string]APIQuota, margin float64) *APIQuotaManager {
+	if margin < 0 || margin >= 1 {
+		margin = 0
+	}
+	return &APIQuotaManager{
+		store:  store,
+		quotas: quotas,
+		margin: margin,
+		counts: make(map[string]map[string]int),
+	}
+}
+
+// Quota returns the quota of a source and whether it has one
+func (m *APIQuotaManager) Quota(source string) (APIQuota, bool) {
+	if m == nil {
+		return APIQuota{}, false
+	}
+	quota, ok := m.quotas[source]
+	return quota, ok
+}
+
+// Acquire counts a call to a source, or returns a *QuotaError without counting it when the
+// call would use the margin of a budget or come ahead of the even pace. Calls are deferred
+// while the usage of a window cannot be loaded, since it may already be spent.
+func (m *APIQuotaManager) Acquire(ctx context.Context, source string) error {
+	quota, ok := m.Quota(source)
+	if !ok {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	windows := quotaWindows(quota, now)
+	for _, window := range windows {
+		if window.limit <= 0 {
+			continue
+		}
+		calls, err := m.usage(ctx, source, window)
+		if err != nil {
+			return &QuotaError{Source: source, Window: window.name, RetryAt: now.Add(quotaUsageRetryDelay), Err: err}
+		}
+		if retryAt, exhausted := m.nextCall(quota, window, calls, now); retryAt.After(now) {
+			return &QuotaError{Source: source, Window: window.name, Exhausted: exhausted, RetryAt: retryAt}
+		}
+	}
+
+	for _, window := range windows {
+		// Windows whose usage has not been loaded are counted once it loads
+		if _, loaded := m.counts[source][window.key]; loaded {
+			m.counts[source][window.key]++
+		}
+		if err := m.store.IncrementAPIQuotaUsage(ctx, source, window.key); err != nil {
+			log.Printf("Warning: failed to record %s API call for the %s quota: %v", source, window.name, err)
+		}
+	}
+	return nil
+}
+
+// Status reports the usage of every source with a quota, sorted by source
+func (m *APIQuotaManager) Status(ctx context.Context) []APIQuotaStatus {
+	statuses := make([]APIQuotaStatus, 0)
+	if m == nil {
+		return statuses
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for source, quota := range m.quotas {
+		status := APIQuotaStatus{Source: source, DailyLimit: quota.Daily, MonthlyLimit: quota.Monthly}
+		for _, window := range quotaWindows(quota, now) {
+			calls, err := m.usage(ctx, source, window)
+			if err != nil {
+				if window.limit > 0 {
+					// Acquire defers calls until the usage loads
+					retryAt := now.Add(quotaUsageRetryDelay)
+					status.NextCallAt = &retryAt
+				}
+				continue
+			}
+			if window.name == QuotaWindowDay {
+				status.DailyCalls = calls
+			} else {
+				status.MonthlyCalls = calls
+			}
+			if window.limit <= 0 {
+				continue
+			}
+			if retryAt, _ := m.nextCall(quota, window, calls, now); retryAt.After(now) {
+				if status.NextCallAt == nil || retryAt.After(*status.NextCallAt) {
+					status.NextCallAt = &retryAt
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Source < statuses[j].Source
+	})
+	return statuses
+}
+
+// Transport returns a transport that acquires a call from the source's quota before every
+// request, or base unchanged when the source has no quota
+func (m *APIQuotaManager) Transport(source string, base http.RoundTripper) http.RoundTripper {
+	if _, ok := m.Quota(source); !ok {
+		return base
+	}
+	return &quotaTransport{quotas: m, source: source, base: base}
+}
+
+// nextCall returns when the next call fits the window's budget, and whether the budget is
+// spent; a time that is not after now means the call may be made
+func (m *APIQuotaManager) nextCall(quota APIQuota, window quotaWindow, calls int, now time.Time) (time.Time, bool) {
+	budget := int(float64(window.limit) * (1 - m.margin))
+	if budget < 1 {
+		budget = 1
+	}
+	if calls >= budget {
+		return window.end, true
+	}
+
+	burst := quota.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	if calls < burst {
+		return now, false
+	}
+
+	// Calls up to the elapsed share of the budget plus the burst are allowed, so call number
+	// calls+1 is allowed once the elapsed share reaches (calls-burst+1)/budget
+	length := window.end.Sub(window.start)
+	return window.start.Add(time.Duration(float64(length) * float64(calls-burst+1) / float64(budget))), false
+}
+
+// usage returns the stored calls of a source in a window, loading them on first use so
+// restarts keep counting. Callers must hold the lock.
+func (m *APIQuotaManager) usage(ctx context.Context, source string, window quotaWindow) (int, error) {
+	counts, ok := m.counts[source]
+	if !ok {
+		counts = make(map[string]int)
+		m.counts[source] = counts
+	}
+	if calls, ok := counts[window.key]; ok {
+		return calls, nil
+	}
+
+	calls, err := m.store.GetAPIQuotaUsage(ctx, source, window.key)
+	if err != nil {
+		// Try again on the next call rather than assuming nothing was spent
+		log.Printf("Warning: failed to load %s API calls for the %s quota: %v", source, window.name, err)
+		return 0, err
+	}
+
+	// Forget the windows that have passed
+	for key := range counts {
+		if len(key) == len(window.key) {
+			delete(counts, key)
+		}
+	}
+	counts[window.key] = calls
+	return calls, nil
+}
+
+// quotaWindows returns the UTC day and month containing now, with the quota's limits
+func quotaWindows(quota APIQuota, now time.Time) []quotaWindow {
+	year, month, day := now.UTC().Date()
+	dayStart := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return []quotaWindow{
+		{name: QuotaWindowDay, key: dayStart.Format("2006-01-02"), start: dayStart, end: dayStart.AddDate(0, 0, 1), limit: quota.Daily},
+		{name: QuotaWindowMonth, key: monthStart.Format("2006-01"), start: monthStart, end: monthStart.AddDate(0, 1, 0), limit: quota.Monthly},
+	}
+}
+
+// quotaTransport acquires a call from a source's quota before passing a request on
+type quotaTransport struct {
+	quotas *APIQuotaManager
+	source string
+	base   http.RoundTripper // nil for http.DefaultTransport
+}
+
+// RoundTrip performs the request if the quota allows it
+func (t *quotaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.quotas.Acquire(req.Context(), t.source); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
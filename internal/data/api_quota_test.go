@@ -0,0 +1,132 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryAPIQuota is an APIQuotaStore kept in a map that fails while err is set
+type memoryAPIQuota struct {
+	calls map[string]int
+	err   error
+	mu    sync.Mutex
+}
+
+func (q *memoryAPIQuota) IncrementAPIQuotaUsage(ctx context.Context, source, window string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return q.err
+	}
+	q.calls[source+" "+window]++
+	return nil
+}
+
+func (q *memoryAPIQuota) GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return 0, q.err
+	}
+	return q.calls[source+" "+window], nil
+}
+
+func TestAPIQuotaNextCall(t *testing.T) {
+	start := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	day := quotaWindow{name: QuotaWindowDay, key: "2026-10-15", start: start, end: start.AddDate(0, 0, 1), limit: 100}
+	quota := APIQuota{Daily: 100, Burst: 3}
+	manager := NewAPIQuotaManager(nil, map[string]APIQuota{"newsapi": quota}, 0.1) // A budget of 90 calls
+
+	tests := []struct {
+		name          string
+		calls         int
+		now           time.Time
+		wantRetryAt   time.Time // Zero when the call is allowed
+		wantExhausted bool
+	}{
+		{"first call", 0, start, time.Time{}, false},
+		{"burst", 2, start.Add(time.Minute), time.Time{}, false},
+		{"past the burst, early", 3, start.Add(10 * time.Minute), start.Add(16 * time.Minute), false},
+		{"past the burst, on pace", 3, start.Add(16 * time.Minute), time.Time{}, false},
+		{"half the budget by noon", 45, start.Add(12 * time.Hour), time.Time{}, false},
+		{"ahead of the pace", 60, start.Add(12 * time.Hour), start.Add(24 * time.Hour * 58 / 90), false},
+		{"last call of the budget", 89, start.Add(23*time.Hour + 30*time.Minute), time.Time{}, false},
+		{"margin reached", 90, start.Add(23*time.Hour + 30*time.Minute), day.end, true},
+		{"past the limit", 120, start.Add(time.Hour), day.end, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryAt, exhausted := manager.nextCall(quota, day, tt.calls, tt.now)
+			if tt.wantRetryAt.IsZero() {
+				if retryAt.After(tt.now) {
+					t.Errorf("nextCall() = %v, want the call allowed at %v", retryAt, tt.now)
+				}
+				return
+			}
+			if !retryAt.Equal(tt.wantRetryAt) || exhausted != tt.wantExhausted {
+				t.Errorf("nextCall() = %v, %v; want %v, %v", retryAt, exhausted, tt.wantRetryAt, tt.wantExhausted)
+			}
+		})
+	}
+}
+
+func TestAPIQuotaDefersCallsUntilUsageLoads(t *testing.T) {
+	ctx := context.Background()
+	storeErr := errors.New("storage is down")
+	store := &memoryAPIQuota{calls: make(map[string]int), err: storeErr}
+	manager := NewAPIQuotaManager(store, map[string]APIQuota{"newsapi": {Daily: 5, Burst: 5}}, 0)
+	day := quotaWindows(APIQuota{}, time.Now())[0].key
+
+	err := manager.Acquire(ctx, "newsapi")
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Exhausted || !errors.Is(err, storeErr) {
+		t.Fatalf("Acquire() with unknown usage = %v, want a deferring QuotaError", err)
+	}
+	if wait := time.Until(quotaErr.RetryAt); wait <= 0 || wait > quotaUsageRetryDelay {
+		t.Errorf("RetryAt is %v away, want within %v", wait, quotaUsageRetryDelay)
+	}
+	if status := manager.Status(ctx); len(status) != 1 || status[0].NextCallAt == nil {
+		t.Errorf("Status() = %+v, want the next call deferred", status)
+	}
+
+	// Once the usage loads, the budget already spent elsewhere is respected
+	store.mu.Lock()
+	store.err = nil
+	store.calls["newsapi "+day] = 5
+	store.mu.Unlock()
+	if err := manager.Acquire(ctx, "newsapi"); !errors.As(err, &quotaErr) || !quotaErr.Exhausted {
+		t.Errorf("Acquire() with the budget spent = %v, want an exhausted QuotaError", err)
+	}
+	if calls, _ := store.GetAPIQuotaUsage(ctx, "newsapi", day); calls != 5 {
+		t.Errorf("stored calls = %d, want the refused calls left uncounted", calls)
+	}
+}
+
+func TestAPIQuotaCountsAllowedCalls(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryAPIQuota{calls: make(map[string]int)}
+	manager := NewAPIQuotaManager(store, map[string]APIQuota{"newsapi": {Daily: 100, Burst: 2}}, 0)
+	windows := quotaWindows(APIQuota{}, time.Now())
+
+	for i := 0; i < 2; i++ {
+		if err := manager.Acquire(ctx, "newsapi"); err != nil {
+			t.Fatalf("Acquire() call %d within the burst = %v", i+1, err)
+		}
+	}
+	if err := manager.Acquire(ctx, "unlimited"); err != nil {
+		t.Errorf("Acquire() for a source without a quota = %v", err)
+	}
+
+	for _, window := range windows {
+		if calls, _ := store.GetAPIQuotaUsage(ctx, "newsapi", window.key); calls != 2 {
+			t.Errorf("stored %s calls = %d, want 2", window.name, calls)
+		}
+	}
+	if status := manager.Status(ctx); len(status) != 1 || status[0].DailyCalls != 2 || status[0].MonthlyCalls != 2 {
+		t.Errorf("Status() = %+v, want 2 daily and monthly calls", status)
+	}
+}
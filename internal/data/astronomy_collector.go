@@ -0,0 +1,220 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	// synodicMonthDays is the mean time from one new moon to the next
+	synodicMonthDays = 29.530588853
+	// lunarDayHours is the mean time between two transits of the moon over the same meridian
+	lunarDayHours = 24.8412
+	// referenceMoonLongitude is the longitude the moon stood over at referenceNewMoon, where
+	// it was noon
+	referenceMoonLongitude = -93.5
+)
+
+// referenceNewMoon is a new moon the moon's age is counted from
+var referenceNewMoon = time.Date(2000, time.January, 6, 18, 14, 0, 0, time.UTC)
+
+// moonPhases are the names of the eight phases, starting at the new moon
+var moonPhases = []string{
+	"new moon", "waxing crescent", "first quarter", "waxing gibbous",
+	"full moon", "waning gibbous", "last quarter", "waning crescent",
+}
+
+// Astronomy is the moon phase at a moment, and the sun and tide of every fishing region
+type Astronomy struct {
+	Time             time.Time   `json:"time"`
+	MoonPhase        string      `json:"moon_phase"`        // e.g. "waxing gibbous" or "full moon"
+	MoonAge          float64     `json:"moon_age"`          // Days since the last new moon
+	MoonIllumination float64     `json:"moon_illumination"` // Illuminated fraction of the moon's disc, 0-1
+	TideRange        string      `json:"tide_range"`        // "spring" near the new and full moon, "neap" near the quarters, or "moderate"
+	Regions          []RegionSky `json:"regions"`
+}
+
+// RegionSky is the sun and synthetic tide of a fishing region at a moment
+type RegionSky struct {
+	RegionID   string     `json:"region_id"`
+	Sunrise    *time.Time `json:"sunrise,omitempty"` // Of the region's local day, in its time zone; nil when the sun does not rise or set
+	Sunset     *time.Time `json:"sunset,omitempty"`
+	Daylight   bool       `json:"daylight"`    // Whether the sun is up
+	TideHeight float64    `json:"tide_height"` // -1 (lowest spring low tide) to 1 (highest spring high tide)
+	Tide       string     `json:"tide"`        // "rising", "high", "falling" or "low"
+}
+
+// ComputeAstronomy calculates the moon phase, and the sunrise, sunset and tide of each region
+// at a time. Sunrise and sunset come from each region's location and are accurate to a few
+// minutes. The tide is a synthetic semidiurnal curve that follows the moon over the region's
+// longitude, highest at spring tides; it suits fish generation but is not a tide prediction.
+func ComputeAstronomy(regions []Region, now time.Time) *Astronomy {
+	age := math.Mod(now.Sub(referenceNewMoon).Hours()/24, synodicMonthDays)
+	if age < 0 {
+		age += synodicMonthDays
+	}
+	phase := age / synodicMonthDays
+
+	// Spring tides follow the new and full moon, neap tides the quarters
+	tideRange := 0.75 + 0.25*math.Cos(4*math.Pi*phase)
+
+	astronomy := &Astronomy{
+		Time:             now,
+		MoonPhase:        moonPhases[int(math.Floor(phase*8+0.5))%8],
+		MoonAge:          math.Round(age*10) / 10,
+		MoonIllumination: math.Round((1-math.Cos(2*math.Pi*phase))/2*100) / 100,
+		TideRange:        "moderate",
+	}
+	switch {
+	case tideRange >= 0.9:
+		astronomy.TideRange = "spring"
+	case tideRange <= 0.6:
+		astronomy.TideRange = "neap"
+	}
+
+	for _, region := range regions {
+		sky := RegionSky{RegionID: region.ID}
+		zone := region.Zone()
+		sunrise, sunset, sunUp := sunriseSunset(region.Location, now.In(zone))
+		if sunrise.IsZero() {
+			sky.Daylight = sunUp
+		} else {
+			sunrise, sunset = sunrise.In(zone), sunset.In(zone)
+			sky.Sunrise, sky.Sunset = &sunrise, &sunset
+			sky.Daylight = !now.Before(sunrise) && now.Before(sunset)
+		}
+
+		// The moon raises two bulges of water, under it and opposite it, that sweep westward
+		// once per lunar day
+		moonLongitude := referenceMoonLongitude - 360*now.Sub(referenceNewMoon).Hours()/lunarDayHours
+		angle := 2 * (region.Location.Longitude - moonLongitude) * math.Pi / 180
+		level := math.Cos(angle)
+		sky.TideHeight = math.Round(tideRange*level*100) / 100
+		switch {
+		case level >= 0.9:
+			sky.Tide = "high"
+		case level <= -0.9:
+			sky.Tide = "low"
+		case math.Sin(angle) < 0:
+			sky.Tide = "rising"
+		default:
+			sky.Tide = "falling"
+		}
+		astronomy.Regions = append(astronomy.Regions, sky)
+	}
+	return astronomy
+}
+
+// sunriseSunset returns the sunrise and sunset of the local day of a location, using the
+// NOAA approximations of the equation of time and the sun's declination. During polar day
+// and night both times are zero, and sunUp tells which it is.
+func sunriseSunset(location Location, local time.Time) (sunrise, sunset time.Time, sunUp bool) {
+	year, month, day := local.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	gamma := 2 * math.Pi / 365 * float64(local.YearDay()-1)
+	equationOfTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	declination := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	// Hour angle of the sun's upper limb at the horizon, allowing for refraction
+	latitude := location.Latitude * math.Pi / 180
+	cosHourAngle := math.Cos(90.833*math.Pi/180)/(math.Cos(latitude)*math.Cos(declination)) -
+		math.Tan(latitude)*math.Tan(declination)
+	if cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, false
+	}
+	if cosHourAngle < -1 {
+		return time.Time{}, time.Time{}, true
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	// Minutes after midnight UTC, four per degree of longitude
+	rise := 720 - 4*(location.Longitude+hourAngle) - equationOfTime
+	set := 720 - 4*(location.Longitude-hourAngle) - equationOfTime
+	return midnight.Add(time.Duration(rise * float64(time.Minute))),
+		midnight.Add(time.Duration(set * float64(time.Minute))), false
+}
+
+// FullMoon reports whether the moon is full
+func (a *Astronomy) FullMoon() bool {
+	return a.MoonPhase == "full moon"
+}
+
+// Region returns the sun and tide of a region, or nil if the region was not computed
+func (a *Astronomy) Region(regionID string) *RegionSky {
+	for i := range a.Regions {
+		if a.Regions[i].RegionID == regionID {
+			return &a.Regions[i]
+		}
+	}
+	return nil
+}
+
+// SignalSummary describes the moon and tides for the fish generation context
+func (a *Astronomy) SignalSummary() string {
+	return fmt.Sprintf("MOON: %s (%.0f%% illuminated), %s tides", a.MoonPhase, a.MoonIllumination*100, a.TideRange)
+}
+
+// RegionSignalSummary adds the sun and tide of the region the fish is generated for
+func (a *Astronomy) RegionSignalSummary(regionID string) string {
+	sky := a.Region(regionID)
+	if sky == nil {
+		return a.SignalSummary()
+	}
+
+	name := regionID
+	if region, ok := GetRegionByID(regionID); ok {
+		name = region.Name
+	}
+	var sun string
+	switch {
+	case sky.Sunrise == nil && sky.Daylight:
+		sun = "midnight sun"
+	case sky.Sunrise == nil:
+		sun = "polar night"
+	case sky.Daylight:
+		sun = fmt.Sprintf("daylight until %s", sky.Sunset.Format("15:04"))
+	default:
+		sun = "night"
+	}
+	return fmt.Sprintf("%s; %s: %s, %s tide", a.SignalSummary(), name, sun, sky.Tide)
+}
+
+// AstronomyCollector computes the moon phase and the sun and tides of the fishing regions.
+// It needs no API, so its collections never fail.
+type AstronomyCollector struct {
+	regions []Region
+}
+
+// NewAstronomyCollector creates an astronomy collector for the regions
+func NewAstronomyCollector(regions []Region) *AstronomyCollector {
+	return &AstronomyCollector{regions: regions}
+}
+
+// Collect computes the moon, sun and tides at the current time
+func (c *AstronomyCollector) Collect(ctx context.Context) (*DataEvent, error) {
+	now := time.Now()
+	return &DataEvent{
+		Type:      AstronomyData,
+		Value:     ComputeAstronomy(c.regions, now),
+		Timestamp: now,
+		Source:    "computed",
+	}, nil
+}
+
+// GetType returns the type of data collected
+func (c *AstronomyCollector) GetType() DataType {
+	return AstronomyData
+}
+
+// Start begins periodic computation of the moon, sun and tides
+func (c *AstronomyCollector) Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent) {
+	log.Printf("Starting astronomy collector with interval %v", interval)
+	runCollector(ctx, c, interval, eventCh)
+}
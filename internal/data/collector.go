@@ -2,6 +2,8 @@ package data
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,11 +11,12 @@ import (
 type DataType string
 
 const (
-	WeatherData  DataType = "weather"
-	BitcoinData  DataType = "bitcoin"
-	OilPriceData DataType = "oil"
-	NewsData     DataType = "news"
-	GoldData     DataType = "gold"
+	WeatherData   DataType = "weather"
+	BitcoinData   DataType = "bitcoin"
+	OilPriceData  DataType = "oil"
+	NewsData      DataType = "news"
+	GoldData      DataType = "gold"
+	AstronomyData DataType = "astronomy"
 )
 
 // DataEvent represents a data event collected from an external source
@@ -89,12 +92,32 @@ type CryptoPrice struct {
 	Volume24h float64 `json:"volume_24h"`
 }
 
+// SignalSummary describes the price for the fish generation context
+func (c *CryptoPrice) SignalSummary() string {
+	return fmt.Sprintf("%s PRICE: $%.2f (%.2f%% change)", strings.ToUpper(c.Symbol), c.PriceUSD, c.Change24h)
+}
+
+// PricePoint returns the price saved under the lowercase symbol, e.g. "eth"
+func (c *CryptoPrice) PricePoint() (string, float64, float64, float64) {
+	return strings.ToLower(c.Symbol), c.PriceUSD, c.Change24h, c.Volume24h
+}
+
 // OilPrice represents oil price data
 type OilPrice struct {
 	PriceUSD  float64 `json:"price_usd"`
 	Change24h float64 `json:"change_24h"` // 24-hour price change percentage
 }
 
+// SignalSummary describes the price for the fish generation context
+func (o *OilPrice) SignalSummary() string {
+	return fmt.Sprintf("OIL PRICE: $%.2f per barrel (%.2f%% change)", o.PriceUSD, o.Change24h)
+}
+
+// PricePoint returns the price saved as "oil"
+func (o *OilPrice) PricePoint() (string, float64, float64, float64) {
+	return "oil", o.PriceUSD, o.Change24h, 0
+}
+
 // NewsItem represents a news headline or article
 type NewsItem struct {
 	Headline    string    `json:"headline"`
@@ -153,3 +176,29 @@ type DataCollector interface {
 	// Start begins the periodic data collection, sending events to the provided channel
 	Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent)
 }
+
+// Signal is implemented by event values the data manager can use without knowing their source.
+// Events of types without special handling are kept when their value is a Signal, and the
+// latest signal of each type is added to the fish generation context.
+type Signal interface {
+	// SignalSummary describes the value in one line, e.g. "OIL PRICE: $81.20 per barrel (-0.40% change)"
+	SignalSummary() string
+}
+
+// RegionalSignal is a Signal that can also describe the region a fish is generated for
+type RegionalSignal interface {
+	Signal
+
+	// RegionSignalSummary describes the value in one line for a fish of the region, in place
+	// of SignalSummary
+	RegionSignalSummary(regionID string) string
+}
+
+// PriceSignal is a Signal that is also saved as price data
+type PriceSignal interface {
+	Signal
+
+	// PricePoint returns the asset name the price is saved under, the price in USD,
+	// the 24-hour change percentage and the 24-hour volume
+	PricePoint() (asset string, priceUSD, change24h, volume24h float64)
+}
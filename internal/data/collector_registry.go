@@ -0,0 +1,267 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CollectorConfig holds the settings of one named collector
+type CollectorConfig struct {
+	Enabled    bool
+	Interval   time.Duration
+	APIKey     string             // Credential of the collector's API; empty for public APIs
+	HTTP       HTTPOptions        // Base URL and transport of the collector's API; the real API when empty
+	Resilience ResilienceSettings // Retries and circuit breaker the collector runs behind
+}
+
+// CollectorFactory creates a collector from its settings
+type CollectorFactory func(config CollectorConfig) (DataCollector, error)
+
+// CollectorRegistry maps collector names to the factories that create them
+type CollectorRegistry struct {
+	names     []string // Registration order, which is also the order of the initial collection
+	factories map[string]CollectorFactory
+}
+
+// NewCollectorRegistry creates an empty registry
+func NewCollectorRegistry() *CollectorRegistry {
+	return &CollectorRegistry{factories: make(map[string]CollectorFactory)}
+}
+
+// DefaultCollectorRegistry returns a registry of the built-in collectors: weather, bitcoin,
+// gold, oil, astronomy and news. News is registered last so the first fish can use the other
+// sources.
+func DefaultCollectorRegistry() *CollectorRegistry {
+	registry := NewCollectorRegistry()
+	registry.Register("weather", func(config CollectorConfig) (DataCollector, error) {
+		return NewRegionWeatherCollectorWithHTTP(config.APIKey, PredefinedRegions(), config.HTTP), nil
+	})
+	registry.Register("bitcoin", func(config CollectorConfig) (DataCollector, error) {
+		return NewCryptoCollectorWithHTTP(config.HTTP), nil
+	})
+	registry.Register("gold", func(config CollectorConfig) (DataCollector, error) {
+		return NewGoldCollectorWithHTTP(config.APIKey, config.HTTP), nil
+	})
+	registry.Register("oil", func(config CollectorConfig) (DataCollector, error) {
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("the oil collector needs an EIA API key")
+		}
+		return NewOilPriceCollectorWithHTTP(config.APIKey, config.HTTP), nil
+	})
+	registry.Register("astronomy", func(config CollectorConfig) (DataCollector, error) {
+		return NewAstronomyCollector(PredefinedRegions()), nil
+	})
+	registry.Register("news", func(config CollectorConfig) (DataCollector, error) {
+		return NewNewsCollectorWithHTTP(config.APIKey, config.HTTP), nil
+	})
+	return registry
+}
+
+// Register adds a collector under a name, replacing any collector already registered under it
+func (r *CollectorRegistry) Register(name string, factory CollectorFactory) {
+	if _, exists := r.factories[name]; !exists {
+		r.names = append(r.names, name)
+	}
+	r.factories[name] = factory
+}
+
+// Names returns the registered collector names in registration order
+func (r *CollectorRegistry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Build creates the enabled collectors in registration order, each wrapped in a
+// ResilientCollector. Enabling a collector that is not registered is an error, so typos in
+// the configuration are not silently ignored.
+func (r *CollectorRegistry) Build(configs map[string]CollectorConfig) ([]ScheduledCollector, error) {
+	var unknown []string
+	for name, config := range configs {
+		if _, ok := r.factories[name]; config.Enabled && !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown collectors %s (available: %s)", strings.Join(unknown, ", "), strings.Join(r.names, ", "))
+	}
+
+	var collectors []ScheduledCollector
+	for _, name := range r.names {
+		config, ok := configs[name]
+		if !ok || !config.Enabled {
+			continue
+		}
+		if config.Interval <= 0 {
+			return nil, fmt.Errorf("collector %s needs a positive interval", name)
+		}
+		collector, err := r.factories[name](config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create collector %s: %v", name, err)
+		}
+		collectors = append(collectors, ScheduledCollector{
+			Name:      name,
+			Collector: NewResilientCollector(name, collector, config.Resilience),
+			Interval:  config.Interval,
+		})
+	}
+	return collectors, nil
+}
+
+// ScheduledCollector is a collector and the interval it runs at
+type ScheduledCollector struct {
+	Name      string
+	Collector DataCollector
+	Interval  time.Duration
+}
+
+// CollectorScheduler runs every collector on its own interval from a single goroutine and
+// publishes their events on one channel. A collector that is still running when it is due
+// again is skipped for that round.
+type CollectorScheduler struct {
+	collectors []ScheduledCollector
+	events     chan *DataEvent
+}
+
+// NewCollectorScheduler creates a scheduler for the collectors
+func NewCollectorScheduler(collectors []ScheduledCollector) *CollectorScheduler {
+	return &CollectorScheduler{
+		collectors: collectors,
+		events:     make(chan *DataEvent, 100),
+	}
+}
+
+// Events returns the channel the collected events are published on
+func (s *CollectorScheduler) Events() <-chan *DataEvent {
+	return s.events
+}
+
+// Collectors returns the scheduled collectors
+func (s *CollectorScheduler) Collectors() []ScheduledCollector {
+	return s.collectors
+}
+
+// Health returns the health of every collector that runs behind a ResilientCollector
+func (s *CollectorScheduler) Health() []CollectorHealth {
+	health := make([]CollectorHealth, 0, len(s.collectors))
+	for _, scheduled := range s.collectors {
+		if resilient, ok := scheduled.Collector.(*ResilientCollector); ok {
+			health = append(health, resilient.Health())
+		}
+	}
+	return health
+}
+
+// CollectNow runs every collector once, in order, and returns the events collected
+func (s *CollectorScheduler) CollectNow(ctx context.Context) []*DataEvent {
+	var events []*DataEvent
+	for _, scheduled := range s.collectors {
+		if event := s.collect(ctx, scheduled); event != nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Run collects from each collector whenever its interval has passed, starting one interval
+// from now, until the context is cancelled
+func (s *CollectorScheduler) Run(ctx context.Context) {
+	if len(s.collectors) == 0 {
+		return
+	}
+
+	start := time.Now()
+	next := make([]time.Time, len(s.collectors))
+	for i, scheduled := range s.collectors {
+		next[i] = start.Add(scheduled.Interval)
+	}
+	running := make([]bool, len(s.collectors))
+	done := make(chan int, len(s.collectors))
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		due := 0
+		for i := range next {
+			if next[i].Before(next[due]) {
+				due = i
+			}
+		}
+		timer := time.NewTimer(time.Until(next[due]))
+
+		select {
+		case <-timer.C:
+			now := time.Now()
+			for i, scheduled := range s.collectors {
+				if next[i].After(now) {
+					continue
+				}
+				next[i] = now.Add(scheduled.Interval)
+				if running[i] {
+					log.Printf("Collector %s is still running, skipping this round", scheduled.Name)
+					continue
+				}
+
+				running[i] = true
+				wg.Add(1)
+				go func(i int, scheduled ScheduledCollector) {
+					defer wg.Done()
+					if event := s.collect(ctx, scheduled); event != nil {
+						select {
+						case s.events <- event:
+						case <-ctx.Done():
+						}
+					}
+					done <- i
+				}(i, scheduled)
+			}
+
+		case i := <-done:
+			timer.Stop()
+			running[i] = false
+
+		case <-ctx.Done():
+			timer.Stop()
+			log.Println("Collector scheduler stopped")
+			return
+		}
+	}
+}
+
+// collect runs one collector, logging failures
+func (s *CollectorScheduler) collect(ctx context.Context, scheduled ScheduledCollector) *DataEvent {
+	event, err := scheduled.Collector.Collect(ctx)
+	if err != nil {
+		logError("Error collecting %s data: %v", scheduled.Name, err)
+		return nil
+	}
+	return event
+}
+
+// runCollector collects immediately and then on every tick of the interval, sending the
+// events to eventCh until the context is cancelled
+func runCollector(ctx context.Context, collector DataCollector, interval time.Duration, eventCh chan<- *DataEvent) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		event, err := collector.Collect(ctx)
+		if err == nil {
+			eventCh <- event
+		} else {
+			log.Printf("Error collecting %s data: %v", collector.GetType(), err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Printf("%s collector stopped", collector.GetType())
+			return
+		}
+	}
+}
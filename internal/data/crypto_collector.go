@@ -25,22 +25,29 @@ type CoinGeckoResponse struct {
 
 // CryptoCollector collects real cryptocurrency data from CoinGecko
 type CryptoCollector struct {
-	client  *http.Client
-	coinIDs []string // List of coin IDs to collect data for
+	client      *http.Client
+	coinIDs     []string // List of coin IDs to collect data for
+	httpOptions HTTPOptions
 }
 
 // NewCryptoCollector creates a new cryptocurrency data collector using the CoinGecko API
 func NewCryptoCollector() *CryptoCollector {
+	return NewCryptoCollectorWithHTTP(HTTPOptions{})
+}
+
+// NewCryptoCollectorWithHTTP creates a cryptocurrency data collector that makes its requests through the given options
+func NewCryptoCollectorWithHTTP(opts HTTPOptions) *CryptoCollector {
 	return &CryptoCollector{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		coinIDs: []string{"bitcoin", "ethereum", "ripple", "litecoin", "cardano"},
+		client:      opts.client(),
+		coinIDs:     []string{"bitcoin", "ethereum", "ripple", "litecoin", "cardano"},
+		httpOptions: opts,
 	}
 }
 
 // Collect retrieves real cryptocurrency data from CoinGecko API
 func (c *CryptoCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	// We'll focus on Bitcoin for this implementation
-	url := "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=bitcoin&order=market_cap_desc&per_page=1&page=1&sparkline=false&price_change_percentage=24h%2C7d"
+	url := c.httpOptions.url("https://api.coingecko.com", "/api/v3/coins/markets?vs_currency=usd&ids=bitcoin&order=market_cap_desc&per_page=1&page=1&sparkline=false&price_change_percentage=24h%2C7d")
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -54,13 +61,13 @@ func (c *CryptoCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	// Make the request
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Parse the response
@@ -0,0 +1,269 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Counters kept per experiment variant
+const (
+	ExperimentCounterGenerations   = "generations"    // Fish generations that got an answer from the model
+	ExperimentCounterParseFailures = "parse_failures" // Generations whose answer never passed validation
+	ExperimentCounterCatches       = "catches"        // Catches of the variant's fish through the fishing API
+)
+
+// promptTemplateNamePattern restricts variant template names to plain file names
+var promptTemplateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// PromptVariant is one arm of a prompt experiment
+type PromptVariant struct {
+	Name     string `json:"name"`
+	Template string `json:"template,omitempty"` // Prompt template, e.g. "fish_from_context.mythic"; the default template when empty
+	Weight   int    `json:"weight"`             // Relative share of generations
+}
+
+// PromptExperiment splits fish generated from merged news and context across prompt variants
+type PromptExperiment struct {
+	Name     string          `json:"name"`
+	Enabled  bool            `json:"enabled"`
+	Variants []PromptVariant `json:"variants"`
+}
+
+// ExperimentVariantCounts holds the counters of one experiment variant
+type ExperimentVariantCounts struct {
+	Experiment    string `bson:"experiment" json:"experiment"`
+	Variant       string `bson:"variant" json:"variant"`
+	Generations   int    `bson:"generations" json:"generations"`
+	ParseFailures int    `bson:"parse_failures" json:"parse_failures"`
+	Catches       int    `bson:"catches" json:"catches"`
+}
+
+// Increment adds one to an ExperimentCounter* counter
+func (c *ExperimentVariantCounts) Increment(counter string) error {
+	switch counter {
+	case ExperimentCounterGenerations:
+		c.Generations++
+	case ExperimentCounterParseFailures:
+		c.ParseFailures++
+	case ExperimentCounterCatches:
+		c.Catches++
+	default:
+		return fmt.Errorf("unknown experiment counter %q", counter)
+	}
+	return nil
+}
+
+// IsExperimentCounter reports whether counter is one of the ExperimentCounter* names
+func IsExperimentCounter(counter string) bool {
+	return (&ExperimentVariantCounts{}).Increment(counter) == nil
+}
+
+// ExperimentStore persists experiment counters
+type ExperimentStore interface {
+	// IncrementExperimentCounter adds one to an ExperimentCounter* counter of a variant
+	IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error
+	// GetExperimentCounts returns the counters of every variant of an experiment
+	GetExperimentCounts(ctx context.Context, experiment string) ([]ExperimentVariantCounts, error)
+}
+
+// ExperimentAssignment is the variant a generation was assigned to
+type ExperimentAssignment struct {
+	Experiment string
+	Variant    string
+	Template   string // Prompt template to render
+}
+
+// PromptExperiments assigns fish generations to experiment variants and records their outcome.
+// A nil PromptExperiments runs no experiment.
+type PromptExperiments struct {
+	experiments []PromptExperiment
+	store       ExperimentStore
+	rng         *rand.Rand
+	mu          sync.Mutex
+}
+
+// LoadPromptExperiments reads experiment definitions from a JSON file holding an array of experiments
+func LoadPromptExperiments(path string) ([]PromptExperiment, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiments file: %v", err)
+	}
+
+	var experiments []PromptExperiment
+	if err := json.Unmarshal(contents, &experiments); err != nil {
+		return nil, fmt.Errorf("failed to parse experiments file %s: %v", path, err)
+	}
+
+	names := make(map[string]bool)
+	for _, experiment := range experiments {
+		if err := experiment.Validate(); err != nil {
+			return nil, err
+		}
+		if names[experiment.Name] {
+			return nil, fmt.Errorf("experiment %q is defined twice", experiment.Name)
+		}
+		names[experiment.Name] = true
+	}
+
+	return experiments, nil
+}
+
+// Validate checks that the experiment has a name and uniquely named variants with positive weights
+func (e PromptExperiment) Validate() error {
+	if e.Name == "" {
+		return errors.New("experiment name is required")
+	}
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("experiment %q needs at least two variants", e.Name)
+	}
+
+	names := make(map[string]bool)
+	for _, variant := range e.Variants {
+		if variant.Name == "" {
+			return fmt.Errorf("experiment %q has a variant without a name", e.Name)
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("experiment %q has two variants named %q", e.Name, variant.Name)
+		}
+		names[variant.Name] = true
+
+		if variant.Weight <= 0 {
+			return fmt.Errorf("variant %q of experiment %q needs a positive weight", variant.Name, e.Name)
+		}
+		if variant.Template != "" && !promptTemplateNamePattern.MatchString(variant.Template) {
+			return fmt.Errorf("variant %q of experiment %q has an invalid template name %q", variant.Name, e.Name, variant.Template)
+		}
+	}
+	return nil
+}
+
+// NewPromptExperiments runs the enabled experiments and records their counters in store.
+// Only the first enabled experiment assigns variants, since a prompt can use one template at a time.
+func NewPromptExperiments(experiments []PromptExperiment, store ExperimentStore) *PromptExperiments {
+	return &PromptExperiments{
+		experiments: experiments,
+		store:       store,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Definitions returns every configured experiment
+func (p *PromptExperiments) Definitions() []PromptExperiment {
+	if p == nil {
+		return nil
+	}
+	return p.experiments
+}
+
+// Active returns the experiment that currently assigns variants
+func (p *PromptExperiments) Active() (PromptExperiment, bool) {
+	if p == nil {
+		return PromptExperiment{}, false
+	}
+	for _, experiment := range p.experiments {
+		if experiment.Enabled {
+			return experiment, true
+		}
+	}
+	return PromptExperiment{}, false
+}
+
+// Assign picks a variant of the active experiment at random by weight.
+// Without an active experiment it returns the default template and no experiment.
+func (p *PromptExperiments) Assign() ExperimentAssignment {
+	assignment := ExperimentAssignment{Template: LLMTaskFishFromContext}
+
+	experiment, ok := p.Active()
+	if !ok {
+		return assignment
+	}
+
+	total := 0
+	for _, variant := range experiment.Variants {
+		total += variant.Weight
+	}
+
+	p.mu.Lock()
+	roll := p.rng.Intn(total)
+	p.mu.Unlock()
+
+	for _, variant := range experiment.Variants {
+		if roll < variant.Weight {
+			assignment.Experiment = experiment.Name
+			assignment.Variant = variant.Name
+			if variant.Template != "" {
+				assignment.Template = variant.Template
+			}
+			break
+		}
+		roll -= variant.Weight
+	}
+	return assignment
+}
+
+// RecordGeneration counts a generation of an assigned variant. Generations that
+// never reached the model, such as provider errors, are not counted.
+func (p *PromptExperiments) RecordGeneration(ctx context.Context, assignment ExperimentAssignment, err error) {
+	if p == nil || assignment.Experiment == "" {
+		return
+	}
+
+	parseFailure := false
+	if err != nil {
+		var genErr *FishGenerationError
+		if !errors.As(err, &genErr) {
+			return
+		}
+		switch genErr.Kind {
+		case FishErrorNoJSON, FishErrorMalformedJSON, FishErrorSchemaViolation:
+			parseFailure = true
+		default:
+			return
+		}
+	}
+
+	p.increment(ctx, assignment.Experiment, assignment.Variant, ExperimentCounterGenerations)
+	if parseFailure {
+		p.increment(ctx, assignment.Experiment, assignment.Variant, ExperimentCounterParseFailures)
+	}
+}
+
+// increment adds one to a counter, logging failures
+func (p *PromptExperiments) increment(ctx context.Context, experiment, variant, counter string) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.IncrementExperimentCounter(ctx, experiment, variant, counter); err != nil {
+		log.Printf("Warning: failed to record %s of experiment %s variant %s: %v", counter, experiment, variant, err)
+	}
+}
+
+// RecordCatch counts a catch of a fish generated in an experiment through the fishing API.
+// Catches are counted even after the experiment is disabled, so its results stay complete.
+func (p *PromptExperiments) RecordCatch(ctx context.Context, fish *FishRecord) {
+	if p == nil || fish == nil || fish.Experiment == "" {
+		return
+	}
+	p.increment(ctx, fish.Experiment, fish.Variant, ExperimentCounterCatches)
+}
+
+// Templates returns the prompt templates used by the variants of every experiment
+func (p *PromptExperiments) Templates() []string {
+	var templates []string
+	for _, experiment := range p.Definitions() {
+		for _, variant := range experiment.Variants {
+			if variant.Template != "" {
+				templates = append(templates, variant.Template)
+			}
+		}
+	}
+	return templates
+}
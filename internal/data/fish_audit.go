@@ -0,0 +1,29 @@
+package data
+
+import "time"
+
+// Admin actions recorded in the fish audit trail
+const (
+	FishAuditApprove = "approve" // A draft or quarantined fish was published
+	FishAuditReject  = "reject"  // A draft or quarantined fish was retired without being published
+	FishAuditEdit    = "edit"    // Text fields were changed
+	FishAuditRetire  = "retire"  // A published fish was withdrawn
+)
+
+// FishFieldChange is the old and new value of one field changed by an admin
+type FishFieldChange struct {
+	Field string `bson:"field" json:"field"` // JSON name of the field
+	Old   string `bson:"old" json:"old"`
+	New   string `bson:"new" json:"new"`
+}
+
+// FishAuditEntry records an admin action on a fish
+type FishAuditEntry struct {
+	FishID   string            `bson:"fish_id" json:"fish_id"`
+	FishName string            `bson:"fish_name" json:"fish_name"` // Name after the action
+	Admin    string            `bson:"admin" json:"admin"`
+	Action   string            `bson:"action" json:"action"` // One of the FishAudit* actions
+	Changes  []FishFieldChange `bson:"changes" json:"changes"`
+	Reason   string            `bson:"reason,omitempty" json:"reason,omitempty"`
+	At       time.Time         `bson:"at" json:"at"`
+}
@@ -0,0 +1,163 @@
+package data
+
+import "time"
+
+// Publish statuses of a fish. Only published fish can be caught or appear in the catalog.
+const (
+	FishStatusDraft       = "draft"       // Generated and waiting for an admin to review it
+	FishStatusQuarantined = "quarantined" // Held back by content moderation
+	FishStatusPublished   = "published"
+	FishStatusRetired     = "retired" // Withdrawn by an admin
+)
+
+// ValidFishStatus reports whether status is one of the FishStatus* values
+func ValidFishStatus(status string) bool {
+	switch status {
+	case FishStatusDraft, FishStatusQuarantined, FishStatusPublished, FishStatusRetired:
+		return true
+	default:
+		return false
+	}
+}
+
+// FishRecord is the canonical fish species record. The generator builds it,
+// storage persists it unchanged and the API returns it, so every field the
+// AI produces survives a round-trip.
+type FishRecord struct {
+	ID               string           `bson:"-" json:"id"` // Hex ObjectID assigned when the fish is first saved
+	Name             string           `bson:"name" json:"name"`
+	Description      string           `bson:"description" json:"description"`
+	Appearance       string           `bson:"appearance,omitempty" json:"appearance,omitempty"`
+	Rarity           string           `bson:"rarity" json:"rarity"`
+	Length           float64          `bson:"length" json:"length"` // in meters
+	Weight           float64          `bson:"weight" json:"weight"` // in kilograms
+	Value            float64          `bson:"value" json:"value"`   // in USD
+	Color            string           `bson:"color" json:"color"`
+	Habitat          string           `bson:"habitat" json:"habitat"`
+	Diet             string           `bson:"diet" json:"diet"`
+	Effect           string           `bson:"effect" json:"effect"` // Human-readable effect description
+	FavoriteWeather  string           `bson:"favorite_weather" json:"favorite_weather"`
+	CatchChance      float64          `bson:"catch_chance" json:"catch_chance"` // percentage, 0-100
+	ExistenceReason  string           `bson:"existence_reason" json:"existence_reason"`
+	OriginContext    string           `bson:"origin_context,omitempty" json:"origin_context,omitempty"`
+	GeneratedAt      time.Time        `bson:"generated_at" json:"generated_at"`
+	IsAIGenerated    bool             `bson:"is_ai_generated" json:"is_ai_generated"`
+	DataSource       string           `bson:"data_source" json:"data_source"`
+	RegionID         string           `bson:"region_id,omitempty" json:"region_id,omitempty"`
+	GenerationReason string           `bson:"generation_reason,omitempty" json:"generation_reason,omitempty"`
+	StatEffects      []FishStatEffect `bson:"stat_effects,omitempty" json:"stat_effects"`
+	UsedArticles     []UsedArticle    `bson:"used_articles,omitempty" json:"used_articles"`
+	PromptVersion    string           `bson:"prompt_version,omitempty" json:"prompt_version,omitempty"`         // Prompt template of AI fish, e.g. "fish_from_context@3"
+	Experiment       string           `bson:"experiment,omitempty" json:"experiment,omitempty"`                 // Prompt experiment the fish was generated in
+	Variant          string           `bson:"experiment_variant,omitempty" json:"experiment_variant,omitempty"` // Variant of the experiment that generated the fish
+	VariantOf        string           `bson:"variant_of,omitempty" json:"variant_of,omitempty"`                 // ID of the existing species this fish duplicates
+	Status           string           `bson:"status" json:"status"`                                             // One of the FishStatus* values; saved as published when empty
+	ModerationFlags  []ModerationFlag `bson:"moderation_flags,omitempty" json:"moderation_flags,omitempty"`     // Why content moderation quarantined the fish
+}
+
+// FishStatEffect is a single gameplay effect of a fish.
+// AI fish use EffectType/Modifier; rule-based fish also fill in Stat, Value, IsPercent and Duration.
+type FishStatEffect struct {
+	EffectType  string  `bson:"effect_type" json:"effect_type"` // "environment" or "player"
+	Modifier    float64 `bson:"modifier" json:"modifier"`
+	Description string  `bson:"description,omitempty" json:"description,omitempty"`
+	WeatherType string  `bson:"weather_type,omitempty" json:"weather_type,omitempty"`
+	Stat        string  `bson:"stat,omitempty" json:"stat,omitempty"`
+	Value       float64 `bson:"value,omitempty" json:"value,omitempty"`
+	IsPercent   bool    `bson:"is_percentage,omitempty" json:"is_percentage,omitempty"`
+	Duration    int     `bson:"duration,omitempty" json:"duration,omitempty"` // in seconds
+}
+
+// UsedArticle records a news article that inspired a fish
+type UsedArticle struct {
+	Headline  string    `bson:"headline" json:"headline"`
+	Source    string    `bson:"source" json:"source"`
+	URL       string    `bson:"url" json:"url"`
+	Category  string    `bson:"category" json:"category"`
+	Sentiment float64   `bson:"sentiment" json:"sentiment"`
+	Keywords  []string  `bson:"keywords" json:"keywords"`
+	Published time.Time `bson:"published" json:"published"`
+	UsedAt    time.Time `bson:"used_at" json:"used_at"`
+	IsMerged  bool      `bson:"is_merged" json:"is_merged"`
+}
+
+// NewUsedArticle records a news item as used for fish generation at the given time
+func NewUsedArticle(news *NewsItem, usedAt time.Time, isMerged bool) UsedArticle {
+	return UsedArticle{
+		Headline:  news.Headline,
+		Source:    news.Source,
+		URL:       news.URL,
+		Category:  news.Category,
+		Sentiment: news.Sentiment,
+		Keywords:  news.Keywords,
+		Published: news.PublishedAt,
+		UsedAt:    usedAt,
+		IsMerged:  isMerged,
+	}
+}
+
+// RarityRank orders rarity levels from Common (1) to Legendary (5); unknown levels rank 0
+func RarityRank(rarity string) int {
+	switch rarity {
+	case "Common":
+		return 1
+	case "Uncommon":
+		return 2
+	case "Rare":
+		return 3
+	case "Epic":
+		return 4
+	case "Legendary":
+		return 5
+	default:
+		return 0
+	}
+}
+
+// RarityValueMultiplier returns the value multiplier for a rarity level
+func RarityValueMultiplier(rarity string) float64 {
+	switch rarity {
+	case "Uncommon":
+		return 3
+	case "Rare":
+		return 6
+	case "Epic":
+		return 12
+	case "Legendary":
+		return 25
+	default:
+		return 1
+	}
+}
+
+// Sanitize replaces invalid UTF-8 in every text field of the record
+func (f *FishRecord) Sanitize() {
+	for _, field := range []*string{
+		&f.Name, &f.Description, &f.Appearance, &f.Rarity, &f.Color, &f.Habitat, &f.Diet,
+		&f.Effect, &f.FavoriteWeather, &f.ExistenceReason, &f.OriginContext, &f.DataSource,
+		&f.RegionID, &f.GenerationReason,
+	} {
+		*field = SanitizeUTF8(*field)
+	}
+
+	for i := range f.StatEffects {
+		effect := &f.StatEffects[i]
+		effect.EffectType = SanitizeUTF8(effect.EffectType)
+		effect.Description = SanitizeUTF8(effect.Description)
+		effect.WeatherType = SanitizeUTF8(effect.WeatherType)
+		effect.Stat = SanitizeUTF8(effect.Stat)
+	}
+
+	for i := range f.UsedArticles {
+		article := &f.UsedArticles[i]
+		article.Headline = SanitizeUTF8(article.Headline)
+		article.Source = SanitizeUTF8(article.Source)
+		article.URL = SanitizeUTF8(article.URL)
+		article.Category = SanitizeUTF8(article.Category)
+		keywords := make([]string, len(article.Keywords))
+		for j, keyword := range article.Keywords {
+			keywords[j] = SanitizeUTF8(keyword)
+		}
+		article.Keywords = keywords
+	}
+}
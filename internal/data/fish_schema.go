@@ -0,0 +1,317 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultFishRepairAttempts is how many corrective re-prompts are sent after an invalid fish response
+const DefaultFishRepairAttempts = 2
+
+// Kinds of fish generation failure reported by FishGenerationError
+const (
+	FishErrorProvider        = "provider"         // The provider request failed
+	FishErrorNoJSON          = "no_json"          // The response contained no JSON object
+	FishErrorMalformedJSON   = "malformed_json"   // The JSON object could not be decoded
+	FishErrorSchemaViolation = "schema_violation" // The JSON object did not satisfy the schema
+	FishErrorBudgetExhausted = "budget_exhausted" // The daily token budget was spent before the call
+)
+
+// FishGenerationError explains why the model could not produce a valid fish
+type FishGenerationError struct {
+	Kind       string   // One of the FishError* kinds, for the last attempt
+	Attempts   int      // Number of model calls made
+	Violations []string // Schema violations of the last response
+	Response   string   // Last raw response from the model
+	Err        error    // Underlying provider or decoding error
+}
+
+// Error describes the failure
+func (e *FishGenerationError) Error() string {
+	msg := fmt.Sprintf("fish generation failed (%s) after %d attempt(s)", e.Kind, e.Attempts)
+	if len(e.Violations) > 0 {
+		msg += ": " + strings.Join(e.Violations, "; ")
+	} else if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *FishGenerationError) Unwrap() error {
+	return e.Err
+}
+
+// FishSchemaField declares the constraints on one field of a fish response
+type FishSchemaField struct {
+	Name        string   // JSON key
+	Type        string   // "string" or "number"
+	Description string   // Shown to the model in the schema
+	Required    bool     // The field must be present and non-empty
+	MinLength   int      // Minimum length of a string, in characters
+	MaxLength   int      // Maximum length of a string, in characters
+	Enum        []string // Allowed string values, compared case-insensitively
+	Minimum     float64  // Minimum of a number
+	Maximum     float64  // Maximum of a number; zero means no range check
+}
+
+// FishResponseSchema declares the JSON object a fish prompt asks the model for.
+// Keys that are not declared are ignored.
+type FishResponseSchema struct {
+	Title  string
+	Fields []FishSchemaField
+}
+
+// NewsFishSchema is the response expected for a fish generated from a single news item
+var NewsFishSchema = FishResponseSchema{
+	Title: "NewsFish",
+	Fields: []FishSchemaField{
+		{Name: "name", Type: "string", Description: "A unique and creative name for the fish species", Required: true, MinLength: 3, MaxLength: 60},
+		{Name: "description", Type: "string", Description: "A short description of the fish, including any relevant traits or abilities", Required: true, MinLength: 20, MaxLength: 600},
+		{Name: "appearance", Type: "string", Description: "A vivid description of the fish's physical appearance", Required: true, MinLength: 10, MaxLength: 400},
+		{Name: "effect", Type: "string", Description: "A gameplay effect that the fish provides to the player", Required: true, MinLength: 5, MaxLength: 300},
+		{Name: "rarity", Type: "string", Description: "How rare the fish is", Required: true, Enum: []string{"Common", "Uncommon", "Rare", "Epic", "Legendary"}},
+		{Name: "size", Type: "number", Description: "Size of the fish in meters", Required: true, Minimum: 0.1, Maximum: 3.0},
+		{Name: "size_units", Type: "string", Description: "Unit of the size", Enum: []string{"meters"}},
+		{Name: "value", Type: "number", Description: "Market value of the fish in USD", Required: true, Minimum: 5, Maximum: 10000},
+	},
+}
+
+// ContextFishSchema is the response expected for a fish generated from merged news, weather and prices.
+// Rarity, size and value are generated programmatically, so they are not part of it.
+var ContextFishSchema = FishResponseSchema{
+	Title: "ContextFish",
+	Fields: []FishSchemaField{
+		{Name: "name", Type: "string", Description: "The fish's creative name", Required: true, MinLength: 3, MaxLength: 60},
+		{Name: "description", Type: "string", Description: "Detailed, imaginative description", Required: true, MinLength: 20, MaxLength: 600},
+		{Name: "appearance", Type: "string", Description: "Physical characteristics and notable features", Required: true, MinLength: 10, MaxLength: 400},
+		{Name: "color", Type: "string", Description: "Primary colors and patterns", Required: true, MinLength: 3, MaxLength: 120},
+		{Name: "diet", Type: "string", Description: "What the fish eats", Required: true, MinLength: 3, MaxLength: 200},
+		{Name: "habitat", Type: "string", Description: "Where the fish lives", Required: true, MinLength: 3, MaxLength: 200},
+		{Name: "effect", Type: "string", Description: "Special quality or effect", Required: true, MinLength: 5, MaxLength: 300},
+		{Name: "favorite_weather", Type: "string", Description: "Weather condition this fish prefers", Required: true, MinLength: 3, MaxLength: 60},
+		{Name: "existence_reason", Type: "string", Description: "Brief explanation of why this fish evolved or exists", Required: true, MinLength: 10, MaxLength: 400},
+	},
+}
+
+// FishNameSchema is the response expected when a fish is renamed because its name is taken
+var FishNameSchema = FishResponseSchema{
+	Title: "FishName",
+	Fields: []FishSchemaField{
+		{Name: "name", Type: "string", Description: "The fish's new name", Required: true, MinLength: 3, MaxLength: 60},
+	},
+}
+
+// FishModerationSchema is the response expected when the model reviews a fish for tasteless content
+var FishModerationSchema = FishResponseSchema{
+	Title: "FishModeration",
+	Fields: []FishSchemaField{
+		{Name: "verdict", Type: "string", Description: "Whether the fish is fit to publish", Required: true, Enum: []string{ContentVerdictSafe, ContentVerdictUnsafe}},
+		{Name: "reason", Type: "string", Description: "Why the fish is unsafe; empty when it is safe", MaxLength: 300},
+	},
+}
+
+// JSON renders the schema as a JSON Schema document for use in prompts
+func (s FishResponseSchema) JSON() string {
+	properties := make(map[string]interface{}, len(s.Fields))
+	required := []string{}
+
+	for _, field := range s.Fields {
+		property := map[string]interface{}{
+			"type":        field.Type,
+			"description": field.Description,
+		}
+		if field.MinLength > 0 {
+			property["minLength"] = field.MinLength
+		}
+		if field.MaxLength > 0 {
+			property["maxLength"] = field.MaxLength
+		}
+		if len(field.Enum) > 0 {
+			property["enum"] = field.Enum
+		}
+		if field.Maximum > 0 {
+			property["minimum"] = field.Minimum
+			property["maximum"] = field.Maximum
+		}
+		properties[field.Name] = property
+
+		if field.Required {
+			required = append(required, field.Name)
+		}
+	}
+
+	schema, _ := json.MarshalIndent(map[string]interface{}{
+		"title":      s.Title,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, "", "  ")
+	return string(schema)
+}
+
+// Parse extracts the fish from a model response and validates it against the schema.
+// The returned error is a *FishGenerationError with Attempts left at zero.
+func (s FishResponseSchema) Parse(response string) (*FishGenerationResponse, error) {
+	var fish FishGenerationResponse
+	if err := s.Decode(response, &fish); err != nil {
+		return nil, err
+	}
+	return &fish, nil
+}
+
+// Decode extracts the JSON object from a model response, validates it against the schema
+// and decodes it into out. The returned error is a *FishGenerationError with Attempts left at zero.
+func (s FishResponseSchema) Decode(response string, out interface{}) error {
+	// Remove Markdown code block markers if present
+	text := strings.ReplaceAll(response, "```json", "")
+	text = strings.ReplaceAll(text, "```", "")
+	text = strings.TrimSpace(text)
+
+	// Extract JSON object from response (in case there's any text before or after)
+	jsonStart := strings.Index(text, "{")
+	jsonEnd := strings.LastIndex(text, "}")
+	if jsonStart == -1 || jsonEnd <= jsonStart {
+		return &FishGenerationError{
+			Kind:       FishErrorNoJSON,
+			Violations: []string{"the response must be a single JSON object"},
+			Response:   response,
+			Err:        errors.New("response does not contain a JSON object"),
+		}
+	}
+	jsonStr := text[jsonStart : jsonEnd+1]
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return &FishGenerationError{
+			Kind:       FishErrorMalformedJSON,
+			Violations: []string{fmt.Sprintf("the response is not valid JSON: %v", err)},
+			Response:   response,
+			Err:        err,
+		}
+	}
+
+	if violations := s.Validate(raw); len(violations) > 0 {
+		return &FishGenerationError{
+			Kind:       FishErrorSchemaViolation,
+			Violations: violations,
+			Response:   response,
+		}
+	}
+
+	// Types were checked above, so decoding into the struct cannot fail on them
+	normalized, _ := json.Marshal(raw)
+	if err := json.Unmarshal(normalized, out); err != nil {
+		return &FishGenerationError{
+			Kind:       FishErrorMalformedJSON,
+			Violations: []string{fmt.Sprintf("the response could not be decoded: %v", err)},
+			Response:   response,
+			Err:        err,
+		}
+	}
+	return nil
+}
+
+// Validate checks a decoded JSON object against the schema and returns one message per violation.
+// String values are trimmed and enum values are rewritten to their declared spelling.
+func (s FishResponseSchema) Validate(raw map[string]interface{}) []string {
+	var violations []string
+
+	for _, field := range s.Fields {
+		value, present := raw[field.Name]
+		if !present || value == nil {
+			if field.Required {
+				violations = append(violations, fmt.Sprintf("%q is required", field.Name))
+			}
+			continue
+		}
+
+		switch field.Type {
+		case "string":
+			str, ok := value.(string)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%q must be a string", field.Name))
+				continue
+			}
+			str, problems := field.validateString(str)
+			raw[field.Name] = str
+			violations = append(violations, problems...)
+
+		case "number":
+			number, ok := value.(float64)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%q must be a JSON number, not %s", field.Name, describeJSONType(value)))
+				continue
+			}
+			if field.Maximum > 0 && (number < field.Minimum || number > field.Maximum) {
+				violations = append(violations, fmt.Sprintf("%q must be between %g and %g (got %g)",
+					field.Name, field.Minimum, field.Maximum, number))
+			}
+		}
+	}
+
+	return violations
+}
+
+// validateString checks a string value against the field's constraints and returns it
+// trimmed, with enum values in their declared spelling
+func (f FishSchemaField) validateString(value string) (string, []string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if f.Required {
+			return value, []string{fmt.Sprintf("%q must not be empty", f.Name)}
+		}
+		return value, nil
+	}
+
+	if len(f.Enum) > 0 {
+		for _, allowed := range f.Enum {
+			if strings.EqualFold(value, allowed) {
+				return allowed, nil
+			}
+		}
+		return value, []string{fmt.Sprintf("%q must be one of %s (got %q)", f.Name, strings.Join(f.Enum, ", "), value)}
+	}
+
+	length := utf8.RuneCountInString(value)
+	if f.MinLength > 0 && length < f.MinLength {
+		return value, []string{fmt.Sprintf("%q must be at least %d characters (got %d)", f.Name, f.MinLength, length)}
+	}
+	if f.MaxLength > 0 && length > f.MaxLength {
+		return value, []string{fmt.Sprintf("%q must be at most %d characters (got %d)", f.Name, f.MaxLength, length)}
+	}
+	return value, nil
+}
+
+// describeJSONType names the JSON type of a decoded value for violation messages
+func describeJSONType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// buildFishRepairPrompt asks the model to correct its previous response
+func buildFishRepairPrompt(prompt, previous string, schema FishResponseSchema, violations []string) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nYour previous response was rejected.\n\nPREVIOUS RESPONSE:\n")
+	b.WriteString(truncateString(strings.TrimSpace(previous), 4000))
+	b.WriteString("\n\nPROBLEMS:\n")
+	for _, violation := range violations {
+		b.WriteString("- " + violation + "\n")
+	}
+	b.WriteString(fmt.Sprintf("\nReturn ONLY a corrected JSON object that satisfies the %s JSON Schema above, with no additional text.\n", schema.Title))
+	return b.String()
+}
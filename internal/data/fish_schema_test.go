@@ -0,0 +1,70 @@
+package data
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFishResponseSchemaDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		kind      string // Empty when the response is valid
+		violation string // Part of one of the violations
+	}{
+		{"valid", validNewsFish, "", ""},
+		{"code block", "Here you go:\n```json\n" + validNewsFish + "\n```", "", ""},
+		{"no json", "I cannot help with that.", FishErrorNoJSON, "single JSON object"},
+		{"malformed", `{"name": "Broken", "size": }`, FishErrorMalformedJSON, "not valid JSON"},
+		{"missing field", `{"name": "Lonely Loach"}`, FishErrorSchemaViolation, `"description" is required`},
+		{"out of range", strings.Replace(validNewsFish, `"size": 0.4`, `"size": 12`, 1), FishErrorSchemaViolation, `"size" must be between`},
+		{"wrong type", strings.Replace(validNewsFish, `"value": 120`, `"value": "120 USD"`, 1), FishErrorSchemaViolation, `"value" must be a JSON number, not a string`},
+		{"bad enum", strings.Replace(validNewsFish, `"rare"`, `"mythic"`, 1), FishErrorSchemaViolation, `"rarity" must be one of`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fish, err := NewsFishSchema.Parse(tt.response)
+			if tt.kind == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if fish.Name != "Headline Herring" || fish.Rarity != "Rare" || fish.Size != 0.4 {
+					t.Errorf("Parse() = %+v, want the normalized fish", fish)
+				}
+				return
+			}
+
+			var genErr *FishGenerationError
+			if !errors.As(err, &genErr) || genErr.Kind != tt.kind {
+				t.Fatalf("Parse() error = %v, want %s", err, tt.kind)
+			}
+			if !strings.Contains(strings.Join(genErr.Violations, "\n"), tt.violation) {
+				t.Errorf("violations = %q, want one containing %q", genErr.Violations, tt.violation)
+			}
+		})
+	}
+}
+
+func TestFishResponseSchemaDecodeReportsUnmarshalError(t *testing.T) {
+	// The schema accepts the response, but it cannot be decoded into this type
+	var out struct {
+		Name int `json:"name"`
+	}
+	err := NewsFishSchema.Decode(validNewsFish, &out)
+
+	var genErr *FishGenerationError
+	if !errors.As(err, &genErr) || genErr.Kind != FishErrorMalformedJSON || genErr.Err == nil {
+		t.Fatalf("Decode() error = %v, want %s with the unmarshal error", err, FishErrorMalformedJSON)
+	}
+	if len(genErr.Violations) != 1 || !strings.Contains(genErr.Violations[0], "could not be decoded") {
+		t.Errorf("violations = %q, want the unmarshal error", genErr.Violations)
+	}
+
+	// The repair prompt tells the model what went wrong
+	repair := buildFishRepairPrompt("prompt", validNewsFish, NewsFishSchema, genErr.Violations)
+	if !strings.Contains(repair, "- the response could not be decoded") {
+		t.Errorf("repair prompt has no problems listed:\n%s", repair)
+	}
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFishValidOnFirstTry(t *testing.T) {
+	ctx := context.Background()
+	scripted := NewScriptedProvider(validNewsFish)
+	usage := &memoryLLMUsage{}
+	client := NewGeminiClientWithLLM(LLMTask{Provider: scripted, Usage: NewLLMUsageTracker(usage, LLMUsageSettings{})}.
+		WithDefaults(LLMTaskFishFromNews, ""))
+
+	fish, err := client.GenerateFishFromNews(ctx, &NewsItem{Headline: "Markets rally", Category: "business"})
+	if err != nil {
+		t.Fatalf("GenerateFishFromNews() error = %v", err)
+	}
+	if fish.Name != "Headline Herring" || fish.PromptVersion == "" {
+		t.Errorf("fish = %+v, want Headline Herring with a prompt version", fish)
+	}
+	if got := len(scripted.Prompts()); got != 1 {
+		t.Errorf("provider calls = %d, want 1", got)
+	}
+	if got := usage.outcomes(); len(got) != 1 || got[0] != LLMOutcomeSuccess {
+		t.Errorf("outcomes = %v, want [%s]", got, LLMOutcomeSuccess)
+	}
+}
+
+func TestGenerateFishRepairedOnLaterAttempt(t *testing.T) {
+	ctx := context.Background()
+	scripted := NewScriptedProvider(
+		"Sorry, here is a fish: Headline Herring",
+		strings.Replace(validNewsFish, `"size": 0.4`, `"size": 12`, 1),
+		validNewsFish,
+	)
+	usage := &memoryLLMUsage{}
+	client := NewGeminiClientWithLLM(LLMTask{Provider: scripted, Usage: NewLLMUsageTracker(usage, LLMUsageSettings{})}.
+		WithDefaults(LLMTaskFishFromNews, ""))
+
+	fish, err := client.GenerateFishFromNews(ctx, &NewsItem{Headline: "Markets rally", Category: "business"})
+	if err != nil {
+		t.Fatalf("GenerateFishFromNews() error = %v", err)
+	}
+	if fish.Size != 0.4 {
+		t.Errorf("fish size = %g, want the repaired 0.4", fish.Size)
+	}
+
+	prompts := scripted.Prompts()
+	if len(prompts) != 3 {
+		t.Fatalf("provider calls = %d, want 3", len(prompts))
+	}
+	// Each repair prompt repeats the original prompt with the previous response and its problems
+	for i, want := range []string{"single JSON object", `"size" must be between`} {
+		repair := prompts[i+1]
+		if !strings.HasPrefix(repair, prompts[0]) {
+			t.Errorf("repair prompt %d does not extend the original prompt", i+1)
+		}
+		if !strings.Contains(repair, "PROBLEMS:") || !strings.Contains(repair, want) {
+			t.Errorf("repair prompt %d does not list %q", i+1, want)
+		}
+	}
+	want := []string{LLMOutcomeInvalid, LLMOutcomeInvalid, LLMOutcomeSuccess}
+	if got := usage.outcomes(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateFishAttemptsExhausted(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   func(p *ScriptedProvider)
+		budget   int // Daily token budget, spent before the call when positive
+		kind     string
+		attempts int
+	}{
+		{"no json", func(p *ScriptedProvider) {
+			p.Reply("no fish today").Reply("still no fish").Reply("none at all")
+		}, 0, FishErrorNoJSON, 3},
+		{"malformed json", func(p *ScriptedProvider) {
+			p.Reply(`{"name": }`).Reply(`{"name": }`).Reply(`{"name": }`)
+		}, 0, FishErrorMalformedJSON, 3},
+		{"schema violation", func(p *ScriptedProvider) {
+			p.Reply(`{"name": "Lonely Loach"}`).Reply(`{"name": "Lonely Loach"}`).Reply(`{"name": "Lonely Loach"}`)
+		}, 0, FishErrorSchemaViolation, 3},
+		{"provider error after repair", func(p *ScriptedProvider) {
+			p.Reply("no fish today").Fail(errors.New("service unavailable"))
+		}, 0, FishErrorProvider, 2},
+		{"budget exhausted", func(p *ScriptedProvider) {}, 10, FishErrorBudgetExhausted, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scripted := NewScriptedProvider()
+			tt.script(scripted)
+
+			tracker := NewLLMUsageTracker(&memoryLLMUsage{}, LLMUsageSettings{DailyTokenBudget: tt.budget})
+			if tt.budget > 0 {
+				tracker.RecordCall(ctx, LLMTaskFishFromNews, "scripted", LLMOptions{},
+					&LLMResponse{PromptTokens: tt.budget}, 0, "", nil)
+			}
+			client := NewGeminiClientWithLLM(LLMTask{Provider: scripted, Usage: tracker}.WithDefaults(LLMTaskFishFromNews, ""))
+
+			_, err := client.GenerateFishFromNews(ctx, &NewsItem{Headline: "Markets rally", Category: "business"})
+			var genErr *FishGenerationError
+			if !errors.As(err, &genErr) {
+				t.Fatalf("GenerateFishFromNews() error = %v, want a FishGenerationError", err)
+			}
+			if genErr.Kind != tt.kind || genErr.Attempts != tt.attempts {
+				t.Errorf("error = %s after %d attempt(s), want %s after %d", genErr.Kind, genErr.Attempts, tt.kind, tt.attempts)
+			}
+			if got := len(scripted.Prompts()); got != tt.attempts {
+				t.Errorf("provider calls = %d, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestGenerateFishRepairAttemptsSetting(t *testing.T) {
+	scripted := NewScriptedProvider("no fish today", validNewsFish)
+	client := NewGeminiClientWithLLM(LLMTask{Provider: scripted}.WithDefaults(LLMTaskFishFromNews, ""))
+	client.SetRepairAttempts(0)
+
+	_, err := client.GenerateFishFromNews(context.Background(), &NewsItem{Headline: "Markets rally", Category: "business"})
+	var genErr *FishGenerationError
+	if !errors.As(err, &genErr) || genErr.Kind != FishErrorNoJSON || genErr.Attempts != 1 {
+		t.Fatalf("GenerateFishFromNews() error = %v, want %s after 1 attempt", err, FishErrorNoJSON)
+	}
+}
+
+func TestGenerateFishInvalidatesCachedBadResponse(t *testing.T) {
+	ctx := context.Background()
+	scripted := NewScriptedProvider("no fish today", validNewsFish)
+	store := newMemoryLLMCache()
+	provider := NewCachingProvider(scripted, store, LLMCacheSettings{})
+	client := NewGeminiClientWithLLM(LLMTask{Provider: provider}.WithDefaults(LLMTaskFishFromNews, ""))
+	client.SetRepairAttempts(0)
+	news := &NewsItem{Headline: "Markets rally", Category: "business"}
+
+	if _, err := client.GenerateFishFromNews(ctx, news); err == nil {
+		t.Fatal("GenerateFishFromNews() error = nil, want the invalid response rejected")
+	}
+	prompt := scripted.Prompts()[0]
+	key := LLMCacheKey("scripted", prompt, client.options)
+	if store.entry(key) != nil || len(store.deleted) != 1 || store.deleted[0] != key {
+		t.Fatalf("cache still holds the rejected response (deleted: %v)", store.deleted)
+	}
+
+	// Retrying the same prompt asks the model again instead of reusing the rejected answer
+	fish, err := client.GenerateFishFromNews(ctx, news)
+	if err != nil {
+		t.Fatalf("GenerateFishFromNews() retry error = %v", err)
+	}
+	if fish.Name != "Headline Herring" {
+		t.Errorf("fish = %+v, want Headline Herring", fish)
+	}
+	if prompts := scripted.Prompts(); len(prompts) != 2 || prompts[1] != prompt {
+		t.Errorf("provider prompts = %d, want the same prompt sent twice", len(prompts))
+	}
+	if entry := store.entry(key); entry == nil || entry.Response != validNewsFish {
+		t.Errorf("cache entry = %+v, want the valid response", entry)
+	}
+}
@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"math"
 	"math/rand"
@@ -33,137 +34,109 @@ type GoldPrice struct {
 
 // GoldCollector collects real gold price data
 type GoldCollector struct {
-	apiKey             string
-	lastPrice          float64
-	client             *http.Client
-	lastCollectionTime time.Time // Track when we last called the API
-	yesterdayPrice     float64   // Store yesterday's price for change calculation
+	apiKey        string
+	lastPrice     float64
+	previousPrice float64 // Price of the collection before the last, for the change calculation
+	client        *http.Client
+	httpOptions   HTTPOptions
 }
 
 // NewGoldCollector creates a new gold price data collector
 func NewGoldCollector(apiKey string) *GoldCollector {
+	return NewGoldCollectorWithHTTP(apiKey, HTTPOptions{})
+}
+
+// NewGoldCollectorWithHTTP creates a gold price data collector that makes its requests through the given options
+func NewGoldCollectorWithHTTP(apiKey string, opts HTTPOptions) *GoldCollector {
 	return &GoldCollector{
-		apiKey:             apiKey,
-		lastPrice:          0, // Will be set on first collection
-		yesterdayPrice:     0, // Will be set after first collection
-		client:             &http.Client{Timeout: 10 * time.Second},
-		lastCollectionTime: time.Time{}, // Zero time initially
+		apiKey:      apiKey,
+		client:      opts.client(),
+		httpOptions: opts,
 	}
 }
 
-// Collect retrieves real gold price data
+// Collect retrieves real gold price data. Without an API key, mock prices are generated;
+// API failures, including calls held back by the API quota, are returned as errors.
 func (c *GoldCollector) Collect(ctx context.Context) (*DataEvent, error) {
-	// Check if we have an API key
-	if c.apiKey != "" {
-		// Check if we should call the API (once per day)
-		currentTime := time.Now()
-		shouldCallAPI := c.lastCollectionTime.IsZero() || // First time collecting
-			currentTime.Sub(c.lastCollectionTime) >= 24*time.Hour // At least 24 hours since last collection
-
-		if shouldCallAPI {
-			log.Printf("Calling gold price API (last call: %v)", c.lastCollectionTime)
-
-			// Save current price as yesterday's price before getting new price
-			if c.lastPrice > 0 {
-				c.yesterdayPrice = c.lastPrice
-			}
+	if c.apiKey == "" {
+		log.Println("No Gold API key provided, using mock data")
+		return c.CollectMockData()
+	}
 
-			// New API endpoint
-			url := "https://api.metalpriceapi.com/v1/convert?from=XAU&to=USD&amount=1"
+	goldPricePerOunce, err := c.fetchPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-			// Create HTTP request
-			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-			if err != nil {
-				log.Printf("Error creating gold API request: %v", err)
-			} else {
-				// Add required headers
-				req.Header.Add("X-API-KEY", c.apiKey)
-				req.Header.Add("Content-Type", "application/json")
-
-				// Make the request
-				resp, err := c.client.Do(req)
-				if err != nil {
-					log.Printf("Error making gold API request: %v", err)
-				} else {
-					defer resp.Body.Close()
-
-					// Check if the response was successful
-					if resp.StatusCode != http.StatusOK {
-						log.Printf("Gold API returned status code %d", resp.StatusCode)
-					} else {
-						// Parse the new response format
-						var apiResponse MetalPriceAPIResponse
-						if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-							log.Printf("Error decoding gold API response: %v", err)
-						} else if !apiResponse.Success {
-							log.Printf("Gold API returned success=false")
-						} else {
-							// API call succeeded, use real data
-							goldPricePerOunce := apiResponse.Result
-
-							// Calculate change percentage based on yesterday's price
-							changePercentage := 0.0
-							if c.yesterdayPrice > 0 {
-								changePercentage = ((goldPricePerOunce - c.yesterdayPrice) / c.yesterdayPrice) * 100.0
-							}
-
-							// Update last collection time
-							c.lastCollectionTime = currentTime
-
-							// Save the current price for future reference
-							c.lastPrice = goldPricePerOunce
-
-							goldData := &GoldPrice{
-								PriceUSD:  goldPricePerOunce,
-								Change24h: changePercentage,
-							}
-
-							return &DataEvent{
-								Type:      GoldData,
-								Value:     goldData,
-								Timestamp: time.Now(),
-								Source:    "metalpriceapi",
-								Raw: map[string]interface{}{
-									"price":           goldPricePerOunce,
-									"previous_price":  c.yesterdayPrice,
-									"change":          changePercentage,
-									"last_collection": c.lastCollectionTime,
-								},
-							}, nil
-						}
-					}
-				}
-			}
-		} else {
-			log.Printf("Using cached gold price data (next API call in %v)",
-				24*time.Hour-currentTime.Sub(c.lastCollectionTime))
-
-			// Use last price we collected from the API
-			goldData := &GoldPrice{
-				PriceUSD:  c.lastPrice,
-				Change24h: ((c.lastPrice - c.yesterdayPrice) / c.yesterdayPrice) * 100.0,
-			}
+	// Save the previous price for the change calculation
+	if c.lastPrice > 0 {
+		c.previousPrice = c.lastPrice
+	}
+	changePercentage := percentChange(c.previousPrice, goldPricePerOunce)
+	c.lastPrice = goldPricePerOunce
 
-			return &DataEvent{
-				Type:      GoldData,
-				Value:     goldData,
-				Timestamp: time.Now(),
-				Source:    "metalpriceapi-cached",
-				Raw: map[string]interface{}{
-					"price":           c.lastPrice,
-					"previous_price":  c.yesterdayPrice,
-					"change":          goldData.Change24h,
-					"last_collection": c.lastCollectionTime,
-					"cached":          true,
-				},
-			}, nil
-		}
-	} else {
-		log.Println("No Gold API key provided, using mock data")
+	goldData := &GoldPrice{
+		PriceUSD:  goldPricePerOunce,
+		Change24h: changePercentage,
+	}
+
+	return &DataEvent{
+		Type:      GoldData,
+		Value:     goldData,
+		Timestamp: time.Now(),
+		Source:    "metalpriceapi",
+		Raw: map[string]interface{}{
+			"price":          goldPricePerOunce,
+			"previous_price": c.previousPrice,
+			"change":         changePercentage,
+		},
+	}, nil
+}
+
+// fetchPrice calls the Metal Price API for the price of one ounce of gold in USD
+func (c *GoldCollector) fetchPrice(ctx context.Context) (float64, error) {
+	url := c.httpOptions.url("https://api.metalpriceapi.com", "/v1/convert?from=XAU&to=USD&amount=1")
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %v", err)
+	}
+
+	// Add required headers
+	req.Header.Add("X-API-KEY", c.apiKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	// Make the request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error making request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	// Fall back to mock data
-	return c.CollectMockData()
+	// Check if the response was successful
+	if err := checkStatus(resp); err != nil {
+		return 0, err
+	}
+
+	// Parse the response
+	var apiResponse MetalPriceAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+		return 0, fmt.Errorf("error decoding response: %v", err)
+	}
+	if !apiResponse.Success {
+		return 0, fmt.Errorf("API returned success=false")
+	}
+	return apiResponse.Result, nil
+}
+
+// percentChange returns the change from the previous to the current price in percent, or
+// zero when there is no previous price
+func percentChange(previous, current float64) float64 {
+	if previous <= 0 {
+		return 0
+	}
+	return ((current - previous) / previous) * 100.0
 }
 
 // CollectMockData generates mock gold price data when API is not available
@@ -0,0 +1,229 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTTPOptions points a collector at another API host or HTTP transport, such as a local
+// test server or a fixture replay
+type HTTPOptions struct {
+	BaseURL   string            // Scheme and host, plus an optional path prefix, replacing the real API's; empty for the real API
+	Transport http.RoundTripper // nil for http.DefaultTransport
+}
+
+// client returns the HTTP client collectors make their requests with
+func (o HTTPOptions) client() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second, Transport: o.Transport}
+}
+
+// url joins the base URL, or the real API's when none is set, with a path and query
+func (o HTTPOptions) url(defaultBaseURL, pathAndQuery string) string {
+	base := defaultBaseURL
+	if o.BaseURL != "" {
+		base = o.BaseURL
+	}
+	return strings.TrimSuffix(base, "/") + pathAndQuery
+}
+
+// Modes of the HTTP fixture transport
+const (
+	FixtureModeRecord = "record" // Call the real APIs and save every response
+	FixtureModeReplay = "replay" // Serve saved responses and never touch the network
+)
+
+// fixtureSecretParams are query parameters left out of fixture names and recorded URLs
+var fixtureSecretParams = map[string]bool{"apikey": true, "api_key": true, "appid": true, "key": true, "token": true}
+
+// fixtureNameUnsafe matches the characters replaced in fixture file names
+var fixtureNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// maxFixtureNameLength is the length after which fixture names are shortened with a hash
+const maxFixtureNameLength = 120
+
+// HTTPFixture is a recorded HTTP response. JSON bodies are stored as JSON so fixtures can
+// be read and edited by hand.
+type HTTPFixture struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"` // Request URL without secret query parameters
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"` // Body, when it is valid JSON
+	Body   string          `json:"body,omitempty"` // Body, otherwise
+}
+
+// NewFixtureTransport returns a transport that records responses into dir or replays them
+// from it, depending on the mode
+func NewFixtureTransport(mode, dir string) (http.RoundTripper, error) {
+	switch mode {
+	case FixtureModeRecord:
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create fixture directory: %v", err)
+		}
+		return &FixtureRecorder{Dir: dir}, nil
+	case FixtureModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("fixture directory is not readable: %v", err)
+		}
+		return &FixtureReplayer{Dir: dir}, nil
+	default:
+		return nil, fmt.Errorf("unknown fixture mode %q (expected %s or %s)", mode, FixtureModeRecord, FixtureModeReplay)
+	}
+}
+
+// FixtureRecorder is a transport that passes requests to the real API and saves each response
+// as a fixture. A later response to the same request replaces the earlier one.
+type FixtureRecorder struct {
+	Dir       string
+	Transport http.RoundTripper // nil for http.DefaultTransport
+}
+
+// RoundTrip performs the request and records the response
+func (r *FixtureRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response to record: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := HTTPFixture{
+		Method: req.Method,
+		URL:    redactedURL(req.URL),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		fixture.Header.Set("Content-Type", contentType)
+	}
+	if json.Valid(body) {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, body, "", "  "); err == nil {
+			fixture.JSON = indented.Bytes()
+		} else {
+			fixture.JSON = body
+		}
+	} else {
+		fixture.Body = string(body)
+	}
+
+	var raw bytes.Buffer
+	encoder := json.NewEncoder(&raw)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %v", err)
+	}
+	path := filepath.Join(r.Dir, FixtureName(req))
+	if err := os.WriteFile(path, raw.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %v", err)
+	}
+	log.Printf("Recorded %s %s to %s", req.Method, fixture.URL, path)
+	return resp, nil
+}
+
+// FixtureReplayer is a transport that answers every request from its fixture and fails
+// requests that have none, so replays are deterministic and never reach the network
+type FixtureReplayer struct {
+	Dir string
+}
+
+// RoundTrip returns the recorded response to the request
+func (r *FixtureReplayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	path := filepath.Join(r.Dir, FixtureName(req))
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s %s (%s): %v", req.Method, redactedURL(req.URL), filepath.Base(path), err)
+	}
+	var fixture HTTPFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %v", path, err)
+	}
+
+	body := []byte(fixture.Body)
+	if len(fixture.JSON) > 0 {
+		body = fixture.JSON
+	}
+	header := fixture.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// FixtureName returns the file a request's response is recorded in: the method, host, path
+// and sorted query without secrets, made safe for file names, e.g.
+// "GET_newsapi.org_v2_top-headlines_category-science_language-en_pageSize-30.json"
+func FixtureName(req *http.Request) string {
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if !fixtureSecretParams[strings.ToLower(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := []string{req.Method, req.URL.Host, req.URL.Path}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, key+"-"+value)
+		}
+	}
+
+	name := strings.Trim(fixtureNameUnsafe.ReplaceAllString(strings.Join(parts, "_"), "_"), "_")
+	if len(name) > maxFixtureNameLength {
+		sum := sha256.Sum256([]byte(name))
+		name = name[:maxFixtureNameLength-13] + "_" + hex.EncodeToString(sum[:])[:12]
+	}
+	return name + ".json"
+}
+
+// redactedURL returns the URL without secret query parameters
+func redactedURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for key := range query {
+		if fixtureSecretParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
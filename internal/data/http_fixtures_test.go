@@ -0,0 +1,175 @@
+package data
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureName(t *testing.T) {
+	long := "https://api.example.com/v1/search?q=" + strings.Repeat("fish", 40)
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"sorted query", "https://newsapi.org/v2/top-headlines?pageSize=30&language=en&category=science",
+			"GET_newsapi.org_v2_top-headlines_category-science_language-en_pageSize-30.json"},
+		{"secrets dropped", "https://api.openweathermap.org/data/2.5/weather?id=2643743&units=metric&appid=secret",
+			"GET_api.openweathermap.org_data_2.5_weather_id-2643743_units-metric.json"},
+		{"secret names are case-insensitive", "https://api.example.com/quote?ApiKey=secret&Token=secret&symbol=XAU",
+			"GET_api.example.com_quote_symbol-XAU.json"},
+		{"repeated values", "https://api.example.com/series?facet=a&facet=b",
+			"GET_api.example.com_series_facet-a_facet-b.json"},
+		{"unsafe characters", "https://api.example.com/data?sort[0][column]=period&q=a+b",
+			"GET_api.example.com_data_q-a_b_sort_0_column_-period.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if got := FixtureName(req); got != tt.want {
+				t.Errorf("FixtureName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("long names hashed", func(t *testing.T) {
+		name := FixtureName(httptest.NewRequest(http.MethodGet, long, nil))
+		if len(name) != maxFixtureNameLength+len(".json") {
+			t.Errorf("FixtureName() has %d characters, want %d: %s", len(name), maxFixtureNameLength+len(".json"), name)
+		}
+		if !strings.HasPrefix(name, "GET_api.example.com_v1_search_q-fishfish") {
+			t.Errorf("FixtureName() = %q, want it to start with the readable request", name)
+		}
+
+		// Requests that only differ past the cut get different files
+		other := FixtureName(httptest.NewRequest(http.MethodGet, long+"x", nil))
+		if other == name || len(other) != len(name) {
+			t.Errorf("FixtureName() = %q for a different request, want another name of the same length", other)
+		}
+		if again := FixtureName(httptest.NewRequest(http.MethodGet, long, nil)); again != name {
+			t.Errorf("FixtureName() = %q on the same request, want %q", again, name)
+		}
+	})
+}
+
+func TestRedactedURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"no query", "https://api.coingecko.com/api/v3/ping", "https://api.coingecko.com/api/v3/ping"},
+		{"no secrets", "https://newsapi.org/v2/top-headlines?language=en&category=science",
+			"https://newsapi.org/v2/top-headlines?category=science&language=en"},
+		{"every secret dropped", "https://api.example.com/v1?apiKey=a&api_key=b&APPID=c&key=d&token=e&symbol=XAU",
+			"https://api.example.com/v1?symbol=XAU"},
+		{"only secrets", "https://api.example.com/v1?token=e", "https://api.example.com/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := redactedURL(u); got != tt.want {
+				t.Errorf("redactedURL() = %q, want %q", got, tt.want)
+			}
+			if u.String() != tt.url {
+				t.Errorf("redactedURL() changed its argument to %q", u.String())
+			}
+		})
+	}
+}
+
+func TestFixtureRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"price":{"usd":64250.5},"ok":true}`))
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("slow down"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewFixtureTransport(FixtureModeRecord, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(record) error = %v", err)
+	}
+	replayer, err := NewFixtureTransport(FixtureModeReplay, dir)
+	if err != nil {
+		t.Fatalf("NewFixtureTransport(replay) error = %v", err)
+	}
+
+	get := func(transport http.RoundTripper, path string) (int, string, string) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading %s error = %v", path, err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	for _, path := range []string{"/json?apikey=secret&asset=btc", "/text"} {
+		status, contentType, body := get(recorder, path)
+		replayedStatus, replayedType, replayedBody := get(replayer, path)
+
+		if replayedStatus != status || replayedType != contentType {
+			t.Errorf("replayed %s = %d %s, want %d %s", path, replayedStatus, replayedType, status, contentType)
+		}
+		// JSON is stored indented, so compare it without whitespace
+		if strings.Join(strings.Fields(replayedBody), "") != strings.Join(strings.Fields(body), "") {
+			t.Errorf("replayed %s body = %q, want %q", path, replayedBody, body)
+		}
+	}
+
+	// The recorded fixture has no secrets, and the replay ignores them
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("recorded %d fixtures, want 2", len(files))
+	}
+	for _, file := range files {
+		raw, _ := os.ReadFile(file)
+		if strings.Contains(string(raw), "secret") {
+			t.Errorf("fixture %s contains the API key:\n%s", filepath.Base(file), raw)
+		}
+	}
+	if _, _, body := get(replayer, "/json?asset=btc&apikey=another"); !strings.Contains(body, "64250.5") {
+		t.Errorf("replay with another API key = %q, want the recorded body", body)
+	}
+
+	// Requests that were never recorded fail instead of reaching the network
+	if _, err := (&http.Client{Transport: replayer}).Get(server.URL + "/json?asset=eth"); err == nil ||
+		!strings.Contains(err.Error(), "no fixture") {
+		t.Errorf("unrecorded request error = %v, want no fixture", err)
+	}
+}
+
+func TestNewFixtureTransportRejectsBadSettings(t *testing.T) {
+	if _, err := NewFixtureTransport("playback", t.TempDir()); err == nil {
+		t.Error("NewFixtureTransport() with an unknown mode succeeded, want an error")
+	}
+	if _, err := NewFixtureTransport(FixtureModeReplay, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("NewFixtureTransport() replaying a missing directory succeeded, want an error")
+	}
+}
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Default limits of the LLM response cache
+const (
+	DefaultLLMCacheTTL        = 7 * 24 * time.Hour
+	DefaultLLMCacheMaxEntries = 5000
+)
+
+// llmCachePruneInterval limits how often expired and excess entries are removed
+const llmCachePruneInterval = 10 * time.Minute
+
+// LLMCacheEntry is a model response stored under a hash of the request that produced it
+type LLMCacheEntry struct {
+	Key            string    `bson:"key" json:"key"`
+	Provider       string    `bson:"provider" json:"provider"`
+	Model          string    `bson:"model" json:"model"`
+	Options        string    `bson:"options" json:"options"` // JSON-encoded LLMOptions
+	Prompt         string    `bson:"prompt" json:"prompt"`   // Kept so cached calls can be inspected
+	Response       string    `bson:"response" json:"response"`
+	PromptTokens   int       `bson:"prompt_tokens" json:"prompt_tokens"`
+	ResponseTokens int       `bson:"response_tokens" json:"response_tokens"`
+	Hits           int       `bson:"hits" json:"hits"`
+	CreatedAt      time.Time `bson:"created_at" json:"created_at"`
+	LastHitAt      time.Time `bson:"last_hit_at,omitempty" json:"last_hit_at,omitempty"`
+	ExpiresAt      time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// LLMCacheStore persists cached LLM responses
+type LLMCacheStore interface {
+	// GetLLMCacheEntry returns the entry with the given key, or nil if there is none
+	GetLLMCacheEntry(ctx context.Context, key string) (*LLMCacheEntry, error)
+	// SaveLLMCacheEntry inserts or replaces the entry with the same key
+	SaveLLMCacheEntry(ctx context.Context, entry *LLMCacheEntry) error
+	// RecordLLMCacheHit increments the hit count of an entry
+	RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error
+	// DeleteLLMCacheEntry removes an entry
+	DeleteLLMCacheEntry(ctx context.Context, key string) error
+	// PruneLLMCache removes expired entries, then the oldest entries beyond maxEntries
+	PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error)
+}
+
+// LLMCacheSettings limits the LLM response cache
+type LLMCacheSettings struct {
+	TTL        time.Duration // How long a response is reused
+	MaxEntries int           // Maximum number of stored responses
+}
+
+// LLMCacheInvalidator is implemented by providers that cache responses, so callers can
+// drop a response that turned out to be unusable instead of getting it again on retry
+type LLMCacheInvalidator interface {
+	Invalidate(ctx context.Context, prompt string, opts LLMOptions)
+}
+
+// CachingProvider wraps an LLMProvider with a content-addressed response cache.
+// Identical requests, including concurrent ones, reach the underlying provider only once.
+type CachingProvider struct {
+	provider  LLMProvider
+	store     LLMCacheStore
+	settings  LLMCacheSettings
+	inflight  map[string]*llmCall
+	lastPrune time.Time
+	mu        sync.Mutex
+}
+
+// llmCall is a provider request that identical requests wait on
+type llmCall struct {
+	done chan struct{}
+	resp *LLMResponse
+	err  error
+}
+
+// NewCachingProvider wraps provider with a cache persisted in store
+func NewCachingProvider(provider LLMProvider, store LLMCacheStore, settings LLMCacheSettings) *CachingProvider {
+	if settings.TTL <= 0 {
+		settings.TTL = DefaultLLMCacheTTL
+	}
+	if settings.MaxEntries <= 0 {
+		settings.MaxEntries = DefaultLLMCacheMaxEntries
+	}
+
+	return &CachingProvider{
+		provider: provider,
+		store:    store,
+		settings: settings,
+		inflight: make(map[string]*llmCall),
+	}
+}
+
+// LLMCacheKey returns the cache key of a request: a SHA-256 of the provider, model, options and prompt
+func LLMCacheKey(provider, prompt string, opts LLMOptions) string {
+	encodedOptions, _ := json.Marshal(opts)
+
+	hash := sha256.New()
+	for _, part := range []string{provider, string(encodedOptions), prompt} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Name returns the name of the wrapped provider
+func (p *CachingProvider) Name() string {
+	return p.provider.Name()
+}
+
+// Generate returns a cached response for an identical earlier request, or calls the provider and caches its response
+func (p *CachingProvider) Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error) {
+	key := LLMCacheKey(p.provider.Name(), prompt, opts)
+
+	if resp := p.lookup(ctx, key); resp != nil {
+		return resp, nil
+	}
+
+	// Wait for an identical request that is already in progress
+	p.mu.Lock()
+	if call, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.resp, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &llmCall{done: make(chan struct{})}
+	p.inflight[key] = call
+	p.mu.Unlock()
+
+	call.resp, call.err = p.provider.Generate(ctx, prompt, opts)
+	if call.err == nil {
+		p.save(ctx, key, prompt, opts, call.resp)
+	}
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	p.mu.Unlock()
+	close(call.done)
+
+	return call.resp, call.err
+}
+
+// lookup returns the cached response for key, or nil on a miss
+func (p *CachingProvider) lookup(ctx context.Context, key string) *LLMResponse {
+	entry, err := p.store.GetLLMCacheEntry(ctx, key)
+	if err != nil {
+		log.Printf("Warning: LLM cache lookup failed: %v", err)
+		return nil
+	}
+
+	now := time.Now()
+	if entry == nil || !now.Before(entry.ExpiresAt) {
+		return nil
+	}
+
+	if err := p.store.RecordLLMCacheHit(ctx, key, now); err != nil {
+		log.Printf("Warning: failed to record LLM cache hit: %v", err)
+	}
+	log.Printf("Using cached %s response (model: %s, cached %s ago)",
+		entry.Provider, entry.Model, now.Sub(entry.CreatedAt).Round(time.Second))
+
+	return &LLMResponse{
+		Text:   entry.Response,
+		Model:  entry.Model,
+		Cached: true, // No tokens were spent on this call
+	}
+}
+
+// save stores a provider response and prunes the cache now and then
+func (p *CachingProvider) save(ctx context.Context, key, prompt string, opts LLMOptions, resp *LLMResponse) {
+	now := time.Now()
+	encodedOptions, _ := json.Marshal(opts)
+
+	entry := &LLMCacheEntry{
+		Key:            key,
+		Provider:       p.provider.Name(),
+		Model:          resp.Model,
+		Options:        string(encodedOptions),
+		Prompt:         prompt,
+		Response:       resp.Text,
+		PromptTokens:   resp.PromptTokens,
+		ResponseTokens: resp.ResponseTokens,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(p.settings.TTL),
+	}
+	if err := p.store.SaveLLMCacheEntry(ctx, entry); err != nil {
+		log.Printf("Warning: failed to cache LLM response: %v", err)
+		return
+	}
+
+	p.mu.Lock()
+	due := now.Sub(p.lastPrune) >= llmCachePruneInterval
+	if due {
+		p.lastPrune = now
+	}
+	p.mu.Unlock()
+
+	if due {
+		removed, err := p.store.PruneLLMCache(ctx, now, p.settings.MaxEntries)
+		if err != nil {
+			log.Printf("Warning: failed to prune LLM cache: %v", err)
+		} else if removed > 0 {
+			log.Printf("Pruned %d LLM cache entries", removed)
+		}
+	}
+}
+
+// Invalidate removes the cached response of a request
+func (p *CachingProvider) Invalidate(ctx context.Context, prompt string, opts LLMOptions) {
+	key := LLMCacheKey(p.provider.Name(), prompt, opts)
+	if err := p.store.DeleteLLMCacheEntry(ctx, key); err != nil {
+		log.Printf("Warning: failed to remove LLM cache entry: %v", err)
+	}
+}
+
+// Close closes the wrapped provider
+func (p *CachingProvider) Close() error {
+	return p.provider.Close()
+}
+
+// invalidateLLMResponse drops a cached response if the provider caches responses
+func invalidateLLMResponse(ctx context.Context, provider LLMProvider, prompt string, opts LLMOptions) {
+	if invalidator, ok := provider.(LLMCacheInvalidator); ok {
+		invalidator.Invalidate(ctx, prompt, opts)
+	}
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// GeminiProvider generates text with Google's Gemini API
+type GeminiProvider struct {
+	apiKey string
+	client *genai.Client
+	mu     sync.Mutex
+}
+
+// NewGeminiProvider creates a Gemini provider; the client is created on first use
+func NewGeminiProvider(apiKey string) *GeminiProvider {
+	return &GeminiProvider{apiKey: apiKey}
+}
+
+// Name returns the provider name
+func (p *GeminiProvider) Name() string {
+	return LLMProviderGemini
+}
+
+// getClient returns the shared Gemini client, creating it if needed
+func (p *GeminiProvider) getClient(ctx context.Context) (*genai.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("no API key provided for Gemini client")
+	}
+
+	client, err := genai.NewClient(ctx, option.WithAPIKey(p.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	p.client = client
+	return client, nil
+}
+
+// Generate sends the prompt to Gemini
+func (p *GeminiProvider) Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error) {
+	client, err := p.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modelName := opts.Model
+	if modelName == "" {
+		modelName = DefaultLLMModel
+	}
+
+	// Configure the model
+	model := client.GenerativeModel(modelName)
+	if opts.Temperature > 0 {
+		model.SetTemperature(float32(opts.Temperature))
+	}
+	if opts.TopP > 0 {
+		model.SetTopP(float32(opts.TopP))
+	}
+	if opts.TopK > 0 {
+		model.SetTopK(int32(opts.TopK))
+	}
+	if opts.MaxTokens > 0 {
+		model.SetMaxOutputTokens(int32(opts.MaxTokens))
+	}
+	model.ResponseMIMEType = "text/plain"
+
+	log.Printf("Sending request to Gemini API using model: %s", modelName)
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("error sending message: %w", err)
+	}
+	if resp == nil || len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil ||
+		len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("empty response from model")
+	}
+
+	// Extract response text
+	result := &LLMResponse{Model: modelName}
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if str, ok := part.(genai.Text); ok {
+			result.Text += string(str)
+		}
+	}
+	if result.Text == "" {
+		return nil, fmt.Errorf("empty or invalid text in model response")
+	}
+
+	if resp.UsageMetadata != nil {
+		result.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+		result.ResponseTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+	}
+
+	return result, nil
+}
+
+// Close closes the Gemini client
+func (p *GeminiProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProvider generates text with an OpenAI-compatible chat completions API,
+// such as a local Ollama or llama.cpp server
+type OpenAIProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// openAIChatRequest is the body of a chat completions request
+type openAIChatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature float64         `json:"temperature,omitempty"`
+	TopP        float64         `json:"top_p,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream"`
+}
+
+// openAIMessage is a single chat message
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatResponse is the part of a chat completions response we use
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// NewOpenAIProvider creates a provider for the server at baseURL, e.g. http://localhost:11434/v1.
+// The API key is optional; local servers usually don't need one.
+func NewOpenAIProvider(baseURL, apiKey string) *OpenAIProvider {
+	return &OpenAIProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 5 * time.Minute}, // Local models can be slow
+	}
+}
+
+// Name returns the provider name
+func (p *OpenAIProvider) Name() string {
+	return LLMProviderOpenAI
+}
+
+// Generate sends the prompt as a single user message
+func (p *OpenAIProvider) Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:       opts.Model,
+		Messages:    []openAIMessage{{Role: "user", Content: prompt}},
+		Temperature: opts.Temperature,
+		TopP:        opts.TopP,
+		MaxTokens:   opts.MaxTokens,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	log.Printf("Sending request to %s using model: %s", p.baseURL, opts.Model)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("LLM server returned status %d: %s", resp.StatusCode, truncateString(string(respBody), 200))
+	}
+
+	var chat openAIChatResponse
+	if err := json.Unmarshal(respBody, &chat); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	if len(chat.Choices) == 0 || chat.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("empty response from model")
+	}
+
+	model := chat.Model
+	if model == "" {
+		model = opts.Model
+	}
+
+	return &LLMResponse{
+		Text:           chat.Choices[0].Message.Content,
+		Model:          model,
+		PromptTokens:   chat.Usage.PromptTokens,
+		ResponseTokens: chat.Usage.CompletionTokens,
+	}, nil
+}
+
+// Close releases idle connections
+func (p *OpenAIProvider) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,161 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LLM tasks that can each be configured with their own provider and model
+const (
+	LLMTaskFishFromNews    = "fish_from_news"    // Fish generated from a single news item
+	LLMTaskFishFromContext = "fish_from_context" // Fish generated from merged news, weather and prices
+	LLMTaskTranslation     = "translation"       // Fish translated into other locales
+)
+
+// Supported LLM provider names
+const (
+	LLMProviderGemini = "gemini"
+	LLMProviderOpenAI = "openai" // Any OpenAI-compatible server, e.g. Ollama or llama.cpp
+)
+
+// DefaultLLMModel is the Gemini model used when none is configured
+const DefaultLLMModel = "gemma-3-27b-it"
+
+// LLMOptions controls a single text generation call. Zero values leave the provider default.
+type LLMOptions struct {
+	Model       string
+	Temperature float64
+	TopP        float64
+	TopK        int
+	MaxTokens   int
+}
+
+// LLMResponse is the text produced by a provider along with usage details
+type LLMResponse struct {
+	Text           string
+	Model          string
+	PromptTokens   int
+	ResponseTokens int
+	Cached         bool // Served from the response cache without calling the model
+}
+
+// LLMProvider generates text from a prompt
+type LLMProvider interface {
+	// Name identifies the provider in logs, e.g. "gemini"
+	Name() string
+	// Generate sends the prompt and returns the model's text response
+	Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error)
+	// Close releases any connections held by the provider
+	Close() error
+}
+
+// LLMTask pairs a provider with the options used for one kind of generation
+type LLMTask struct {
+	Name     string // One of the LLMTask* names, used for usage accounting
+	Provider LLMProvider
+	Options  LLMOptions
+	Usage    *LLMUsageTracker // Records every call and enforces the token budget; optional
+	Prompts  *PromptLibrary   // Prompt templates; the built-in ones when nil
+}
+
+// LLMSettings configures the provider for one task
+type LLMSettings struct {
+	Provider string // "gemini" or "openai"
+	BaseURL  string // OpenAI-compatible server URL, e.g. http://localhost:11434/v1
+	APIKey   string // Gemini API key or bearer token for the OpenAI-compatible server
+	Options  LLMOptions
+}
+
+// DefaultLLMOptions returns the generation options each task has always used
+func DefaultLLMOptions(task string) LLMOptions {
+	if task == LLMTaskTranslation {
+		// Low temperature for more consistent translations
+		return LLMOptions{Model: DefaultLLMModel, Temperature: 0.2, TopP: 0.95, TopK: 40}
+	}
+	return LLMOptions{Model: DefaultLLMModel, Temperature: 0.9, TopP: 0.95, TopK: 64, MaxTokens: 8192}
+}
+
+// LoadLLMSettings loads the provider settings for a task from environment variables.
+// LLM_PROVIDER, LLM_MODEL, LLM_BASE_URL and LLM_API_KEY apply to every task and can be
+// overridden per task, e.g. LLM_TRANSLATION_MODEL or LLM_FISH_FROM_NEWS_PROVIDER.
+// Per-task LLM_<TASK>_TEMPERATURE and LLM_<TASK>_MAX_TOKENS tune generation.
+func LoadLLMSettings(task, geminiAPIKey string) LLMSettings {
+	prefix := "LLM_" + strings.ToUpper(task) + "_"
+	lookup := func(name string) string {
+		if value := strings.TrimSpace(os.Getenv(prefix + name)); value != "" {
+			return value
+		}
+		return strings.TrimSpace(os.Getenv("LLM_" + name))
+	}
+
+	settings := LLMSettings{
+		Provider: strings.ToLower(lookup("PROVIDER")),
+		BaseURL:  lookup("BASE_URL"),
+		APIKey:   lookup("API_KEY"),
+		Options:  DefaultLLMOptions(task),
+	}
+	if settings.Provider == "" {
+		settings.Provider = LLMProviderGemini
+	}
+	if settings.Provider == LLMProviderGemini {
+		// The shared LLM_API_KEY is meant for the OpenAI-compatible server
+		settings.APIKey = strings.TrimSpace(os.Getenv(prefix + "API_KEY"))
+		if settings.APIKey == "" {
+			settings.APIKey = geminiAPIKey
+		}
+	}
+
+	if model := lookup("MODEL"); model != "" {
+		settings.Options.Model = model
+	}
+	if value, err := strconv.ParseFloat(os.Getenv(prefix+"TEMPERATURE"), 64); err == nil && value >= 0 {
+		settings.Options.Temperature = value
+	}
+	if value, err := strconv.Atoi(os.Getenv(prefix + "MAX_TOKENS")); err == nil && value > 0 {
+		settings.Options.MaxTokens = value
+	}
+
+	return settings
+}
+
+// NewLLMProvider creates the provider described by the settings
+func NewLLMProvider(settings LLMSettings) (LLMProvider, error) {
+	switch settings.Provider {
+	case "", LLMProviderGemini:
+		return NewGeminiProvider(settings.APIKey), nil
+	case LLMProviderOpenAI:
+		if settings.BaseURL == "" {
+			return nil, fmt.Errorf("the openai provider requires a base URL")
+		}
+		return NewOpenAIProvider(settings.BaseURL, settings.APIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider '%s'", settings.Provider)
+	}
+}
+
+// NewLLMTask creates the provider and options for a task from environment variables
+func NewLLMTask(task, geminiAPIKey string) (LLMTask, error) {
+	settings := LoadLLMSettings(task, geminiAPIKey)
+	provider, err := NewLLMProvider(settings)
+	if err != nil {
+		return LLMTask{}, fmt.Errorf("invalid LLM settings for %s: %v", task, err)
+	}
+	return LLMTask{Name: task, Provider: provider, Options: settings.Options}, nil
+}
+
+// WithDefaults fills in a Gemini provider and the task's default options when they are unset
+func (t LLMTask) WithDefaults(task, geminiAPIKey string) LLMTask {
+	if t.Name == "" {
+		t.Name = task
+	}
+	if t.Provider == nil {
+		t.Provider = NewGeminiProvider(geminiAPIKey)
+	}
+	if t.Options == (LLMOptions{}) {
+		t.Options = DefaultLLMOptions(task)
+	}
+	return t
+}
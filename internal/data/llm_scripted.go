@@ -0,0 +1,87 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ScriptedProvider is a deterministic LLMProvider for tests and offline runs.
+// It returns its scripted replies in order and records every prompt it receives.
+type ScriptedProvider struct {
+	replies []scriptedReply
+	prompts []string
+	mu      sync.Mutex
+}
+
+// scriptedReply is either a response text or an error
+type scriptedReply struct {
+	text string
+	err  error
+}
+
+// NewScriptedProvider creates a provider that answers with the given responses in order
+func NewScriptedProvider(responses ...string) *ScriptedProvider {
+	p := &ScriptedProvider{}
+	for _, response := range responses {
+		p.Reply(response)
+	}
+	return p
+}
+
+// Reply queues a response
+func (p *ScriptedProvider) Reply(text string) *ScriptedProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.replies = append(p.replies, scriptedReply{text: text})
+	return p
+}
+
+// Fail queues an error
+func (p *ScriptedProvider) Fail(err error) *ScriptedProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.replies = append(p.replies, scriptedReply{err: err})
+	return p
+}
+
+// Name returns the provider name
+func (p *ScriptedProvider) Name() string {
+	return "scripted"
+}
+
+// Generate records the prompt and returns the next scripted reply
+func (p *ScriptedProvider) Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prompts = append(p.prompts, prompt)
+	if len(p.replies) == 0 {
+		return nil, fmt.Errorf("scripted provider has no reply left for call %d", len(p.prompts))
+	}
+
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	if reply.err != nil {
+		return nil, reply.err
+	}
+
+	return &LLMResponse{
+		Text:           reply.text,
+		Model:          opts.Model,
+		PromptTokens:   len(prompt) / 4, // Rough estimate so accounting has something to count
+		ResponseTokens: len(reply.text) / 4,
+	}, nil
+}
+
+// Prompts returns every prompt received so far
+func (p *ScriptedProvider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.prompts...)
+}
+
+// Close does nothing
+func (p *ScriptedProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,405 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLLMCache is an LLMCacheStore kept in a map
+type memoryLLMCache struct {
+	entries map[string]*LLMCacheEntry
+	deleted []string
+	mu      sync.Mutex
+}
+
+func newMemoryLLMCache() *memoryLLMCache {
+	return &memoryLLMCache{entries: make(map[string]*LLMCacheEntry)}
+}
+
+func (c *memoryLLMCache) GetLLMCacheEntry(ctx context.Context, key string) (*LLMCacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		copied := *entry
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (c *memoryLLMCache) SaveLLMCacheEntry(ctx context.Context, entry *LLMCacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	copied := *entry
+	c.entries[entry.Key] = &copied
+	return nil
+}
+
+func (c *memoryLLMCache) RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		entry.Hits++
+		entry.LastHitAt = at
+	}
+	return nil
+}
+
+func (c *memoryLLMCache) DeleteLLMCacheEntry(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	c.deleted = append(c.deleted, key)
+	return nil
+}
+
+func (c *memoryLLMCache) PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error) {
+	return 0, nil
+}
+
+func (c *memoryLLMCache) entry(key string) *LLMCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key]
+}
+
+// memoryLLMUsage is an LLMUsageStore kept in a slice
+type memoryLLMUsage struct {
+	records []*LLMUsageRecord
+	mu      sync.Mutex
+}
+
+func (u *memoryLLMUsage) SaveLLMUsage(ctx context.Context, record *LLMUsageRecord) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.records = append(u.records, record)
+	return nil
+}
+
+func (u *memoryLLMUsage) GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]LLMUsageTotal, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var records []*LLMUsageRecord
+	for _, record := range u.records {
+		if !record.Timestamp.Before(from) && record.Timestamp.Before(to) {
+			records = append(records, record)
+		}
+	}
+	return AggregateLLMUsage(records), nil
+}
+
+func (u *memoryLLMUsage) outcomes() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var outcomes []string
+	for _, record := range u.records {
+		outcomes = append(outcomes, record.Outcome)
+	}
+	return outcomes
+}
+
+// gatedProvider holds every call until release is closed, signalling each one on entered
+type gatedProvider struct {
+	*ScriptedProvider
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *gatedProvider) Generate(ctx context.Context, prompt string, opts LLMOptions) (*LLMResponse, error) {
+	p.entered <- struct{}{}
+	<-p.release
+	return p.ScriptedProvider.Generate(ctx, prompt, opts)
+}
+
+const validNewsFish = `{"name": "Headline Herring", "description": "A silvery fish that swims against the current of every news cycle.",
+"appearance": "Scales printed with tiny columns of text", "effect": "Reveals the next news event", "rarity": "rare",
+"size": 0.4, "size_units": "meters", "value": 120}`
+
+const validContextFish = `{"name": "Gale Gudgeon", "description": "A restless fish that only surfaces when the harbour is closed by storms.",
+"appearance": "Grey fins frayed like torn sails", "color": "slate grey", "diet": "drifting plankton", "habitat": "storm-tossed harbours",
+"effect": "Calms the sea for a moment", "favorite_weather": "stormy", "existence_reason": "Evolved to feed while boats stay in port"}`
+
+func TestCachingProviderMissThenHit(t *testing.T) {
+	ctx := context.Background()
+	scripted := NewScriptedProvider("first answer", "second answer")
+	store := newMemoryLLMCache()
+	provider := NewCachingProvider(scripted, store, LLMCacheSettings{})
+	opts := DefaultLLMOptions(LLMTaskFishFromNews)
+
+	miss, err := provider.Generate(ctx, "prompt", opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if miss.Cached || miss.Text != "first answer" {
+		t.Fatalf("first call = %+v, want uncached first answer", miss)
+	}
+
+	hit, err := provider.Generate(ctx, "prompt", opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !hit.Cached || hit.Text != "first answer" {
+		t.Fatalf("second call = %+v, want cached first answer", hit)
+	}
+	if entry := store.entry(LLMCacheKey("scripted", "prompt", opts)); entry == nil || entry.Hits != 1 {
+		t.Errorf("cache entry = %+v, want one recorded hit", entry)
+	}
+
+	// Different options are a different request
+	opts.Temperature = 0.1
+	other, err := provider.Generate(ctx, "prompt", opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if other.Cached || other.Text != "second answer" {
+		t.Errorf("call with other options = %+v, want uncached second answer", other)
+	}
+	if got := len(scripted.Prompts()); got != 2 {
+		t.Errorf("provider calls = %d, want 2", got)
+	}
+}
+
+func TestCachingProviderFailureIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	scripted := NewScriptedProvider().Fail(errors.New("unavailable")).Reply("answer")
+	store := newMemoryLLMCache()
+	provider := NewCachingProvider(scripted, store, LLMCacheSettings{})
+
+	if _, err := provider.Generate(ctx, "prompt", LLMOptions{}); err == nil {
+		t.Fatal("Generate() error = nil, want the provider error")
+	}
+	resp, err := provider.Generate(ctx, "prompt", LLMOptions{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Cached || resp.Text != "answer" {
+		t.Errorf("retry = %+v, want uncached answer", resp)
+	}
+}
+
+func TestCachingProviderDeduplicatesConcurrentRequests(t *testing.T) {
+	ctx := context.Background()
+	gated := &gatedProvider{
+		ScriptedProvider: NewScriptedProvider("only answer"),
+		entered:          make(chan struct{}, 10),
+		release:          make(chan struct{}),
+	}
+	provider := NewCachingProvider(gated, newMemoryLLMCache(), LLMCacheSettings{})
+
+	const callers = 5
+	results := make(chan *LLMResponse, callers)
+	errs := make(chan error, callers)
+	var wg sync.WaitGroup
+	call := func() {
+		defer wg.Done()
+		resp, err := provider.Generate(ctx, "prompt", LLMOptions{})
+		if err != nil {
+			errs <- err
+			return
+		}
+		results <- resp
+	}
+
+	// Let the first request reach the provider before the identical ones start
+	wg.Add(1)
+	go call()
+	<-gated.entered
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go call()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(gated.release)
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Generate() error = %v", err)
+	}
+	count := 0
+	for resp := range results {
+		count++
+		if resp.Text != "only answer" {
+			t.Errorf("response = %q, want the single provider answer", resp.Text)
+		}
+	}
+	if count != callers {
+		t.Errorf("got %d responses, want %d", count, callers)
+	}
+	if got := len(gated.Prompts()); got != 1 {
+		t.Errorf("provider calls = %d, want 1", got)
+	}
+}
+
+func TestUsageTrackerBudgetExhaustion(t *testing.T) {
+	ctx := context.Background()
+	usage := &memoryLLMUsage{}
+	tracker := NewLLMUsageTracker(usage, LLMUsageSettings{DailyTokenBudget: 100})
+	scripted := NewScriptedProvider(validNewsFish, validNewsFish)
+	client := NewGeminiClientWithLLM(LLMTask{Provider: scripted, Usage: tracker}.WithDefaults(LLMTaskFishFromNews, ""))
+	news := &NewsItem{Headline: "Markets rally on record harvest", Category: "business"}
+
+	if err := tracker.CheckBudget(ctx); err != nil {
+		t.Fatalf("CheckBudget() before any call = %v", err)
+	}
+	if _, err := client.GenerateFishFromNews(ctx, news); err != nil {
+		t.Fatalf("GenerateFishFromNews() error = %v", err)
+	}
+
+	// The prompt alone is well over 100 tokens, so the first call spends the budget
+	if !tracker.BudgetExhausted(ctx) {
+		t.Fatalf("BudgetExhausted() = false after %d tokens", tracker.TokensUsedToday(ctx))
+	}
+	if err := tracker.CheckBudget(ctx); !errors.Is(err, ErrLLMBudgetExhausted) {
+		t.Fatalf("CheckBudget() = %v, want ErrLLMBudgetExhausted", err)
+	}
+
+	_, err := client.GenerateFishFromNews(ctx, news)
+	var genErr *FishGenerationError
+	if !errors.As(err, &genErr) || genErr.Kind != FishErrorBudgetExhausted {
+		t.Fatalf("GenerateFishFromNews() error = %v, want %s", err, FishErrorBudgetExhausted)
+	}
+	if genErr.Attempts != 0 || !errors.Is(err, ErrLLMBudgetExhausted) {
+		t.Errorf("error = %+v, want no attempts wrapping ErrLLMBudgetExhausted", genErr)
+	}
+	if got := len(scripted.Prompts()); got != 1 {
+		t.Errorf("provider calls = %d, want 1", got)
+	}
+
+	// A restarted tracker counts the usage already stored for today
+	restarted := NewLLMUsageTracker(usage, LLMUsageSettings{DailyTokenBudget: 100})
+	if !restarted.BudgetExhausted(ctx) {
+		t.Error("restarted tracker has budget left, want it exhausted")
+	}
+}
+
+func TestUsageTrackerCachedCallsAreFree(t *testing.T) {
+	ctx := context.Background()
+	usage := &memoryLLMUsage{}
+	tracker := NewLLMUsageTracker(usage, LLMUsageSettings{})
+	provider := NewCachingProvider(NewScriptedProvider(validNewsFish), newMemoryLLMCache(), LLMCacheSettings{})
+	client := NewGeminiClientWithLLM(LLMTask{Provider: provider, Usage: tracker}.WithDefaults(LLMTaskFishFromNews, ""))
+	news := &NewsItem{Headline: "Markets rally on record harvest", Category: "business"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GenerateFishFromNews(ctx, news); err != nil {
+			t.Fatalf("GenerateFishFromNews() call %d error = %v", i+1, err)
+		}
+	}
+	spent := tracker.TokensUsedToday(ctx)
+
+	if _, err := client.GenerateFishFromNews(ctx, news); err != nil {
+		t.Fatalf("GenerateFishFromNews() error = %v", err)
+	}
+	if got := tracker.TokensUsedToday(ctx); got != spent {
+		t.Errorf("tokens after a cached call = %d, want %d", got, spent)
+	}
+	want := []string{LLMOutcomeSuccess, LLMOutcomeCached, LLMOutcomeCached}
+	if got := usage.outcomes(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+}
+
+func TestLoadLLMSettingsPerTask(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "OpenAI")
+	t.Setenv("LLM_BASE_URL", "http://localhost:11434/v1")
+	t.Setenv("LLM_API_KEY", "local-token")
+	t.Setenv("LLM_MODEL", "llama3")
+	t.Setenv("LLM_TRANSLATION_PROVIDER", "gemini")
+	t.Setenv("LLM_TRANSLATION_MODEL", "gemini-2.0-flash")
+	t.Setenv("LLM_FISH_FROM_CONTEXT_TEMPERATURE", "0.5")
+	t.Setenv("LLM_FISH_FROM_CONTEXT_MAX_TOKENS", "2048")
+
+	tests := []struct {
+		task        string
+		provider    string
+		apiKey      string
+		model       string
+		temperature float64
+		maxTokens   int
+	}{
+		{LLMTaskFishFromNews, LLMProviderOpenAI, "local-token", "llama3", 0.9, 8192},
+		{LLMTaskFishFromContext, LLMProviderOpenAI, "local-token", "llama3", 0.5, 2048},
+		// The shared LLM_API_KEY is not sent to Gemini
+		{LLMTaskTranslation, LLMProviderGemini, "gemini-key", "gemini-2.0-flash", 0.2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			settings := LoadLLMSettings(tt.task, "gemini-key")
+			if settings.Provider != tt.provider || settings.APIKey != tt.apiKey {
+				t.Errorf("provider = %s (key %q), want %s (key %q)", settings.Provider, settings.APIKey, tt.provider, tt.apiKey)
+			}
+			opts := settings.Options
+			if opts.Model != tt.model || opts.Temperature != tt.temperature || opts.MaxTokens != tt.maxTokens {
+				t.Errorf("options = %+v, want model %s, temperature %g, max tokens %d",
+					opts, tt.model, tt.temperature, tt.maxTokens)
+			}
+
+			llm, err := NewLLMTask(tt.task, "gemini-key")
+			if err != nil {
+				t.Fatalf("NewLLMTask() error = %v", err)
+			}
+			if llm.Name != tt.task || llm.Provider.Name() != tt.provider {
+				t.Errorf("task = %s on %s, want %s on %s", llm.Name, llm.Provider.Name(), tt.task, tt.provider)
+			}
+		})
+	}
+}
+
+func TestNewLLMTaskRejectsInvalidProvider(t *testing.T) {
+	t.Setenv("LLM_FISH_FROM_NEWS_PROVIDER", "openai")
+	if _, err := NewLLMTask(LLMTaskFishFromNews, ""); err == nil {
+		t.Error("NewLLMTask() with openai and no base URL succeeded, want an error")
+	}
+
+	t.Setenv("LLM_FISH_FROM_NEWS_PROVIDER", "claude")
+	if _, err := NewLLMTask(LLMTaskFishFromNews, ""); err == nil {
+		t.Error("NewLLMTask() with an unknown provider succeeded, want an error")
+	}
+}
+
+func TestTasksUseTheirOwnProvider(t *testing.T) {
+	ctx := context.Background()
+	newsProvider := NewScriptedProvider(validNewsFish)
+	contextProvider := NewScriptedProvider(validContextFish)
+
+	// A configured provider is kept; only the name and options are filled in
+	newsTask := LLMTask{Provider: newsProvider}.WithDefaults(LLMTaskFishFromNews, "gemini-key")
+	if newsTask.Provider != newsProvider || newsTask.Name != LLMTaskFishFromNews ||
+		newsTask.Options != DefaultLLMOptions(LLMTaskFishFromNews) {
+		t.Fatalf("WithDefaults() = %+v, want the scripted provider with default options", newsTask)
+	}
+	contextOptions := LLMOptions{Model: "llama3", Temperature: 0.5}
+	contextTask := LLMTask{Provider: contextProvider, Options: contextOptions}.WithDefaults(LLMTaskFishFromContext, "gemini-key")
+	if contextTask.Options != contextOptions {
+		t.Fatalf("WithDefaults() options = %+v, want the configured %+v", contextTask.Options, contextOptions)
+	}
+
+	usage := &memoryLLMUsage{}
+	tracker := NewLLMUsageTracker(usage, LLMUsageSettings{})
+	newsTask.Usage, contextTask.Usage = tracker, tracker
+
+	news := &NewsItem{Headline: "Storm closes harbour", Category: "weather"}
+	if _, err := NewGeminiClientWithLLM(newsTask).GenerateFishFromNews(ctx, news); err != nil {
+		t.Fatalf("GenerateFishFromNews() error = %v", err)
+	}
+	contextData := map[string]interface{}{"news": []*NewsItem{news}}
+	if _, err := NewGeminiClientWithLLM(contextTask).GenerateUniqueFishFromContext(ctx, contextData, "storm", ""); err != nil {
+		t.Fatalf("GenerateUniqueFishFromContext() error = %v", err)
+	}
+
+	if got := len(newsProvider.Prompts()); got != 1 {
+		t.Errorf("news provider calls = %d, want 1", got)
+	}
+	if got := len(contextProvider.Prompts()); got != 1 {
+		t.Errorf("context provider calls = %d, want 1", got)
+	}
+	if len(usage.records) != 2 ||
+		usage.records[0].Task != LLMTaskFishFromNews || usage.records[0].Model != DefaultLLMModel ||
+		usage.records[1].Task != LLMTaskFishFromContext || usage.records[1].Model != "llama3" {
+		t.Errorf("usage records = %+v, want one call per task with its own model", usage.records)
+	}
+}
@@ -0,0 +1,259 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Outcomes of an LLM call recorded in LLMUsageRecord
+const (
+	LLMOutcomeSuccess = "success" // The model answered with a usable response
+	LLMOutcomeCached  = "cached"  // The response came from the cache; no tokens were spent
+	LLMOutcomeInvalid = "invalid" // The model answered but the response was rejected
+	LLMOutcomeError   = "error"   // The request failed
+)
+
+// ErrLLMBudgetExhausted is returned instead of calling the model once the daily token budget is spent
+var ErrLLMBudgetExhausted = errors.New("daily LLM token budget exhausted")
+
+// LLMUsageRecord describes a single LLM call
+type LLMUsageRecord struct {
+	Timestamp      time.Time `bson:"timestamp" json:"timestamp"`
+	Task           string    `bson:"task" json:"task"` // One of the LLMTask* names
+	Provider       string    `bson:"provider" json:"provider"`
+	Model          string    `bson:"model" json:"model"`
+	PromptTokens   int       `bson:"prompt_tokens" json:"prompt_tokens"`
+	ResponseTokens int       `bson:"response_tokens" json:"response_tokens"`
+	LatencyMs      int64     `bson:"latency_ms" json:"latency_ms"`
+	Outcome        string    `bson:"outcome" json:"outcome"` // One of the LLMOutcome* values
+	Error          string    `bson:"error,omitempty" json:"error,omitempty"`
+	CostUSD        float64   `bson:"cost_usd" json:"cost_usd"`
+}
+
+// LLMUsageTotal aggregates the calls of one task and model on one UTC day
+type LLMUsageTotal struct {
+	Date           string  `bson:"date" json:"date"` // YYYY-MM-DD
+	Task           string  `bson:"task" json:"task"`
+	Model          string  `bson:"model" json:"model"`
+	Calls          int     `bson:"calls" json:"calls"`
+	CachedCalls    int     `bson:"cached_calls" json:"cached_calls"`
+	FailedCalls    int     `bson:"failed_calls" json:"failed_calls"` // Errors and rejected responses
+	PromptTokens   int     `bson:"prompt_tokens" json:"prompt_tokens"`
+	ResponseTokens int     `bson:"response_tokens" json:"response_tokens"`
+	TotalTokens    int     `bson:"total_tokens" json:"total_tokens"`
+	AvgLatencyMs   float64 `bson:"avg_latency_ms" json:"avg_latency_ms"`
+	CostUSD        float64 `bson:"cost_usd" json:"cost_usd"`
+}
+
+// LLMUsageStore persists LLM usage records
+type LLMUsageStore interface {
+	// SaveLLMUsage stores a single call
+	SaveLLMUsage(ctx context.Context, record *LLMUsageRecord) error
+	// GetLLMUsageTotals aggregates calls made in [from, to) per UTC day, task and model
+	GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]LLMUsageTotal, error)
+}
+
+// LLMUsageSettings configures usage accounting
+type LLMUsageSettings struct {
+	DailyTokenBudget        int     // Prompt plus response tokens allowed per UTC day; zero means unlimited
+	PromptPricePerMillion   float64 // USD per million prompt tokens, for cost estimates
+	ResponsePricePerMillion float64 // USD per million response tokens, for cost estimates
+}
+
+// LLMUsageTracker records every LLM call and enforces the daily token budget.
+// A nil tracker records nothing and never runs out of budget.
+type LLMUsageTracker struct {
+	store       LLMUsageStore
+	settings    LLMUsageSettings
+	day         string // UTC date the token count belongs to
+	tokensToday int
+	mu          sync.Mutex
+}
+
+// NewLLMUsageTracker creates a tracker that stores usage in store
+func NewLLMUsageTracker(store LLMUsageStore, settings LLMUsageSettings) *LLMUsageTracker {
+	return &LLMUsageTracker{
+		store:    store,
+		settings: settings,
+	}
+}
+
+// usageDay returns the UTC date a usage record is counted on
+func usageDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// NextBudgetReset returns when the daily token budget starts over
+func NextBudgetReset(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Settings returns the accounting settings
+func (t *LLMUsageTracker) Settings() LLMUsageSettings {
+	if t == nil {
+		return LLMUsageSettings{}
+	}
+	return t.settings
+}
+
+// TokensUsedToday returns the prompt and response tokens spent since midnight UTC
+func (t *LLMUsageTracker) TokensUsedToday(ctx context.Context) int {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loadToday(ctx, time.Now())
+	return t.tokensToday
+}
+
+// loadToday resets the token count when the day changes, reading any usage already
+// stored for the new day so restarts keep counting. Callers must hold the lock.
+func (t *LLMUsageTracker) loadToday(ctx context.Context, now time.Time) {
+	today := usageDay(now)
+	if t.day == today {
+		return
+	}
+
+	year, month, day := now.UTC().Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	totals, err := t.store.GetLLMUsageTotals(ctx, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		// Try again on the next call rather than assuming nothing was spent
+		log.Printf("Warning: failed to load today's LLM usage: %v", err)
+		return
+	}
+
+	t.day = today
+	t.tokensToday = 0
+	for _, total := range totals {
+		t.tokensToday += total.TotalTokens
+	}
+}
+
+// BudgetExhausted reports whether today's token budget has been spent
+func (t *LLMUsageTracker) BudgetExhausted(ctx context.Context) bool {
+	if t == nil || t.settings.DailyTokenBudget <= 0 {
+		return false
+	}
+	return t.TokensUsedToday(ctx) >= t.settings.DailyTokenBudget
+}
+
+// CheckBudget returns ErrLLMBudgetExhausted once today's token budget has been spent
+func (t *LLMUsageTracker) CheckBudget(ctx context.Context) error {
+	if t.BudgetExhausted(ctx) {
+		return fmt.Errorf("%w (%d of %d tokens used, resets at %s)", ErrLLMBudgetExhausted,
+			t.TokensUsedToday(ctx), t.settings.DailyTokenBudget, NextBudgetReset(time.Now()).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// RecordCall stores the usage of an LLM call. resp may be nil when the call failed;
+// outcome is derived from callErr and resp unless it is given explicitly.
+func (t *LLMUsageTracker) RecordCall(ctx context.Context, task, provider string, opts LLMOptions,
+	resp *LLMResponse, latency time.Duration, outcome string, callErr error) {
+	if t == nil {
+		return
+	}
+
+	record := &LLMUsageRecord{
+		Timestamp: time.Now().UTC(),
+		Task:      task,
+		Provider:  provider,
+		Model:     opts.Model,
+		LatencyMs: latency.Milliseconds(),
+		Outcome:   outcome,
+	}
+	if resp != nil {
+		if resp.Model != "" {
+			record.Model = resp.Model
+		}
+		if !resp.Cached {
+			record.PromptTokens = resp.PromptTokens
+			record.ResponseTokens = resp.ResponseTokens
+		}
+	}
+	if callErr != nil {
+		record.Error = truncateString(callErr.Error(), 500)
+	}
+	if record.Outcome == "" {
+		switch {
+		case callErr != nil:
+			record.Outcome = LLMOutcomeError
+		case resp != nil && resp.Cached:
+			record.Outcome = LLMOutcomeCached
+		default:
+			record.Outcome = LLMOutcomeSuccess
+		}
+	}
+	record.CostUSD = float64(record.PromptTokens)*t.settings.PromptPricePerMillion/1e6 +
+		float64(record.ResponseTokens)*t.settings.ResponsePricePerMillion/1e6
+
+	t.mu.Lock()
+	t.loadToday(ctx, record.Timestamp)
+	if t.day == usageDay(record.Timestamp) {
+		t.tokensToday += record.PromptTokens + record.ResponseTokens
+	}
+	t.mu.Unlock()
+
+	if err := t.store.SaveLLMUsage(ctx, record); err != nil {
+		log.Printf("Warning: failed to record LLM usage: %v", err)
+	}
+}
+
+// AggregateLLMUsage totals usage records per UTC day, task and model, ordered by date, task and model
+func AggregateLLMUsage(records []*LLMUsageRecord) []LLMUsageTotal {
+	type groupKey struct{ date, task, model string }
+	groups := make(map[groupKey]*LLMUsageTotal)
+	latency := make(map[groupKey]int64)
+
+	for _, record := range records {
+		key := groupKey{usageDay(record.Timestamp), record.Task, record.Model}
+		total, ok := groups[key]
+		if !ok {
+			total = &LLMUsageTotal{Date: key.date, Task: key.task, Model: key.model}
+			groups[key] = total
+		}
+
+		total.Calls++
+		switch record.Outcome {
+		case LLMOutcomeCached:
+			total.CachedCalls++
+		case LLMOutcomeError, LLMOutcomeInvalid:
+			total.FailedCalls++
+		}
+		total.PromptTokens += record.PromptTokens
+		total.ResponseTokens += record.ResponseTokens
+		total.TotalTokens += record.PromptTokens + record.ResponseTokens
+		total.CostUSD += record.CostUSD
+		latency[key] += record.LatencyMs
+	}
+
+	totals := make([]LLMUsageTotal, 0, len(groups))
+	for key, total := range groups {
+		total.AvgLatencyMs = float64(latency[key]) / float64(total.Calls)
+		totals = append(totals, *total)
+	}
+	SortLLMUsageTotals(totals)
+	return totals
+}
+
+// SortLLMUsageTotals orders totals by date, task and model
+func SortLLMUsageTotals(totals []LLMUsageTotal) {
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Date != totals[j].Date {
+			return totals[i].Date < totals[j].Date
+		}
+		if totals[i].Task != totals[j].Task {
+			return totals[i].Task < totals[j].Task
+		}
+		return totals[i].Model < totals[j].Model
+	})
+}
@@ -0,0 +1,199 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultTranslationLocales is used when no translation locales are configured
+const DefaultTranslationLocales = "vi"
+
+// Locale describes a language fish are translated into and how to check a translation
+type Locale struct {
+	Code     string // Language code stored with each translation, e.g. "vi"
+	Name     string // English name of the language used in prompts, e.g. "Vietnamese"
+	Guidance string // Extra instructions for the translator
+
+	// Scripts the translated text may use; ASCII, digits, punctuation and symbols are always allowed
+	scripts []*unicode.RangeTable
+	// Script that must appear in the name and description, so an untranslated
+	// English answer is rejected. Nil for languages written in Latin script.
+	requiredScript []*unicode.RangeTable
+}
+
+// supportedLocales lists every language the translation pipeline knows how to validate
+var supportedLocales = map[string]Locale{
+	"vi": {
+		Code:     "vi",
+		Name:     "Vietnamese",
+		Guidance: "Use natural Vietnamese with full diacritics and adapt cultural references for Vietnamese speakers.",
+		scripts:  []*unicode.RangeTable{unicode.Latin},
+	},
+	"es": {
+		Code:     "es",
+		Name:     "Spanish",
+		Guidance: "Use neutral Latin American Spanish.",
+		scripts:  []*unicode.RangeTable{unicode.Latin},
+	},
+	"fr": {
+		Code:    "fr",
+		Name:    "French",
+		scripts: []*unicode.RangeTable{unicode.Latin},
+	},
+	"de": {
+		Code:    "de",
+		Name:    "German",
+		scripts: []*unicode.RangeTable{unicode.Latin},
+	},
+	"pt": {
+		Code:     "pt",
+		Name:     "Portuguese",
+		Guidance: "Use Brazilian Portuguese.",
+		scripts:  []*unicode.RangeTable{unicode.Latin},
+	},
+	"id": {
+		Code:    "id",
+		Name:    "Indonesian",
+		scripts: []*unicode.RangeTable{unicode.Latin},
+	},
+	"ja": {
+		Code:           "ja",
+		Name:           "Japanese",
+		Guidance:       "Write fish names in katakana or kanji as a Japanese game would.",
+		scripts:        []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Latin},
+		requiredScript: []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana},
+	},
+	"ko": {
+		Code:           "ko",
+		Name:           "Korean",
+		scripts:        []*unicode.RangeTable{unicode.Hangul, unicode.Han, unicode.Latin},
+		requiredScript: []*unicode.RangeTable{unicode.Hangul},
+	},
+	"zh": {
+		Code:           "zh",
+		Name:           "Simplified Chinese",
+		scripts:        []*unicode.RangeTable{unicode.Han, unicode.Latin},
+		requiredScript: []*unicode.RangeTable{unicode.Han},
+	},
+	"th": {
+		Code:           "th",
+		Name:           "Thai",
+		scripts:        []*unicode.RangeTable{unicode.Thai, unicode.Latin},
+		requiredScript: []*unicode.RangeTable{unicode.Thai},
+	},
+	"ru": {
+		Code:           "ru",
+		Name:           "Russian",
+		scripts:        []*unicode.RangeTable{unicode.Cyrillic, unicode.Latin},
+		requiredScript: []*unicode.RangeTable{unicode.Cyrillic},
+	},
+}
+
+// LookupLocale returns the supported locale with the given code
+func LookupLocale(code string) (Locale, bool) {
+	locale, ok := supportedLocales[strings.ToLower(strings.TrimSpace(code))]
+	return locale, ok
+}
+
+// ParseLocales parses a comma-separated list of locale codes such as "vi,ja,es"
+func ParseLocales(list string) ([]Locale, error) {
+	var locales []Locale
+	seen := make(map[string]bool)
+
+	for _, code := range strings.Split(list, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+
+		locale, ok := LookupLocale(code)
+		if !ok {
+			return nil, fmt.Errorf("unsupported translation locale '%s'", code)
+		}
+		seen[code] = true
+		locales = append(locales, locale)
+	}
+
+	if len(locales) == 0 {
+		return nil, fmt.Errorf("no translation locales configured")
+	}
+	return locales, nil
+}
+
+// LocaleCodes returns the codes of the given locales
+func LocaleCodes(locales []Locale) []string {
+	codes := make([]string, len(locales))
+	for i, locale := range locales {
+		codes[i] = locale.Code
+	}
+	return codes
+}
+
+// ValidRune reports whether a character may appear in text of this locale
+func (l Locale) ValidRune(r rune) bool {
+	if r <= unicode.MaxASCII || unicode.In(r, unicode.Common, unicode.Inherited) {
+		return true
+	}
+	return unicode.In(r, l.scripts...)
+}
+
+// ValidateText checks that a translated string only uses the scripts of this locale
+func (l Locale) ValidateText(text string) error {
+	for _, r := range text {
+		if !l.ValidRune(r) {
+			return fmt.Errorf("character %U is not valid %s text", r, l.Name)
+		}
+	}
+	return nil
+}
+
+// usesRequiredScript reports whether text contains at least one character of the locale's own script
+func (l Locale) usesRequiredScript(text string) bool {
+	if l.requiredScript == nil {
+		return true
+	}
+	for _, r := range text {
+		if unicode.In(r, l.requiredScript...) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTranslation checks a translation against the rules of this locale. Fields
+// with invalid characters are cleared so they fall back to English, and an error is
+// returned when the name or description is missing or was left untranslated.
+func (l Locale) ValidateTranslation(fields *TranslationFields) error {
+	for name, field := range map[string]*string{
+		"name":             &fields.Name,
+		"description":      &fields.Description,
+		"color":            &fields.Color,
+		"diet":             &fields.Diet,
+		"habitat":          &fields.Habitat,
+		"favorite_weather": &fields.FavoriteWeather,
+		"existence_reason": &fields.ExistenceReason,
+		"effect":           &fields.Effect,
+		"player_effect":    &fields.PlayerEffect,
+	} {
+		if err := l.ValidateText(*field); err != nil {
+			logTranslate("Dropping %s translation of %s: %v", l.Code, name, err)
+			*field = ""
+		}
+	}
+
+	for i, text := range fields.StatEffectTexts {
+		if err := l.ValidateText(text); err != nil {
+			logTranslate("Dropping %s translation of stat effect %d: %v", l.Code, i+1, err)
+			fields.StatEffectTexts[i] = ""
+		}
+	}
+
+	if fields.Name == "" || fields.Description == "" {
+		return fmt.Errorf("%s translation has no valid name or description", l.Name)
+	}
+	if !l.usesRequiredScript(fields.Name + fields.Description) {
+		return fmt.Errorf("%s translation is not written in %s script", l.Name, l.Name)
+	}
+	return nil
+}
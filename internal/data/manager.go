@@ -6,10 +6,10 @@ import (
 	"log"
 	"math"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
-	"unicode/utf8"
 )
 
 // Add colored logging constants at the top of the file
@@ -22,8 +22,6 @@ const (
 	logColorPurple = "\033[35m"
 	logColorCyan   = "\033[36m"
 	logColorWhite  = "\033[37m"
-	// Add a new log color for translations
-	logColorTeal = "\033[36;1m" // Bright cyan for translation logs
 )
 
 // GenerationRequest represents a queued fish generation request
@@ -64,60 +62,56 @@ func logError(format string, v ...interface{}) {
 	log.Printf(logColorRed+"[ERROR] "+format+logColorReset, v...)
 }
 
-// logTranslation logs translation-related messages with teal color
-func logTranslation(format string, v ...interface{}) {
-	log.Printf(logColorTeal+"[TRANSLATE] "+format+logColorReset, v...)
-}
-
 // DatabaseClient defines the interface for database operations
 type DatabaseClient interface {
 	SaveWeatherData(ctx context.Context, weatherInfo *WeatherInfo, regionID, cityID string) error
 	SavePriceData(ctx context.Context, assetType string, price, volume, changePercent, volumeChange float64, source string) error
 	SaveNewsData(ctx context.Context, newsItem *NewsItem) error
 	GetRecentWeatherData(ctx context.Context, regionID string, limit int) ([]*WeatherInfo, error)
+	SaveRegionWeather(ctx context.Context, weather *RegionalWeather) error
+	GetRegionWeather(ctx context.Context, regionID string) (*RegionalWeather, error)
 	GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error)
 	GetRecentNewsData(ctx context.Context, limit int) ([]*NewsItem, error)
-	SaveFishData(ctx context.Context, fishData interface{}) error
+	SaveFishData(ctx context.Context, fish *FishRecord) error
 	// New methods for persistence
 	SaveUsedNewsIDs(ctx context.Context, usedIDs map[string]bool) error
 	GetUsedNewsIDs(ctx context.Context) (map[string]bool, error)
 	SaveGenerationQueue(ctx context.Context, queue []GenerationRequest) error
 	GetGenerationQueue(ctx context.Context) ([]GenerationRequest, error)
-	// Methods for translation
-	GetUntranslatedFish(ctx context.Context, limit int) ([]map[string]interface{}, error)
-	UpdateFishWithTranslation(ctx context.Context, fishID interface{}, translatedFish map[string]interface{}) error
 }
 
 // CollectionSettings holds configuration for data collection
 type CollectionSettings struct {
-	WeatherInterval     time.Duration // 1 hour in production
-	PriceInterval       time.Duration // 6 hours in production
-	NewsInterval        time.Duration // 20 minutes in production
+	Collectors          []ScheduledCollector // Data sources and their intervals, usually built by a CollectorRegistry
 	TestMode            bool
-	GeminiApiKey        string        // API key for Gemini
-	GenerationCooldown  time.Duration // Optional generation cooldown
-	EnableTranslation   bool          // Whether to enable Vietnamese translation
-	TranslationCooldown time.Duration // Cooldown between translations
+	GeminiApiKey        string             // API key for Gemini
+	GenerationCooldown  time.Duration      // Optional generation cooldown
+	FishLLM             LLMTask            // Provider for fish generated from merged news and context; Gemini when unset
+	LLMUsage            *LLMUsageTracker   // Pauses the generation queue once the daily token budget is spent
+	Experiments         *PromptExperiments // Splits fish generated from merged news and context across prompt variants; optional
+	SpeciesGuard        *SpeciesGuard      // Renames or marks as variants fish that duplicate existing species; optional
+	Moderator           *ContentModerator  // Quarantines fish with unsafe content instead of publishing them; optional
+	ReviewGeneratedFish bool               // Save generated fish as drafts that an admin must approve
 }
 
 // DataManager handles data collection across different regions and sources
 type DataManager struct {
-	settings         CollectionSettings
-	db               DatabaseClient
-	weatherCollector *WeatherCollector
-	bitcoinCollector *CryptoCollector
-	goldCollector    *GoldCollector
-	newsCollector    *NewsCollector
-	geminiClient     *GeminiClient
-	translatorClient *TranslatorClient // Add translator client
-	regions          []Region
-	cancelFuncs      []context.CancelFunc
-	mu               sync.Mutex
+	settings     CollectionSettings
+	db           DatabaseClient
+	scheduler    *CollectorScheduler
+	geminiClient *GeminiClient
+	regions      []Region
+	cancelFuncs  []context.CancelFunc
+	mu           sync.Mutex
 	// Store most recent data for each type
-	lastWeatherData *WeatherInfo
+	regionWeather   map[string]*RegionalWeather // Latest weather of each region, guarded by weatherMu
+	astronomy       *Astronomy                  // Latest moon, sun and tides, guarded by weatherMu
+	weatherMu       sync.RWMutex                // Separate from mu, which is held while a fish is generated
 	lastBitcoinData *CryptoPrice
 	lastGoldData    *GoldPrice
 	lastNewsData    *NewsItem
+	lastSignals     map[DataType]Signal // Latest value of each source without special handling, guarded by signalsMu
+	signalsMu       sync.RWMutex        // Separate from mu, so signal events don't wait for a generation
 	// For merged news generation
 	mergedNewsItem  *NewsItem   // For backward compatibility
 	mergedNewsItems []*NewsItem // Store up to 2 additional news items
@@ -134,28 +128,12 @@ type DataManager struct {
 	queueProcessRunning bool
 	// Add WaitGroup to track running goroutines
 	wg sync.WaitGroup
-	// Translation tracking
-	lastTranslation     time.Time
-	translationCooldown time.Duration
 }
 
 // NewDataManager creates a new data manager
-func NewDataManager(settings CollectionSettings, db DatabaseClient,
-	weatherApiKey, newsApiKey, metalPriceApiKey, geminiApiKey string) *DataManager {
-
+func NewDataManager(settings CollectionSettings, db DatabaseClient, geminiApiKey string) *DataManager {
 	regions := PredefinedRegions()
 
-	// Set default intervals if not specified
-	if settings.WeatherInterval == 0 {
-		settings.WeatherInterval = 1 * time.Hour // 1 hour default for weather
-	}
-	if settings.PriceInterval == 0 {
-		settings.PriceInterval = 6 * time.Hour // 6 hours default for prices
-	}
-	if settings.NewsInterval == 0 {
-		settings.NewsInterval = 20 * time.Minute // 20 minutes default for news
-	}
-
 	// Set generation cooldown based on test mode or settings
 	var generationCooldown time.Duration
 	if settings.GenerationCooldown > 0 {
@@ -169,31 +147,14 @@ func NewDataManager(settings CollectionSettings, db DatabaseClient,
 		generationCooldown = 25 * time.Minute // 25 minutes in production
 	}
 
-	// Set translation cooldown or use default
-	var translationCooldown time.Duration
-	if settings.TranslationCooldown > 0 {
-		translationCooldown = settings.TranslationCooldown
-	} else {
-		translationCooldown = 2 * time.Minute // 2 minutes default for translation
-	}
-
-	// Create translator client if translation is enabled
-	var translatorClient *TranslatorClient
-	if settings.EnableTranslation {
-		translatorClient = NewTranslatorClient(geminiApiKey)
-		log.Println("Vietnamese fish translation enabled with cooldown:", translationCooldown)
-	}
-
 	return &DataManager{
 		settings:             settings,
 		db:                   db,
-		weatherCollector:     NewWeatherCollector(weatherApiKey),
-		bitcoinCollector:     NewCryptoCollector(),
-		goldCollector:        NewGoldCollector(metalPriceApiKey),
-		newsCollector:        NewNewsCollector(newsApiKey),
-		geminiClient:         NewGeminiClient(geminiApiKey),
-		translatorClient:     translatorClient,
+		scheduler:            NewCollectorScheduler(settings.Collectors),
+		geminiClient:         NewGeminiClientWithLLM(settings.FishLLM.WithDefaults(LLMTaskFishFromContext, geminiApiKey)),
 		regions:              regions,
+		regionWeather:        make(map[string]*RegionalWeather),
+		lastSignals:          make(map[DataType]Signal),
 		cancelFuncs:          make([]context.CancelFunc, 0),
 		initialDataCollected: false,
 		dataReady:            false,
@@ -205,8 +166,6 @@ func NewDataManager(settings CollectionSettings, db DatabaseClient,
 		mergedNewsItem:       nil, // Initialize to nil
 		mergedNewsItems:      make([]*NewsItem, 0),
 		wg:                   sync.WaitGroup{},
-		lastTranslation:      time.Time{}, // Zero time means no translation has happened yet
-		translationCooldown:  translationCooldown,
 	}
 }
 
@@ -218,74 +177,26 @@ func (m *DataManager) Start(ctx context.Context) {
 	// Immediately collect initial data from all sources
 	m.collectInitialData(baseCtx)
 
-	// Use a single goroutine for all periodic tasks
+	// The scheduler runs every collector on its own interval
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
+		m.scheduler.Run(baseCtx)
+	}()
 
-		// Create tickers for each collection type
-		weatherTicker := time.NewTicker(m.settings.WeatherInterval)
-		priceTicker := time.NewTicker(m.settings.PriceInterval)
-		newsTicker := time.NewTicker(m.settings.NewsInterval)
-
-		defer weatherTicker.Stop()
-		defer priceTicker.Stop()
-		defer newsTicker.Stop()
-
-		// Add a ticker for checking for fish to translate
-		var translationTicker *time.Ticker
-		if m.settings.EnableTranslation && m.translatorClient != nil {
-			translationTicker = time.NewTicker(30 * time.Second)
-			defer translationTicker.Stop()
-		}
+	// Use a single goroutine to handle collected data
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
 
 		for {
-			// Use different select statements based on whether translation is enabled
-			if translationTicker != nil {
-				select {
-				case <-weatherTicker.C:
-					// Collect weather for all regions
-					for _, region := range m.regions {
-						m.collectWeatherDataForRegion(baseCtx, region)
-					}
-
-				case <-priceTicker.C:
-					// Collect price data
-					m.collectPriceData(baseCtx)
-
-				case <-newsTicker.C:
-					// Collect news data
-					m.collectNewsData(baseCtx)
-
-				case <-translationTicker.C:
-					// Check for fish to translate
-					m.checkForFishToTranslate(baseCtx)
-
-				case <-baseCtx.Done():
-					log.Println("Data collection goroutine stopped")
-					return
-				}
-			} else {
-				// Version without translation ticker
-				select {
-				case <-weatherTicker.C:
-					// Collect weather for all regions
-					for _, region := range m.regions {
-						m.collectWeatherDataForRegion(baseCtx, region)
-					}
-
-				case <-priceTicker.C:
-					// Collect price data
-					m.collectPriceData(baseCtx)
-
-				case <-newsTicker.C:
-					// Collect news data
-					m.collectNewsData(baseCtx)
-
-				case <-baseCtx.Done():
-					log.Println("Data collection goroutine stopped")
-					return
-				}
+			select {
+			case event := <-m.scheduler.Events():
+				m.handleEvent(baseCtx, event)
+
+			case <-baseCtx.Done():
+				log.Println("Data collection goroutine stopped")
+				return
 			}
 		}
 	}()
@@ -308,17 +219,16 @@ func (m *DataManager) collectInitialData(ctx context.Context) {
 	// We no longer mark all existing news as used at startup
 	// This allows using older news for generations
 
-	// Collect weather data for each region
-	for _, region := range m.regions {
-		m.collectWeatherDataForRegion(ctx, region)
+	// Collect from every source once; news comes last
+	newsCollected := false
+	for _, event := range m.scheduler.CollectNow(ctx) {
+		if err := m.handleEvent(ctx, event); err == nil && event.Type == NewsData {
+			newsCollected = true
+		}
 	}
 
-	// Collect price data
-	m.collectPriceData(ctx)
-
-	// Collect news data or retrieve from database
-	err := m.collectNewsData(ctx)
-	if err != nil && m.settings.TestMode && m.db != nil {
+	// Retrieve news from the database if none was collected
+	if !newsCollected && m.settings.TestMode && m.db != nil {
 		log.Println("No news received, attempting to use latest news from database...")
 		recentNews, err := m.db.GetRecentNewsData(ctx, 1)
 		if err == nil && len(recentNews) > 0 {
@@ -348,122 +258,197 @@ func (m *DataManager) collectInitialData(ctx context.Context) {
 	log.Println("Initial data collection completed")
 }
 
-// collectWeatherDataForRegion collects weather data for all cities in a region
-func (m *DataManager) collectWeatherDataForRegion(ctx context.Context, region Region) {
-	logWeather("Collecting weather data for region: %s", region.Name)
+// handleEvent saves a collected event and keeps its value for fish generation
+func (m *DataManager) handleEvent(ctx context.Context, event *DataEvent) error {
+	switch event.Type {
+	case WeatherData:
+		return m.handleWeatherEvent(ctx, event)
+	case BitcoinData:
+		return m.handleBitcoinEvent(ctx, event)
+	case GoldData:
+		return m.handleGoldEvent(ctx, event)
+	case NewsData:
+		return m.handleNewsEvent(ctx, event)
+	case AstronomyData:
+		return m.handleAstronomyEvent(ctx, event)
+	default:
+		return m.handleSignalEvent(ctx, event)
+	}
+}
+
+// handleWeatherEvent saves the weather of each city in the event, and the weather of each
+// region aggregated from its cities
+func (m *DataManager) handleWeatherEvent(ctx context.Context, event *DataEvent) error {
+	var collected []*RegionWeather
+	switch value := event.Value.(type) {
+	case []*RegionWeather:
+		collected = value
+	case *WeatherInfo:
+		// Collectors that do not know the regions report a single city
+		collected = []*RegionWeather{{Weather: value}}
+	default:
+		logError("Invalid weather data type: %T", event.Value)
+		return fmt.Errorf("invalid weather data type: %T", event.Value)
+	}
 
-	// For each city in the region, collect weather data
-	for _, cityID := range region.CityIDs {
-		dataEvent, err := m.weatherCollector.Collect(ctx)
-		if err != nil {
-			logError("Error collecting weather data for city %s in region %s: %v",
-				cityID, region.ID, err)
+	var regionIDs []string
+	regionCities := make(map[string][]*WeatherInfo)
+	for _, city := range collected {
+		if city == nil || city.Weather == nil {
 			continue
 		}
 
-		// Cast the value to WeatherInfo
-		weatherInfo, ok := dataEvent.Value.(WeatherInfo)
-		if !ok {
-			// Try pointer type
-			weatherInfoPtr, okPtr := dataEvent.Value.(*WeatherInfo)
-			if !okPtr {
-				logError("Invalid weather data type for city %s", cityID)
-				continue
+		// Save to database
+		if m.db != nil {
+			err := m.db.SaveWeatherData(ctx, city.Weather, city.RegionID, city.CityID)
+			if err != nil {
+				logError("Error saving weather data for city %s: %v", city.CityID, err)
+			} else {
+				logWeather("Weather data saved for city %s in region %s: %s, %.1f°C",
+					city.CityID, city.RegionID, city.Weather.Condition, city.Weather.TempC)
 			}
-			weatherInfo = *weatherInfoPtr
 		}
 
-		// Save to database
+		// Cities without a region are not part of any region's weather
+		if city.RegionID == "" {
+			continue
+		}
+		if _, ok := regionCities[city.RegionID]; !ok {
+			regionIDs = append(regionIDs, city.RegionID)
+		}
+		regionCities[city.RegionID] = append(regionCities[city.RegionID], city.Weather)
+	}
+
+	for _, regionID := range regionIDs {
+		weather := AggregateRegionWeather(regionID, regionCities[regionID])
 		if m.db != nil {
-			err = m.db.SaveWeatherData(ctx, &weatherInfo, region.ID, cityID)
-			if err != nil {
-				logError("Error saving weather data for city %s: %v", cityID, err)
-				continue
+			if err := m.db.SaveRegionWeather(ctx, weather); err != nil {
+				logError("Error saving weather of region %s: %v", regionID, err)
 			}
-			logWeather("Weather data saved for city %s in region %s: %s, %.1f°C",
-				cityID, region.ID, weatherInfo.Condition, weatherInfo.TempC)
 		}
+		logWeather("Weather of region %s from %d cities: %s, %.1f°C, wind up to %.1f km/h (extreme: %t)",
+			regionID, weather.Cities, weather.Condition, weather.TempC, weather.WindKph, weather.IsExtreme)
 
-		// Store the most recent weather data
-		m.lastWeatherData = &weatherInfo
+		m.weatherMu.Lock()
+		m.regionWeather[regionID] = weather
+		m.weatherMu.Unlock()
 	}
 
 	// Mark data as ready
 	m.dataReady = true
+	return nil
 }
 
-// collectPriceData collects price data from all price sources
-func (m *DataManager) collectPriceData(ctx context.Context) {
-	// Collect Bitcoin data
-	btcEvent, err := m.bitcoinCollector.Collect(ctx)
-	if err != nil {
-		logError("Error collecting Bitcoin data: %v", err)
-	} else if m.db != nil {
-		// Try both value and pointer types
-		btcData, ok := btcEvent.Value.(CryptoPrice)
-		if !ok {
-			btcDataPtr, okPtr := btcEvent.Value.(*CryptoPrice)
-			if !okPtr {
-				logError("Invalid Bitcoin data type")
-				return
-			}
-			btcData = *btcDataPtr
+// handleBitcoinEvent saves the Bitcoin price
+func (m *DataManager) handleBitcoinEvent(ctx context.Context, event *DataEvent) error {
+	// Try both value and pointer types
+	btcData, ok := event.Value.(CryptoPrice)
+	if !ok {
+		btcDataPtr, okPtr := event.Value.(*CryptoPrice)
+		if !okPtr {
+			logError("Invalid Bitcoin data type")
+			return fmt.Errorf("invalid bitcoin data type: %T", event.Value)
 		}
+		btcData = *btcDataPtr
+	}
 
-		err = m.db.SavePriceData(ctx, "btc", btcData.PriceUSD, 0, btcData.Change24h, btcData.Volume24h, btcEvent.Source)
+	if m.db != nil {
+		err := m.db.SavePriceData(ctx, "btc", btcData.PriceUSD, 0, btcData.Change24h, btcData.Volume24h, event.Source)
 		if err != nil {
 			logError("Error saving Bitcoin data: %v", err)
-		} else {
-			logBitcoin("Price data saved: $%.2f (%.2f%%)", btcData.PriceUSD, btcData.Change24h)
-			// Store the most recent bitcoin data
-			m.lastBitcoinData = &btcData
+			return err
 		}
+		logBitcoin("Price data saved: $%.2f (%.2f%%)", btcData.PriceUSD, btcData.Change24h)
+		// Store the most recent bitcoin data
+		m.lastBitcoinData = &btcData
 	}
 
-	// Collect Gold data - Try API first, then fall back to mock if needed
-	goldEvent, err := m.goldCollector.Collect(ctx)
-	if err != nil {
-		logError("Error collecting Gold data: %v", err)
-	} else if m.db != nil {
-		// Try both value and pointer types
-		goldData, ok := goldEvent.Value.(GoldPrice)
-		if !ok {
-			goldDataPtr, okPtr := goldEvent.Value.(*GoldPrice)
-			if !okPtr {
-				logError("Invalid Gold data type")
-				return
-			}
-			goldData = *goldDataPtr
-		}
+	// Mark data as ready
+	m.dataReady = true
+	return nil
+}
 
-		// Fix gold price if it's unrealistically low or not a valid number
-		if goldData.PriceUSD < 100 || math.IsInf(goldData.PriceUSD, 0) || math.IsNaN(goldData.PriceUSD) {
-			logError("Gold price invalid ($%.2f), adjusting to standard range", goldData.PriceUSD)
-			goldData.PriceUSD = 1800.0 // Set a reasonable default gold price
+// handleGoldEvent saves the gold price
+func (m *DataManager) handleGoldEvent(ctx context.Context, event *DataEvent) error {
+	// Try both value and pointer types
+	goldData, ok := event.Value.(GoldPrice)
+	if !ok {
+		goldDataPtr, okPtr := event.Value.(*GoldPrice)
+		if !okPtr {
+			logError("Invalid Gold data type")
+			return fmt.Errorf("invalid gold data type: %T", event.Value)
 		}
+		goldData = *goldDataPtr
+	}
 
-		err = m.db.SavePriceData(ctx, "gold", goldData.PriceUSD, 0, goldData.Change24h, 0, goldEvent.Source)
+	// Fix gold price if it's unrealistically low or not a valid number
+	if goldData.PriceUSD < 100 || math.IsInf(goldData.PriceUSD, 0) || math.IsNaN(goldData.PriceUSD) {
+		logError("Gold price invalid ($%.2f), adjusting to standard range", goldData.PriceUSD)
+		goldData.PriceUSD = 1800.0 // Set a reasonable default gold price
+	}
+
+	if m.db != nil {
+		err := m.db.SavePriceData(ctx, "gold", goldData.PriceUSD, 0, goldData.Change24h, 0, event.Source)
 		if err != nil {
 			logError("Error saving Gold data: %v", err)
-		} else {
-			logGold("Price data saved: $%.2f (%.2f%%)", goldData.PriceUSD, goldData.Change24h)
-			// Store the most recent gold data
-			m.lastGoldData = &goldData
+			return err
 		}
+		logGold("Price data saved: $%.2f (%.2f%%)", goldData.PriceUSD, goldData.Change24h)
+		// Store the most recent gold data
+		m.lastGoldData = &goldData
 	}
 
 	// Mark data as ready
 	m.dataReady = true
+	return nil
 }
 
-// collectNewsData collects news data
-func (m *DataManager) collectNewsData(ctx context.Context) error {
-	newsEvent, err := m.newsCollector.Collect(ctx)
-	if err != nil {
-		logError("Error collecting news data: %v", err)
-		return err
+// handleAstronomyEvent keeps the latest moon, sun and tides for the fishing conditions, and
+// as a signal for fish generation
+func (m *DataManager) handleAstronomyEvent(ctx context.Context, event *DataEvent) error {
+	astronomy, ok := event.Value.(*Astronomy)
+	if !ok {
+		logError("Ignoring astronomy data: unexpected type %T", event.Value)
+		return fmt.Errorf("unsupported astronomy data type: %T", event.Value)
+	}
+
+	m.weatherMu.Lock()
+	m.astronomy = astronomy
+	m.weatherMu.Unlock()
+
+	return m.handleSignalEvent(ctx, event)
+}
+
+// handleSignalEvent keeps the latest value of a source without special handling, and saves
+// it as price data when it is a price. Values that are not a Signal are ignored.
+func (m *DataManager) handleSignalEvent(ctx context.Context, event *DataEvent) error {
+	signal, ok := event.Value.(Signal)
+	if !ok {
+		logError("Ignoring %s data: %T is not a signal", event.Type, event.Value)
+		return fmt.Errorf("unsupported %s data type: %T", event.Type, event.Value)
+	}
+
+	if price, ok := signal.(PriceSignal); ok && m.db != nil {
+		asset, priceUSD, change24h, volume24h := price.PricePoint()
+		if err := m.db.SavePriceData(ctx, asset, priceUSD, 0, change24h, volume24h, event.Source); err != nil {
+			logError("Error saving %s data: %v", event.Type, err)
+			return err
+		}
 	}
+	log.Printf("[%s] %s", strings.ToUpper(string(event.Type)), signal.SignalSummary())
+
+	m.signalsMu.Lock()
+	m.lastSignals[event.Type] = signal
+	m.signalsMu.Unlock()
 
+	// Mark data as ready
+	m.dataReady = true
+	return nil
+}
+
+// handleNewsEvent saves a batch of collected news and starts generating fish from it
+func (m *DataManager) handleNewsEvent(ctx context.Context, newsEvent *DataEvent) error {
+	var err error
 	if m.db == nil {
 		return fmt.Errorf("database client is nil")
 	}
@@ -782,10 +767,13 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 		}
 	}
 
-	if m.lastWeatherData != nil {
+	// Fish are generated for a region, in that region's weather
+	region := m.pickRegion()
+	regionWeather := m.RegionWeather(region.ID)
+	if regionWeather != nil {
 		sourcesAvailable++
-		contextSummary = append(contextSummary, fmt.Sprintf("WEATHER: %s, %.1f°C",
-			m.lastWeatherData.Condition, m.lastWeatherData.TempC))
+		contextSummary = append(contextSummary, fmt.Sprintf("WEATHER (%s): %s, %.1f°C",
+			region.Name, regionWeather.Condition, regionWeather.TempC))
 	}
 
 	// Add Bitcoin data
@@ -820,6 +808,30 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 			m.lastGoldData.PriceUSD, changeDirection, m.lastGoldData.Change24h))
 	}
 
+	// Add the other sources, in a stable order, describing the fish's region where they can
+	m.signalsMu.RLock()
+	lastSignals := make(map[DataType]Signal, len(m.lastSignals))
+	for dataType, signal := range m.lastSignals {
+		lastSignals[dataType] = signal
+	}
+	m.signalsMu.RUnlock()
+	signalTypes := make([]string, 0, len(lastSignals))
+	for dataType := range lastSignals {
+		signalTypes = append(signalTypes, string(dataType))
+	}
+	sort.Strings(signalTypes)
+	var signals []string
+	for _, dataType := range signalTypes {
+		sourcesAvailable++
+		signal := lastSignals[DataType(dataType)]
+		if regional, ok := signal.(RegionalSignal); ok {
+			signals = append(signals, regional.RegionSignalSummary(region.ID))
+		} else {
+			signals = append(signals, signal.SignalSummary())
+		}
+	}
+	contextSummary = append(contextSummary, signals...)
+
 	// Print the context summary with divider lines for visibility
 	logFish(strings.Repeat("-", 80))
 	for _, line := range contextSummary {
@@ -829,21 +841,10 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 
 	// We need at least 2 data sources (news is already checked above)
 	if sourcesAvailable < 2 {
-		logError("Insufficient context data for fish generation: only %d/4 sources available (minimum: 2)", sourcesAvailable)
-		return
-	}
-
-	// Check if we have a valid API key
-	if m.settings.GeminiApiKey == "" {
-		logError("Gemini API key required for fish generation")
+		logError("Insufficient context data for fish generation: only %d sources besides news available (minimum: 2)", sourcesAvailable)
 		return
 	}
 
-	logFish("Using Gemini API key (length: %d)", len(m.settings.GeminiApiKey))
-
-	// Create a context with the API key
-	fishCtx := context.WithValue(ctx, "gemini_api_key", m.settings.GeminiApiKey)
-
 	// Prepare rich context for fish generation
 	contextData := map[string]interface{}{
 		"news": m.lastNewsData,
@@ -855,8 +856,8 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 		contextData["merged_news"] = m.mergedNewsItems
 	}
 
-	if m.lastWeatherData != nil {
-		contextData["weather"] = m.lastWeatherData
+	if regionWeather != nil {
+		contextData["weather"] = regionWeather.WeatherInfo()
 	}
 
 	if m.lastBitcoinData != nil {
@@ -867,6 +868,10 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 		contextData["gold"] = m.lastGoldData
 	}
 
+	if len(signals) > 0 {
+		contextData["signals"] = signals
+	}
+
 	// Set cooldown time BEFORE generation to prevent simultaneous generations
 	// This prevents multiple generations from being triggered while one is still in process
 	m.lastFishGeneration = currentTime
@@ -874,7 +879,12 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 	// Generate a unique fish using Gemini with all available context
 	logFish("Generating unique fish using %d data sources and %d news articles (Reason: %s)...",
 		sourcesAvailable, 1+len(m.mergedNewsItems), reason)
-	fishData, err := m.geminiClient.GenerateUniqueFishFromContext(fishCtx, contextData, reason)
+	assignment := m.settings.Experiments.Assign()
+	if assignment.Experiment != "" {
+		logFish("Prompt experiment %s: using variant %s", assignment.Experiment, assignment.Variant)
+	}
+	fishData, err := m.geminiClient.GenerateUniqueFishFromContext(ctx, contextData, reason, assignment.Template)
+	m.settings.Experiments.RecordGeneration(ctx, assignment, err)
 
 	if err != nil {
 		logError("Error generating fish: %v", err)
@@ -898,7 +908,12 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 	source := rand.NewSource(time.Now().UnixNano())
 	rng := rand.New(source)
 
-	// Generate random rarity with weighted distribution
+	// Generate random rarity with weighted distribution. Legendary fish are three times as
+	// likely under a full moon.
+	legendaryChance := 2.0
+	if astronomy := m.Astronomy(); astronomy != nil && astronomy.FullMoon() {
+		legendaryChance = 6.0
+	}
 	rarityRoll := rng.Float64() * 100
 	var rarity string
 	var catchChanceMin, catchChanceMax float64
@@ -917,11 +932,11 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 		rarity = "Rare"
 		catchChanceMin, catchChanceMax = 20, 40
 		rarityMultiplier = 6
-	case rarityRoll < 98: // 8% chance
+	case rarityRoll < 100-legendaryChance: // 8% chance, 4% under a full moon
 		rarity = "Epic"
 		catchChanceMin, catchChanceMax = 10, 20
 		rarityMultiplier = 12
-	default: // 2% chance
+	default: // 2% chance, 6% under a full moon
 		rarity = "Legendary"
 		catchChanceMin, catchChanceMax = 1, 10
 		rarityMultiplier = 25
@@ -1015,101 +1030,83 @@ func (m *DataManager) generateFishFromData(ctx context.Context, reason string) {
 
 	// Save the fish to the database (if DB is available)
 	if m.db != nil {
-		// Choose a random region for the fish
-		regionIndex := int(time.Now().UnixNano()) % len(m.regions)
-		regionID := m.regions[regionIndex].ID
+		regionID := region.ID
 
 		// Create current time once to ensure consistent timestamps
 		timestamp := time.Now()
 
-		// Initialize the usedArticles array
-		usedArticles := make([]map[string]interface{}, 0)
-
-		// Add the primary news article
-		if m.lastNewsData != nil {
-			usedArticles = append(usedArticles, map[string]interface{}{
-				"headline":  m.lastNewsData.Headline,
-				"source":    m.lastNewsData.Source,
-				"url":       m.lastNewsData.URL,
-				"category":  m.lastNewsData.Category,
-				"sentiment": m.lastNewsData.Sentiment,
-				"keywords":  m.lastNewsData.Keywords,
-				"published": m.lastNewsData.PublishedAt,
-				"used_at":   timestamp,
-				"is_merged": false,
-			})
-		}
-
-		// Add all merged news items to the used articles
-		if m.mergedNewsItems != nil && len(m.mergedNewsItems) > 0 {
-			for _, news := range m.mergedNewsItems {
-				if news != nil {
-					usedArticles = append(usedArticles, map[string]interface{}{
-						"headline":  news.Headline,
-						"source":    news.Source,
-						"url":       news.URL,
-						"category":  news.Category,
-						"sentiment": news.Sentiment,
-						"keywords":  news.Keywords,
-						"published": news.PublishedAt,
-						"used_at":   timestamp,
-						"is_merged": true,
-					})
-				}
+		// Record every news article that inspired this fish
+		usedArticles := []UsedArticle{NewUsedArticle(m.lastNewsData, timestamp, false)}
+		for _, news := range m.mergedNewsItems {
+			if news != nil {
+				usedArticles = append(usedArticles, NewUsedArticle(news, timestamp, true))
 			}
 		}
 
 		// Log the number of articles being saved
 		logFish("Saving fish with %d used articles", len(usedArticles))
 
-		// Create a structured fish data object that matches the MongoDB schema exactly
-		fish := map[string]interface{}{
-			"name":              fishData.Name,
-			"description":       fishData.Description,
-			"rarity":            rarity,
-			"length":            lengthMeters,
-			"weight":            weightKg,
-			"color":             fishData.Color,
-			"habitat":           fishData.Habitat,
-			"diet":              fishData.Diet,
-			"generated_at":      timestamp,
-			"is_ai_generated":   true,
-			"data_source":       "gemini-ai",
-			"region_id":         regionID,
-			"generation_reason": reason,
-			"favorite_weather":  fishData.FavoriteWeather,
-			"catch_chance":      catchChance,
-			"existence_reason":  fishData.ExistenceReason,
-			"used_articles":     usedArticles,
-			"stat_effects": []map[string]interface{}{
+		fish := &FishRecord{
+			Name:             fishData.Name,
+			Description:      fishData.Description,
+			Appearance:       fishData.Appearance,
+			Rarity:           rarity,
+			Length:           lengthMeters,
+			Weight:           weightKg,
+			Value:            fishValue,
+			Color:            fishData.Color,
+			Habitat:          fishData.Habitat,
+			Diet:             fishData.Diet,
+			Effect:           fishData.Effect,
+			FavoriteWeather:  fishData.FavoriteWeather,
+			CatchChance:      catchChance,
+			ExistenceReason:  fishData.ExistenceReason,
+			OriginContext:    fishData.OriginContext,
+			PromptVersion:    fishData.PromptVersion,
+			Experiment:       assignment.Experiment,
+			Variant:          assignment.Variant,
+			GeneratedAt:      timestamp,
+			IsAIGenerated:    true,
+			DataSource:       "gemini-ai",
+			RegionID:         regionID,
+			GenerationReason: reason,
+			UsedArticles:     usedArticles,
+			StatEffects: []FishStatEffect{
 				{
-					"effect_type":  "environment",
-					"modifier":     fishData.CatchChance / 100.0,
-					"description":  fishData.Effect,
-					"weather_type": fishData.FavoriteWeather,
+					EffectType:  "environment",
+					Modifier:    catchChance / 100.0,
+					Description: fishData.Effect,
+					WeatherType: fishData.FavoriteWeather,
 				},
 				{
-					"effect_type": "player",
-					"modifier":    fishData.Value / 1000.0,
-					"description": "Affects player abilities based on fish value",
+					EffectType:  "player",
+					Modifier:    fishValue / 1000.0,
+					Description: "Affects player abilities based on fish value",
 				},
 			},
 		}
 
-		// Call the database method to save the fish
-		if saveFishMethod, ok := m.db.(interface {
-			SaveFishData(context.Context, interface{}) error
-		}); ok {
-			err := saveFishMethod.SaveFishData(ctx, fish)
-			if err != nil {
-				logError("Error saving generated fish: %v", err)
-				// Log more details for debugging
-				logError("Fish data: %+v", fish)
-			} else {
-				logFish("Fish saved to database: %s (ID: %s)", fishData.Name, regionID)
-			}
+		// Keep the catalog free of duplicate species
+		m.settings.SpeciesGuard.Resolve(ctx, fish, m.geminiClient)
+
+		// Hold back fish with unsafe content, and every fish when review is required
+		fish.Status = FishStatusPublished
+		if m.settings.ReviewGeneratedFish {
+			fish.Status = FishStatusDraft
+		}
+		if flags := m.settings.Moderator.Moderate(ctx, fish); len(flags) > 0 {
+			fish.Status = FishStatusQuarantined
+			fish.ModerationFlags = flags
+		}
+
+		if err := m.db.SaveFishData(ctx, fish); err != nil {
+			logError("Error saving generated fish: %v", err)
+			// Log more details for debugging
+			logError("Fish data: %+v", fish)
+		} else if fish.Status == FishStatusQuarantined {
+			logFish("Fish quarantined for review: %s (ID: %s, flagged by %s)", fish.Name, fish.ID, fish.ModerationFlags[0].Rule)
 		} else {
-			logError("Database client doesn't support SaveFishData method")
+			logFish("Fish saved to database: %s (ID: %s, region: %s, status: %s)", fish.Name, fish.ID, regionID, fish.Status)
 		}
 	}
 
@@ -1155,7 +1152,7 @@ func (m *DataManager) findUnusedNewsItem(ctx context.Context) (*NewsItem, error)
 }
 
 // generateFishWithLock is a wrapper that handles locking for automated fish generation
-// (used by collectNewsData)
+// (used by collectInitialData)
 func (m *DataManager) generateFishWithLock(ctx context.Context, reason string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -1216,13 +1213,47 @@ func (m *DataManager) Stop() {
 	log.Println("Data Manager stopped")
 }
 
+// RegionWeather returns the latest weather of a region, or nil if none has been collected
+func (m *DataManager) RegionWeather(regionID string) *RegionalWeather {
+	m.weatherMu.RLock()
+	defer m.weatherMu.RUnlock()
+	return m.regionWeather[regionID]
+}
+
+// Astronomy returns the latest moon phase, sun and tides, or nil if none has been collected
+func (m *DataManager) Astronomy() *Astronomy {
+	m.weatherMu.RLock()
+	defer m.weatherMu.RUnlock()
+	return m.astronomy
+}
+
+// pickRegion chooses a random region to generate a fish for, preferring the regions whose
+// weather has been collected
+func (m *DataManager) pickRegion() Region {
+	var candidates []Region
+	m.weatherMu.RLock()
+	for _, region := range m.regions {
+		if m.regionWeather[region.ID] != nil {
+			candidates = append(candidates, region)
+		}
+	}
+	m.weatherMu.RUnlock()
+	if len(candidates) == 0 {
+		candidates = m.regions
+	}
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// CollectorHealth returns the health state of every collector
+func (m *DataManager) CollectorHealth() []CollectorHealth {
+	return m.scheduler.Health()
+}
+
 // GetCollectors returns all data collectors managed by this data manager
 func (m *DataManager) GetCollectors() []DataCollector {
-	collectors := []DataCollector{
-		m.weatherCollector,
-		m.bitcoinCollector,
-		m.goldCollector,
-		m.newsCollector,
+	collectors := make([]DataCollector, 0, len(m.scheduler.Collectors()))
+	for _, scheduled := range m.scheduler.Collectors() {
+		collectors = append(collectors, scheduled.Collector)
 	}
 	return collectors
 }
@@ -1386,7 +1417,7 @@ func (m *DataManager) checkPendingNewsForGeneration(ctx context.Context) {
 	}
 }
 
-// loadPersistentState loads used news IDs and queued generation requests from the database
+// loadPersistentState loads used news IDs, queued generation requests and the weather of each region from the database
 func (m *DataManager) loadPersistentState(ctx context.Context) {
 	if m.db == nil {
 		logError("Cannot load persistent state: database not available")
@@ -1414,6 +1445,20 @@ func (m *DataManager) loadPersistentState(ctx context.Context) {
 		m.mu.Unlock()
 		logFish("Loaded %d generation requests from database", len(queue))
 	}
+
+	// Load the weather of each region
+	for _, region := range m.regions {
+		weather, err := m.db.GetRegionWeather(ctx, region.ID)
+		if err != nil {
+			logError("Failed to load weather of region %s: %v", region.ID, err)
+			continue
+		}
+		if weather != nil {
+			m.weatherMu.Lock()
+			m.regionWeather[region.ID] = weather
+			m.weatherMu.Unlock()
+		}
+	}
 }
 
 // savePersistentState saves current state to the database for crash recovery
@@ -1508,6 +1553,7 @@ func (m *DataManager) processGenerationQueue() {
 		}
 	}()
 
+	budgetPaused := false
 	for {
 		// Check cooldown status first
 		m.mu.Lock()
@@ -1539,6 +1585,26 @@ func (m *DataManager) processGenerationQueue() {
 			// After waiting, continue to processing
 		}
 
+		// Hold queued requests until the daily token budget resets
+		if m.settings.LLMUsage.BudgetExhausted(context.Background()) {
+			resetAt := NextBudgetReset(time.Now())
+			if !budgetPaused {
+				logFish("Daily LLM token budget exhausted, pausing generation queue until %s",
+					resetAt.Format(time.RFC3339))
+				budgetPaused = true
+			}
+			waitTime := time.Until(resetAt)
+			if waitTime > 15*time.Minute {
+				waitTime = 15 * time.Minute
+			}
+			time.Sleep(waitTime)
+			continue
+		}
+		if budgetPaused {
+			logFish("Daily LLM token budget reset, resuming generation queue")
+			budgetPaused = false
+		}
+
 		// Only check for news if we're not on cooldown anymore
 		m.mu.Lock()
 		hasQueueItems := len(m.generationQueue) > 0
@@ -1582,302 +1648,3 @@ func (m *DataManager) processGenerationQueue() {
 		m.generateFishWithLock(ctx, request.Reason)
 	}
 }
-
-// checkForFishToTranslate checks for recently generated fish that need translation
-func (m *DataManager) checkForFishToTranslate(ctx context.Context) {
-	// Skip if translation is disabled or translator is not initialized
-	if !m.settings.EnableTranslation || m.translatorClient == nil {
-		return
-	}
-
-	// Check cooldown
-	m.mu.Lock()
-	currentTime := time.Now()
-	timeSinceLastTranslation := currentTime.Sub(m.lastTranslation)
-
-	if !m.lastTranslation.IsZero() && timeSinceLastTranslation < m.translationCooldown {
-		m.mu.Unlock()
-		return // Still on cooldown
-	}
-	m.mu.Unlock()
-
-	// Find untranslated fish
-	untranslatedFish, err := m.db.GetUntranslatedFish(ctx, 1)
-	if err != nil {
-		logError("Error finding untranslated fish: %v", err)
-		return
-	}
-
-	if len(untranslatedFish) == 0 {
-		// No untranslated fish found, nothing to do
-		return
-	}
-
-	// Get the first untranslated fish
-	fishToTranslate := untranslatedFish[0]
-
-	// Log that we're about to attempt translation
-	logTranslation("Found untranslated fish, preparing for translation")
-
-	// Pre-sanitize ALL fields in the source fish
-	// This is especially important for origin context that might contain news headlines with formatting issues
-	for key, value := range fishToTranslate {
-		if strValue, ok := value.(string); ok {
-			fishToTranslate[key] = SanitizeUTF8(strValue)
-		}
-	}
-
-	// Check specifically for fish containing the problematic "MISSING" text
-	var containsMissing bool
-	for key, val := range fishToTranslate {
-		if strVal, ok := val.(string); ok {
-			if strings.Contains(strVal, "MISSING") || strings.Contains(strVal, "%!") {
-				logTranslation("Found problematic string in field '%s': %s", key, strVal)
-				containsMissing = true
-				fishToTranslate[key] = SanitizeUTF8(strVal)
-			}
-		}
-	}
-
-	if containsMissing {
-		logTranslation("This fish contained problematic formatting characters, applying extra sanitization")
-
-		// Double sanitize the used_articles field which might contain problematic headlines
-		if usedArticles, ok := fishToTranslate["used_articles"].([]interface{}); ok {
-			for i, article := range usedArticles {
-				if articleMap, ok := article.(map[string]interface{}); ok {
-					for field, value := range articleMap {
-						if strValue, ok := value.(string); ok {
-							// Apply extra sanitization for article headlines
-							articleMap[field] = SanitizeUTF8(strValue)
-						}
-					}
-					// Update the article in place
-					usedArticles[i] = articleMap
-				}
-			}
-			// Update the used_articles field
-			fishToTranslate["used_articles"] = usedArticles
-		}
-	}
-
-	// Pre-validate all string fields to ensure valid UTF-8
-	// This helps identify problematic fields before we try to use them
-	for key, value := range fishToTranslate {
-		if strValue, ok := value.(string); ok {
-			if !utf8.ValidString(strValue) {
-				logError("Found invalid UTF-8 in field '%s', attempting to fix", key)
-				fishToTranslate[key] = SanitizeUTF8(strValue)
-			}
-		}
-	}
-
-	// Start translation
-	logTranslation("Translating fish: %s", extractStringFieldSafely(fishToTranslate, "name", "Unnamed Fish"))
-
-	// Extract stat effects for structured translation
-	var statEffects []map[string]interface{}
-	if statEffectsInterface, ok := fishToTranslate["stat_effects"].([]interface{}); ok {
-		for _, effectInterface := range statEffectsInterface {
-			if effect, ok := effectInterface.(map[string]interface{}); ok {
-				sanitizedEffect := make(map[string]interface{})
-				for k, v := range effect {
-					if strValue, ok := v.(string); ok {
-						sanitizedEffect[k] = SanitizeUTF8(strValue)
-					} else {
-						sanitizedEffect[k] = v
-					}
-				}
-				statEffects = append(statEffects, sanitizedEffect)
-			}
-		}
-	}
-
-	// Prepare text for each stat effect to be translated
-	statEffectTexts := make([]string, 0, len(statEffects)*2) // Allow for descriptions and weather types
-	statEffectTypes := make([]string, 0, len(statEffects)*2)
-
-	// First pass: get all descriptions (keeping their index aligned with the stat effects array)
-	for _, effect := range statEffects {
-		// Add the description to the list (even if empty, to maintain indexing)
-		if description, ok := effect["description"].(string); ok && description != "" {
-			statEffectTexts = append(statEffectTexts, description)
-			statEffectTypes = append(statEffectTypes, "description")
-		} else {
-			// Add an empty placeholder to maintain indexing
-			statEffectTexts = append(statEffectTexts, "")
-			statEffectTypes = append(statEffectTypes, "description")
-		}
-	}
-
-	// Second pass: add all weather_type fields (will be indexed after all descriptions)
-	for _, effect := range statEffects {
-		if weatherType, ok := effect["weather_type"].(string); ok && weatherType != "" {
-			statEffectTexts = append(statEffectTexts, weatherType)
-			statEffectTypes = append(statEffectTypes, "weather_type")
-		}
-	}
-
-	// Extract fields to translate - add defensive extraction with defaults
-	fieldsToTranslate := TranslationFields{
-		Name:            extractStringFieldSafely(fishToTranslate, "name", "Unnamed Fish"),
-		Description:     extractStringFieldSafely(fishToTranslate, "description", "No description available"),
-		Color:           extractStringFieldSafely(fishToTranslate, "color", "Unknown color"),
-		Diet:            extractStringFieldSafely(fishToTranslate, "diet", "Unknown diet"),
-		Habitat:         extractStringFieldSafely(fishToTranslate, "habitat", "Unknown habitat"),
-		FavoriteWeather: extractStringFieldSafely(fishToTranslate, "favorite_weather", "Unknown weather"),
-		ExistenceReason: extractStringFieldSafely(fishToTranslate, "existence_reason", "Unknown reason"),
-		Effect:          "",
-		PlayerEffect:    "Affects player abilities",
-		StatEffectTexts: statEffectTexts,
-	}
-
-	// Set the cooldown before translation to prevent multiple translations at once
-	m.mu.Lock()
-	m.lastTranslation = currentTime
-	m.mu.Unlock()
-
-	// Double safety check before translation
-	if m.translatorClient == nil {
-		logError("Translation client became nil after check, aborting translation")
-		return
-	}
-
-	// Final validation of all translation fields
-	fieldsToTranslate.Name = SanitizeUTF8(fieldsToTranslate.Name)
-	fieldsToTranslate.Description = SanitizeUTF8(fieldsToTranslate.Description)
-	fieldsToTranslate.Color = SanitizeUTF8(fieldsToTranslate.Color)
-	fieldsToTranslate.Diet = SanitizeUTF8(fieldsToTranslate.Diet)
-	fieldsToTranslate.Habitat = SanitizeUTF8(fieldsToTranslate.Habitat)
-	fieldsToTranslate.FavoriteWeather = SanitizeUTF8(fieldsToTranslate.FavoriteWeather)
-	fieldsToTranslate.ExistenceReason = SanitizeUTF8(fieldsToTranslate.ExistenceReason)
-	fieldsToTranslate.Effect = SanitizeUTF8(fieldsToTranslate.Effect)
-	fieldsToTranslate.PlayerEffect = SanitizeUTF8(fieldsToTranslate.PlayerEffect)
-
-	// Sanitize all stat effect texts
-	for i, text := range fieldsToTranslate.StatEffectTexts {
-		fieldsToTranslate.StatEffectTexts[i] = SanitizeUTF8(text)
-	}
-
-	// Translate the fish
-	translatedFields, err := m.translatorClient.TranslateFish(ctx, fieldsToTranslate)
-	if err != nil {
-		logError("Error translating fish: %v", err)
-		return
-	}
-
-	// Create a new map for the translation instead of modifying the original
-	translatedFish := make(map[string]interface{})
-
-	// Only copy essential fields that we know are safe
-	safeFields := []string{"_id", "name", "description", "rarity", "length", "weight",
-		"region_id", "data_source", "generated_at", "is_ai_generated", "generation_reason"}
-
-	for _, field := range safeFields {
-		if val, ok := fishToTranslate[field]; ok {
-			translatedFish[field] = val
-		}
-	}
-
-	// Explicitly set translated fields as new fields with _vi suffix
-	translatedFish["name_vi"] = SanitizeUTF8(translatedFields.Name)
-	translatedFish["description_vi"] = SanitizeUTF8(translatedFields.Description)
-	translatedFish["color_vi"] = SanitizeUTF8(translatedFields.Color)
-	translatedFish["diet_vi"] = SanitizeUTF8(translatedFields.Diet)
-	translatedFish["existence_reason_vi"] = SanitizeUTF8(translatedFields.ExistenceReason)
-	translatedFish["is_translated"] = true
-	translatedFish["translated_at"] = time.Now()
-
-	// Set habitat if it exists
-	if translatedFields.Habitat != "" {
-		translatedFish["habitat_vi"] = SanitizeUTF8(translatedFields.Habitat)
-	}
-
-	// Create a new stat_effects_vi array with translations
-	statEffectsVi := make([]map[string]interface{}, 0, len(statEffects))
-	statEffectIndex := 0
-
-	// Process translated stat effects
-	for _, effect := range statEffects {
-		// Create a deep copy of the original effect
-		translatedEffect := make(map[string]interface{})
-		for k, v := range effect {
-			translatedEffect[k] = v
-		}
-
-		// Get the description from the original effect
-		_, hasDesc := effect["description"].(string)
-
-		// Add Vietnamese translations
-		// Get the corresponding translated text from the position in statEffectTexts
-		// that matches the original position in statEffects array
-		if hasDesc && statEffectIndex < len(translatedFields.StatEffectTexts) {
-			// Add the translated description
-			translatedDesc := translatedFields.StatEffectTexts[statEffectIndex]
-			if translatedDesc != "" {
-				translatedEffect["description_vi"] = SanitizeUTF8(translatedDesc)
-				logTranslation("Translated effect %d: %s", statEffectIndex+1, truncateString(translatedDesc, 50))
-			}
-			statEffectIndex++
-		}
-
-		// Also handle weather_type if present
-		if _, hasWeather := effect["weather_type"].(string); hasWeather {
-			// For weather_type, we add it as a separate entry in StatEffectTexts
-			// So we need to find the right index - it should be after all descriptions
-			weatherIndex := len(statEffects) + statEffectIndex
-			if weatherIndex < len(translatedFields.StatEffectTexts) {
-				translatedEffect["weather_type_vi"] = SanitizeUTF8(translatedFields.StatEffectTexts[weatherIndex])
-				logTranslation("Translated weather type %d: %s", statEffectIndex+1,
-					truncateString(translatedFields.StatEffectTexts[weatherIndex], 50))
-			}
-			statEffectIndex++
-		}
-
-		statEffectsVi = append(statEffectsVi, translatedEffect)
-	}
-
-	// Save both the original and translated stat effects
-	if len(statEffects) > 0 {
-		translatedFish["stat_effects"] = statEffects
-	}
-	if len(statEffectsVi) > 0 {
-		translatedFish["stat_effects_vi"] = statEffectsVi
-	}
-
-	translatedFish["favorite_weather_vi"] = SanitizeUTF8(translatedFields.FavoriteWeather)
-
-	// Final check of all translated fields to ensure they are valid UTF-8
-	for key, value := range translatedFish {
-		if strValue, ok := value.(string); ok {
-			if !utf8.ValidString(strValue) {
-				logTranslation("Final sanitization for field '%s'", key)
-				translatedFish[key] = SanitizeUTF8(strValue)
-			}
-		}
-	}
-
-	// Save the translated fish back to the database
-	fishID := fishToTranslate["_id"]
-	err = m.db.UpdateFishWithTranslation(ctx, fishID, translatedFish)
-	if err != nil {
-		logError("Error saving translated fish: %v", err)
-		return
-	}
-
-	logTranslation("Successfully translated fish '%s' to Vietnamese", fishToTranslate["name"])
-}
-
-// Helper function to safely extract string fields with a default value
-func extractStringFieldSafely(data map[string]interface{}, field string, defaultValue string) string {
-	if value, ok := data[field]; ok && value != nil {
-		if strValue, ok := value.(string); ok {
-			if utf8.ValidString(strValue) {
-				return strValue
-			}
-			// If not valid UTF-8, sanitize it
-			return SanitizeUTF8(strValue)
-		}
-	}
-	return defaultValue
-}
@@ -9,9 +9,9 @@ import (
 	"time"
 )
 
-// MockWeatherCollector collects mock weather data
+// MockWeatherCollector collects mock weather data for the cities of the fishing regions
 type MockWeatherCollector struct {
-	locations  []string
+	regions    []Region
 	conditions []string
 	rand       *rand.Rand
 }
@@ -22,39 +22,44 @@ func NewMockWeatherCollector() *MockWeatherCollector {
 	r := rand.New(source)
 
 	return &MockWeatherCollector{
-		locations:  []string{"New York", "London", "Tokyo", "Sydney", "Paris", "Berlin", "Rio de Janeiro"},
+		regions:    PredefinedRegions(),
 		conditions: []string{"Sunny", "Rainy", "Cloudy", "Stormy", "Windy", "Snowy", "Clear"},
 		rand:       r,
 	}
 }
 
-// Collect generates mock weather data
+// Collect generates mock weather data for every city of a random region. The event value
+// is a []*RegionWeather.
 func (c *MockWeatherCollector) Collect(ctx context.Context) (*DataEvent, error) {
-	// Create random weather data
-	location := c.locations[c.rand.Intn(len(c.locations))]
-	condition := c.conditions[c.rand.Intn(len(c.conditions))]
-	tempC := 10.0 + c.rand.Float64()*25.0  // 10-35 degrees C
-	humidity := 30 + c.rand.Intn(70)       // 30-100% humidity
-	windKph := 5.0 + c.rand.Float64()*45.0 // 5-50 km/h
-
-	// 10% chance of extreme weather
-	isExtreme := c.rand.Float64() < 0.1
-
-	weatherInfo := &WeatherInfo{
-		Condition: condition,
-		Location:  location,
-		TempC:     tempC,
-		Humidity:  humidity,
-		WindKph:   windKph,
-		IsExtreme: isExtreme,
+	region := c.regions[c.rand.Intn(len(c.regions))]
+
+	var collected []*RegionWeather
+	for _, cityID := range region.CityIDs {
+		// Create random weather data
+		condition := c.conditions[c.rand.Intn(len(c.conditions))]
+		tempC := 10.0 + c.rand.Float64()*25.0  // 10-35 degrees C
+		humidity := 30 + c.rand.Intn(70)       // 30-100% humidity
+		windKph := 5.0 + c.rand.Float64()*45.0 // 5-50 km/h
+
+		// 10% chance of extreme weather
+		isExtreme := c.rand.Float64() < 0.1
+
+		weatherInfo := &WeatherInfo{
+			Condition: condition,
+			Location:  region.Name,
+			TempC:     tempC,
+			Humidity:  humidity,
+			WindKph:   windKph,
+			IsExtreme: isExtreme,
+		}
+		collected = append(collected, &RegionWeather{RegionID: region.ID, CityID: cityID, Weather: weatherInfo})
 	}
 
 	return &DataEvent{
 		Type:      WeatherData,
-		Value:     weatherInfo,
+		Value:     collected,
 		Timestamp: time.Now(),
 		Source:    "mock-weather-api",
-		Raw:       weatherInfo,
 	}, nil
 }
 
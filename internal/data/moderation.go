@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rules that can flag a fish during moderation
+const (
+	ModerationRuleBlocklist = "blocklist"  // A blocklisted word or phrase appears in a field
+	ModerationRulePattern   = "pattern"    // A pattern rule matches a field
+	ModerationRuleSelfCheck = "self_check" // The LLM judged the fish unsafe
+)
+
+// Verdicts of the LLM self-check
+const (
+	ContentVerdictSafe   = "safe"
+	ContentVerdictUnsafe = "unsafe"
+)
+
+// ModerationFlag explains why moderation quarantined a fish
+type ModerationFlag struct {
+	Rule  string `bson:"rule" json:"rule"`                       // One of the ModerationRule* rules
+	Field string `bson:"field,omitempty" json:"field,omitempty"` // JSON name of the flagged field; empty for the self-check
+	Term  string `bson:"term,omitempty" json:"term,omitempty"`   // Blocklisted term or pattern name
+	Match string `bson:"match" json:"match"`                     // Text that matched, or the self-check's reason or failure
+}
+
+// ModerationPattern is a named regular expression that flags the fields it matches
+type ModerationPattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"` // Go regexp syntax; add (?i) for case-insensitive rules
+}
+
+// ModerationRules are the blocklist and pattern rules applied to generated fish
+type ModerationRules struct {
+	Blocklist []string            `json:"blocklist"` // Words and phrases matched case-insensitively, not as part of a longer word
+	Patterns  []ModerationPattern `json:"patterns"`
+}
+
+// DefaultModerationRules returns the built-in rules, aimed at fish that make light of
+// tragedies in the news or name real politicians
+func DefaultModerationRules() ModerationRules {
+	return ModerationRules{
+		Blocklist: []string{
+			"victim", "victims", "massacre", "genocide", "holocaust", "terrorist", "terrorism",
+			"suicide", "murder", "murdered", "corpse", "death toll", "mass shooting", "bombing",
+			"hostage", "rape", "nazi",
+		},
+		Patterns: []ModerationPattern{
+			{
+				Name:    "named_official",
+				Pattern: `\b(?:President|Prime Minister|Senator|Governor|Chancellor|Minister|Congressman|Congresswoman|Mayor|Premier|Ayatollah)\s+\p{Lu}[\p{L}'-]+`,
+			},
+			{
+				Name:    "death_joke",
+				Pattern: `(?i)\b(?:died|dies|dead|killed|drowned|perished)\b[^.!?]{0,60}\b(?:lol|haha|hilarious|funny|joke)\b`,
+			},
+		},
+	}
+}
+
+// LoadModerationRules reads rules from a JSON file and adds them to the built-in rules
+func LoadModerationRules(path string) (ModerationRules, error) {
+	rules := DefaultModerationRules()
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read moderation rules file: %v", err)
+	}
+
+	var extra ModerationRules
+	if err := json.Unmarshal(contents, &extra); err != nil {
+		return rules, fmt.Errorf("failed to parse moderation rules file %s: %v", path, err)
+	}
+
+	rules.Blocklist = append(rules.Blocklist, extra.Blocklist...)
+	rules.Patterns = append(rules.Patterns, extra.Patterns...)
+	return rules, nil
+}
+
+// ContentReview is the LLM's judgement of whether a fish is fit to publish
+type ContentReview struct {
+	Verdict string `json:"verdict"` // ContentVerdictSafe or ContentVerdictUnsafe
+	Reason  string `json:"reason"`
+}
+
+// ContentReviewer asks an LLM to review a fish the rules let through
+type ContentReviewer interface {
+	ReviewFishContent(ctx context.Context, fish *FishRecord) (*ContentReview, error)
+}
+
+// compiledModerationRule is a blocklist term or pattern ready for matching
+type compiledModerationRule struct {
+	rule    string // ModerationRuleBlocklist or ModerationRulePattern
+	term    string
+	pattern *regexp.Regexp
+}
+
+// find returns the text the rule matches, without the characters around a blocklist term,
+// or "" when it does not match
+func (r compiledModerationRule) find(text string) string {
+	match := r.pattern.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	if r.rule == ModerationRuleBlocklist {
+		return match[1]
+	}
+	return match[0]
+}
+
+// ContentModerator checks the name, description, effect and existence reason of generated
+// fish before they are published. A nil ContentModerator lets every fish through.
+type ContentModerator struct {
+	rules    []compiledModerationRule
+	reviewer ContentReviewer
+}
+
+// NewContentModerator compiles the rules. The reviewer is optional; when set, fish that
+// pass the rules are also reviewed by the LLM.
+func NewContentModerator(rules ModerationRules, reviewer ContentReviewer) (*ContentModerator, error) {
+	moderator := &ContentModerator{reviewer: reviewer}
+
+	for _, term := range rules.Blocklist {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		// Unlike \b, these also delimit terms that start or end with punctuation, like "c++"
+		moderator.rules = append(moderator.rules, compiledModerationRule{
+			rule:    ModerationRuleBlocklist,
+			term:    term,
+			pattern: regexp.MustCompile(`(?i)(?:^|\W)(` + regexp.QuoteMeta(term) + `)(?:\W|$)`),
+		})
+	}
+
+	for _, pattern := range rules.Patterns {
+		if pattern.Name == "" {
+			return nil, fmt.Errorf("moderation pattern %q has no name", pattern.Pattern)
+		}
+		compiled, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid moderation pattern %q: %v", pattern.Name, err)
+		}
+		moderator.rules = append(moderator.rules, compiledModerationRule{
+			rule:    ModerationRulePattern,
+			term:    pattern.Name,
+			pattern: compiled,
+		})
+	}
+
+	return moderator, nil
+}
+
+// Moderate returns the reasons the fish should be quarantined, or nil when it may be published.
+// The LLM self-check only runs when no rule matched; if it fails, the fish is quarantined.
+func (m *ContentModerator) Moderate(ctx context.Context, fish *FishRecord) []ModerationFlag {
+	if m == nil {
+		return nil
+	}
+
+	fields := []struct {
+		name, text string
+	}{
+		{"name", fish.Name},
+		{"description", fish.Description},
+		{"effect", fish.Effect},
+		{"existence_reason", fish.ExistenceReason},
+	}
+
+	var flags []ModerationFlag
+	for _, field := range fields {
+		for _, rule := range m.rules {
+			if match := rule.find(field.text); match != "" {
+				flags = append(flags, ModerationFlag{Rule: rule.rule, Field: field.name, Term: rule.term, Match: match})
+			}
+		}
+	}
+	if len(flags) > 0 || m.reviewer == nil {
+		return flags
+	}
+
+	review, err := m.reviewer.ReviewFishContent(ctx, fish)
+	if err != nil {
+		log.Printf("Warning: content self-check of fish %q failed, quarantining it: %v", fish.Name, err)
+		return []ModerationFlag{{Rule: ModerationRuleSelfCheck, Match: fmt.Sprintf("the self-check failed: %v", err)}}
+	}
+	if review.Verdict == ContentVerdictUnsafe {
+		return []ModerationFlag{{Rule: ModerationRuleSelfCheck, Match: review.Reason}}
+	}
+	return nil
+}
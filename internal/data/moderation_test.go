@@ -0,0 +1,153 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeContentReviewer answers every self-check with the same review or error
+type fakeContentReviewer struct {
+	review *ContentReview
+	err    error
+	calls  int
+}
+
+func (r *fakeContentReviewer) ReviewFishContent(ctx context.Context, fish *FishRecord) (*ContentReview, error) {
+	r.calls++
+	return r.review, r.err
+}
+
+// plainFish is a generated fish that no built-in rule flags
+func plainFish() *FishRecord {
+	return &FishRecord{
+		Name:            "Drizzle Dart",
+		Description:     "A quick silver fish that follows light rain across the bay.",
+		Effect:          "Catches are faster while it drizzles",
+		ExistenceReason: "Evolved in shallow water where the rain stirs up plankton",
+	}
+}
+
+func TestContentModeratorDefaultRules(t *testing.T) {
+	moderator, err := NewContentModerator(DefaultModerationRules(), nil)
+	if err != nil {
+		t.Fatalf("NewContentModerator() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(fish *FishRecord)
+		rule  string // Empty when the fish passes
+		field string
+		term  string
+		match string
+	}{
+		{"plain fish", func(*FishRecord) {}, "", "", "", ""},
+		{"longer word", func(f *FishRecord) { f.Description = "Nobody is victimized by this gentle fish." }, "", "", "", ""},
+		{"blocklisted word", func(f *FishRecord) { f.Description = "It swims among the Victims of the flood." },
+			ModerationRuleBlocklist, "description", "victims", "Victims"},
+		{"blocklisted phrase", func(f *FishRecord) { f.ExistenceReason = "Born from the death toll, of course." },
+			ModerationRuleBlocklist, "existence_reason", "death toll", "death toll"},
+		{"named official", func(f *FishRecord) { f.Name = "President Marlow Minnow" },
+			ModerationRulePattern, "name", "named_official", "President Marlow"},
+		{"death joke", func(f *FishRecord) { f.Effect = "Everyone who drowned here was hilarious" },
+			ModerationRulePattern, "effect", "death_joke", "drowned here was hilarious"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fish := plainFish()
+			tt.edit(fish)
+			flags := moderator.Moderate(context.Background(), fish)
+			if tt.rule == "" {
+				if len(flags) != 0 {
+					t.Errorf("Moderate() = %+v, want no flags", flags)
+				}
+				return
+			}
+			want := ModerationFlag{Rule: tt.rule, Field: tt.field, Term: tt.term, Match: tt.match}
+			if len(flags) != 1 || flags[0] != want {
+				t.Errorf("Moderate() = %+v, want [%+v]", flags, want)
+			}
+		})
+	}
+}
+
+func TestContentModeratorBlocklistTermsWithPunctuation(t *testing.T) {
+	moderator, err := NewContentModerator(ModerationRules{Blocklist: []string{"c++", "#doomscroll", "u.s.a."}}, nil)
+	if err != nil {
+		t.Fatalf("NewContentModerator() error = %v", err)
+	}
+
+	tests := []struct {
+		description string
+		match       string // Empty when nothing should match
+	}{
+		{"It only eats C++ manuals.", "C++"},
+		{"#doomscroll until dawn", "#doomscroll"},
+		{"Found off the coast of the U.S.A.", "U.S.A."},
+		{"It eats c and nothing else.", ""},
+		{"Not a #doomscrolling fish", ""},
+	}
+
+	for _, tt := range tests {
+		fish := plainFish()
+		fish.Description = tt.description
+		flags := moderator.Moderate(context.Background(), fish)
+		switch {
+		case tt.match == "" && len(flags) != 0:
+			t.Errorf("Moderate(%q) = %+v, want no flags", tt.description, flags)
+		case tt.match != "" && (len(flags) != 1 || flags[0].Match != tt.match):
+			t.Errorf("Moderate(%q) = %+v, want a flag matching %q", tt.description, flags, tt.match)
+		}
+	}
+}
+
+func TestContentModeratorSelfCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		reviewer *fakeContentReviewer
+		fish     func() *FishRecord
+		calls    int
+		match    string // Part of the self-check flag's match; empty for no flag
+	}{
+		{"safe", &fakeContentReviewer{review: &ContentReview{Verdict: ContentVerdictSafe}}, plainFish, 1, ""},
+		{"unsafe", &fakeContentReviewer{review: &ContentReview{Verdict: ContentVerdictUnsafe, Reason: "mocks a disaster"}}, plainFish, 1, "mocks a disaster"},
+		{"failed", &fakeContentReviewer{err: errors.New("provider unavailable")}, plainFish, 1, "self-check failed: provider unavailable"},
+		{"rules matched first", &fakeContentReviewer{err: errors.New("not called")}, func() *FishRecord {
+			fish := plainFish()
+			fish.Description = "A terrorist of the reef"
+			return fish
+		}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderator, err := NewContentModerator(DefaultModerationRules(), tt.reviewer)
+			if err != nil {
+				t.Fatalf("NewContentModerator() error = %v", err)
+			}
+			flags := moderator.Moderate(context.Background(), tt.fish())
+			if tt.reviewer.calls != tt.calls {
+				t.Errorf("self-check ran %d times, want %d", tt.reviewer.calls, tt.calls)
+			}
+
+			var selfCheck []ModerationFlag
+			for _, flag := range flags {
+				if flag.Rule == ModerationRuleSelfCheck {
+					selfCheck = append(selfCheck, flag)
+				}
+			}
+			if tt.match == "" {
+				if len(selfCheck) != 0 {
+					t.Errorf("Moderate() = %+v, want no self-check flag", flags)
+				}
+				return
+			}
+			if len(flags) != 1 || len(selfCheck) != 1 || !strings.Contains(selfCheck[0].Match, tt.match) {
+				t.Errorf("Moderate() = %+v, want one self-check flag containing %q", flags, tt.match)
+			}
+		})
+	}
+}
@@ -36,16 +36,23 @@ type NewsCollector struct {
 	currentIndex  int
 	lastHeadlines map[string]bool // Track recently used headlines to avoid duplicates
 	client        *http.Client
+	httpOptions   HTTPOptions
 }
 
 // NewNewsCollector creates a new news data collector using the NewsAPI
 func NewNewsCollector(apiKey string) *NewsCollector {
+	return NewNewsCollectorWithHTTP(apiKey, HTTPOptions{})
+}
+
+// NewNewsCollectorWithHTTP creates a news data collector that makes its requests through the given options
+func NewNewsCollectorWithHTTP(apiKey string, opts HTTPOptions) *NewsCollector {
 	return &NewsCollector{
 		apiKey:        apiKey,
 		categories:    []string{"business", "technology", "science", "health", "entertainment"},
 		currentIndex:  0,
 		lastHeadlines: make(map[string]bool),
-		client:        &http.Client{Timeout: 10 * time.Second},
+		client:        opts.client(),
+		httpOptions:   opts,
 	}
 }
 
@@ -123,10 +130,10 @@ func (c *NewsCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	c.currentIndex = (c.currentIndex + 1) % len(c.categories)
 
 	// Construct API URL
-	url := fmt.Sprintf(
-		"https://newsapi.org/v2/top-headlines?category=%s&language=en&pageSize=30&apiKey=%s",
+	url := c.httpOptions.url("https://newsapi.org", fmt.Sprintf(
+		"/v2/top-headlines?category=%s&language=en&pageSize=30&apiKey=%s",
 		category, c.apiKey,
-	)
+	))
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -137,13 +144,13 @@ func (c *NewsCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	// Make the request
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Parse the response
@@ -154,7 +161,7 @@ func (c *NewsCollector) Collect(ctx context.Context) (*DataEvent, error) {
 
 	// Check if we got any articles
 	if len(newsAPIResponse.Articles) == 0 {
-		return nil, fmt.Errorf("no news articles returned")
+		return nil, fmt.Errorf("%w: no news articles returned", ErrNoNewData)
 	}
 
 	// Create a batch of processed news items
@@ -202,7 +209,7 @@ func (c *NewsCollector) Collect(ctx context.Context) (*DataEvent, error) {
 
 	// If we couldn't find any new articles, return an error
 	if len(newsItems) == 0 {
-		return nil, fmt.Errorf("no new headlines found after processing")
+		return nil, fmt.Errorf("%w: no new headlines found after processing", ErrNoNewData)
 	}
 
 	return &DataEvent{
@@ -23,17 +23,24 @@ type EIAResponse struct {
 
 // OilPriceCollector collects real oil price data from EIA API
 type OilPriceCollector struct {
-	apiKey    string
-	lastPrice float64
-	client    *http.Client
+	apiKey      string
+	lastPrice   float64
+	client      *http.Client
+	httpOptions HTTPOptions
 }
 
 // NewOilPriceCollector creates a new oil price data collector using the EIA API
 func NewOilPriceCollector(apiKey string) *OilPriceCollector {
+	return NewOilPriceCollectorWithHTTP(apiKey, HTTPOptions{})
+}
+
+// NewOilPriceCollectorWithHTTP creates an oil price data collector that makes its requests through the given options
+func NewOilPriceCollectorWithHTTP(apiKey string, opts HTTPOptions) *OilPriceCollector {
 	return &OilPriceCollector{
-		apiKey:    apiKey,
-		lastPrice: 0, // Will be set on first collection
-		client:    &http.Client{Timeout: 10 * time.Second},
+		apiKey:      apiKey,
+		lastPrice:   0, // Will be set on first collection
+		client:      opts.client(),
+		httpOptions: opts,
 	}
 }
 
@@ -41,10 +48,10 @@ func NewOilPriceCollector(apiKey string) *OilPriceCollector {
 func (c *OilPriceCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	// Use WTI Crude Oil Price (PET.RWTC.D)
 	seriesID := "PET.RWTC.D"
-	url := fmt.Sprintf(
-		"https://api.eia.gov/v2/seriesData/%s?api_key=%s&frequency=daily&data[0]=value&start=2023-01-01&sort[0][column]=period&sort[0][direction]=desc&offset=0&length=2",
+	url := c.httpOptions.url("https://api.eia.gov", fmt.Sprintf(
+		"/v2/seriesData/%s?api_key=%s&frequency=daily&data[0]=value&start=2023-01-01&sort[0][column]=period&sort[0][direction]=desc&offset=0&length=2",
 		seriesID, c.apiKey,
-	)
+	))
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -55,13 +62,13 @@ func (c *OilPriceCollector) Collect(ctx context.Context) (*DataEvent, error) {
 	// Make the request
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, err
 	}
 
 	// Parse the response
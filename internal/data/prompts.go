@@ -0,0 +1,251 @@
+package data
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// defaultPromptFiles holds the built-in prompt templates, one per LLM task
+//
+//go:embed prompts/*.tmpl
+var defaultPromptFiles embed.FS
+
+// promptVersionPattern matches the version comment every template starts with, e.g. {{/* version: 3 */}}
+var promptVersionPattern = regexp.MustCompile(`^\s*\{\{-?\s*/\*\s*version:\s*(\S+)\s*\*/\s*-?\}\}`)
+
+// Prompt templates that are not tied to an LLM task
+const (
+	PromptFishRename     = "fish_rename"     // Asks for a new name when a fish's name is already taken
+	PromptFishModeration = "fish_moderation" // Asks whether a fish is fit to publish
+)
+
+// promptFuncs are the functions available to prompt templates
+var promptFuncs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// NewsFishPrompt is the data of the fish_from_news template
+type NewsFishPrompt struct {
+	Headline  string
+	Category  string
+	Sentiment string // positive, negative or neutral
+	Schema    string // JSON Schema the response must satisfy
+}
+
+// PromptNews is a news item as shown in a prompt
+type PromptNews struct {
+	Headline  string
+	Category  string
+	Sentiment string // positive, negative or neutral
+}
+
+// ContextFishPrompt is the data of the fish_from_context template
+type ContextFishPrompt struct {
+	Date         string      // Current date, e.g. "January 2, 2006"
+	Reason       string      // Why the fish is being generated
+	News         *PromptNews // Primary news item; nil when there is none
+	RelatedNews  []PromptNews
+	EconomicNews bool // Any of the news is about business, economy or finance
+	Bitcoin      *CryptoPrice
+	Gold         *GoldPrice
+	Weather      *WeatherInfo
+	Signals      []string // One-line summaries of the other collected sources, such as the oil price
+	Theme        string   // Theme inferred from all headlines, set when there is related news
+	Schema       string   // JSON Schema the response must satisfy
+}
+
+// FishRenamePrompt is the data of the fish_rename template
+type FishRenamePrompt struct {
+	Name        string
+	Description string
+	Appearance  string
+	TakenNames  []string // Names the new name must not repeat
+	Schema      string   // JSON Schema the response must satisfy
+}
+
+// FishModerationPrompt is the data of the fish_moderation template
+type FishModerationPrompt struct {
+	Name            string
+	Description     string
+	Effect          string
+	ExistenceReason string
+	Headlines       []string // News headlines that inspired the fish
+	Schema          string   // JSON Schema the response must satisfy
+}
+
+// TranslationPrompt is the data of the translation template
+type TranslationPrompt struct {
+	Locale Locale
+	Fields TranslationFields
+}
+
+// promptTemplate is a parsed template and the file it was loaded from
+type promptTemplate struct {
+	template *template.Template
+	version  string    // Declared in the template's version comment
+	path     string    // Empty for built-in templates
+	modTime  time.Time // Modification time of the file when it was loaded
+}
+
+// PromptLibrary renders the prompt of each LLM task from a text/template file.
+// Files in the template directory override the built-in templates and are
+// reloaded when they change, so prompts can be edited without a restart.
+type PromptLibrary struct {
+	dir       string
+	templates map[string]*promptTemplate
+	seen      map[string]time.Time // Modification time of each file when last read; zero when missing
+	mu        sync.Mutex
+}
+
+var (
+	defaultPrompts     *PromptLibrary
+	defaultPromptsOnce sync.Once
+)
+
+// DefaultPromptLibrary returns the library of built-in templates
+func DefaultPromptLibrary() *PromptLibrary {
+	defaultPromptsOnce.Do(func() {
+		library, err := NewPromptLibrary("")
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in prompt template: %v", err))
+		}
+		defaultPrompts = library
+	})
+	return defaultPrompts
+}
+
+// NewPromptLibrary loads the prompt templates, preferring <name>.tmpl files in dir
+// over the built-in ones. An empty dir uses only the built-in templates.
+func NewPromptLibrary(dir string) (*PromptLibrary, error) {
+	library := &PromptLibrary{
+		dir:       dir,
+		templates: make(map[string]*promptTemplate),
+		seen:      make(map[string]time.Time),
+	}
+
+	for _, name := range []string{LLMTaskFishFromNews, LLMTaskFishFromContext, LLMTaskTranslation, PromptFishRename, PromptFishModeration} {
+		tmpl, err := library.load(name)
+		if err != nil {
+			return nil, err
+		}
+		library.templates[name] = tmpl
+		library.seen[name] = tmpl.modTime
+	}
+
+	return library, nil
+}
+
+// load parses the template of a task from the template directory, or the built-in one
+func (l *PromptLibrary) load(name string) (*promptTemplate, error) {
+	loaded := &promptTemplate{}
+	var source []byte
+
+	if l.dir != "" {
+		path := filepath.Join(l.dir, name+".tmpl")
+		info, err := os.Stat(path)
+		if err == nil {
+			if source, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("failed to read prompt template %s: %v", path, err)
+			}
+			loaded.path = path
+			loaded.modTime = info.ModTime()
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read prompt template %s: %v", path, err)
+		}
+	}
+	if source == nil {
+		var err error
+		if source, err = defaultPromptFiles.ReadFile("prompts/" + name + ".tmpl"); err != nil {
+			return nil, fmt.Errorf("no built-in prompt template for %s: %v", name, err)
+		}
+	}
+
+	match := promptVersionPattern.FindSubmatch(source)
+	if match == nil {
+		return nil, fmt.Errorf("prompt template %s must start with a version comment like {{/* version: 1 */}}", name)
+	}
+	loaded.version = string(match[1])
+
+	tmpl, err := template.New(name).Funcs(promptFuncs).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %v", name, err)
+	}
+	loaded.template = tmpl
+
+	return loaded, nil
+}
+
+// current returns the template of a task, reloading it first if its file changed.
+// A template that no longer parses is reported and the previous one kept.
+func (l *PromptLibrary) current(name string) (*promptTemplate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tmpl, ok := l.templates[name]
+	if !ok {
+		// Variant templates, such as those of prompt experiments, are loaded on first use
+		loaded, err := l.load(name)
+		if err != nil {
+			return nil, err
+		}
+		l.templates[name] = loaded
+		l.seen[name] = loaded.modTime
+		return loaded, nil
+	}
+	if l.dir == "" {
+		return tmpl, nil
+	}
+
+	// Reload when the file was edited, added or removed
+	var modTime time.Time
+	info, err := os.Stat(filepath.Join(l.dir, name+".tmpl"))
+	if err == nil {
+		modTime = info.ModTime()
+	} else if !os.IsNotExist(err) {
+		log.Printf("Warning: failed to check prompt template %s: %v", name, err)
+		return tmpl, nil
+	}
+	if modTime.Equal(l.seen[name]) {
+		return tmpl, nil
+	}
+	l.seen[name] = modTime
+
+	reloaded, err := l.load(name)
+	if err != nil {
+		log.Printf("Warning: keeping prompt template %s version %s: %v", name, tmpl.version, err)
+		return tmpl, nil
+	}
+	log.Printf("Reloaded prompt template %s (version %s -> %s)", name, tmpl.version, reloaded.version)
+	l.templates[name] = reloaded
+	return reloaded, nil
+}
+
+// Preload loads a template that is not one of the task templates, such as the prompt
+// of an experiment variant, so a missing or invalid file is reported at startup
+func (l *PromptLibrary) Preload(name string) error {
+	_, err := l.current(name)
+	return err
+}
+
+// Render executes the template of a task and returns the prompt together with the
+// template's version, in the form "<task>@<version>"
+func (l *PromptLibrary) Render(name string, data interface{}) (string, string, error) {
+	tmpl, err := l.current(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	var prompt bytes.Buffer
+	if err := tmpl.template.Execute(&prompt, data); err != nil {
+		return "", "", fmt.Errorf("failed to render prompt template %s version %s: %v", name, tmpl.version, err)
+	}
+	return prompt.String(), name + "@" + tmpl.version, nil
+}
@@ -1,5 +1,11 @@
 package data
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 // Region represents an ocean region with specific characteristics
 type Region struct {
 	ID          string   `json:"id" bson:"_id"`
@@ -7,7 +13,8 @@ type Region struct {
 	Description string   `json:"description" bson:"description"`
 	Location    Location `json:"location" bson:"location"`
 	Tags        []string `json:"tags" bson:"tags"`
-	CityIDs     []string `json:"city_ids" bson:"city_ids"` // OpenWeatherMap city IDs
+	CityIDs     []string `json:"city_ids" bson:"city_ids"`   // OpenWeatherMap city IDs
+	TimeZone    string   `json:"time_zone" bson:"time_zone"` // IANA time zone of the region's waters
 	Temperature Range    `json:"temperature" bson:"temperature"`
 	Depth       Range    `json:"depth" bson:"depth"`
 	Salinity    Range    `json:"salinity" bson:"salinity"`
@@ -33,6 +40,7 @@ func PredefinedRegions() []Region {
 			Name:        "North Atlantic",
 			Description: "Cold, deep waters with diverse marine life",
 			Location:    Location{Latitude: 45.0, Longitude: -30.0},
+			TimeZone:    "Atlantic/Azores",
 			Tags:        []string{"cold", "deep", "temperate"},
 			CityIDs: []string{
 				"5128581", // New York
@@ -49,6 +57,7 @@ func PredefinedRegions() []Region {
 			Name:        "Tropical Pacific",
 			Description: "Warm, clear waters with vibrant coral reefs",
 			Location:    Location{Latitude: 0.0, Longitude: 160.0},
+			TimeZone:    "Pacific/Guadalcanal",
 			Tags:        []string{"warm", "tropical", "coral"},
 			CityIDs: []string{
 				"1850147", // Tokyo
@@ -65,6 +74,7 @@ func PredefinedRegions() []Region {
 			Name:        "Mediterranean Sea",
 			Description: "Warm, saltier waters with rich history and biodiversity",
 			Location:    Location{Latitude: 35.0, Longitude: 18.0},
+			TimeZone:    "Europe/Malta",
 			Tags:        []string{"warm", "salty", "historic"},
 			CityIDs: []string{
 				"2988507", // Paris
@@ -81,6 +91,7 @@ func PredefinedRegions() []Region {
 			Name:        "Arctic Ocean",
 			Description: "Extremely cold waters with unique ice-adapted species",
 			Location:    Location{Latitude: 80.0, Longitude: 0.0},
+			TimeZone:    "Arctic/Longyearbyen",
 			Tags:        []string{"frigid", "icy", "extreme"},
 			CityIDs: []string{
 				"3413829", // Reykjavik
@@ -97,6 +108,7 @@ func PredefinedRegions() []Region {
 			Name:        "South Pacific",
 			Description: "Pristine waters with diverse island ecosystems",
 			Location:    Location{Latitude: -20.0, Longitude: -170.0},
+			TimeZone:    "Pacific/Niue",
 			Tags:        []string{"tropical", "island", "diverse"},
 			CityIDs: []string{
 				"2147714", // Sydney
@@ -111,6 +123,18 @@ func PredefinedRegions() []Region {
 	}
 }
 
+// Zone returns the region's time zone, or a fixed zone from its longitude when the time
+// zone database is unavailable
+func (r Region) Zone() *time.Location {
+	if r.TimeZone != "" {
+		if loc, err := time.LoadLocation(r.TimeZone); err == nil {
+			return loc
+		}
+	}
+	offset := int(math.Round(r.Location.Longitude / 15))
+	return time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*3600)
+}
+
 // GetRegionByID returns a region by its ID
 func GetRegionByID(id string) (Region, bool) {
 	for _, region := range PredefinedRegions() {
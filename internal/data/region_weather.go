@@ -0,0 +1,88 @@
+package data
+
+import "time"
+
+// RegionalWeather is the weather of a fishing region, aggregated from the weather of its cities
+type RegionalWeather struct {
+	RegionID  string    `bson:"region_id" json:"region_id"`
+	Condition string    `bson:"condition" json:"condition"` // Most common condition among the cities
+	TempC     float64   `bson:"temp_c" json:"temp_c"`       // Mean temperature
+	Humidity  int       `bson:"humidity" json:"humidity"`   // Mean humidity
+	WindKph   float64   `bson:"wind_kph" json:"wind_kph"`   // Strongest wind
+	IsExtreme bool      `bson:"is_extreme" json:"is_extreme"`
+	Cities    int       `bson:"cities" json:"cities"` // Cities the weather was aggregated from
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// AggregateRegionWeather combines the weather of a region's cities into its dominant
+// condition, mean temperature and humidity, and strongest wind. The region's weather is
+// extreme when any of its cities' weather is. Returns nil without cities.
+func AggregateRegionWeather(regionID string, cities []*WeatherInfo) *RegionalWeather {
+	counts := make(map[string]int)
+	var conditions []string // In order of first appearance, so ties go to the earlier city
+	var tempSum float64
+	var humiditySum, collected int
+	var maxWind float64
+	var extreme bool
+	for _, city := range cities {
+		if city == nil {
+			continue
+		}
+		if counts[city.Condition] == 0 {
+			conditions = append(conditions, city.Condition)
+		}
+		counts[city.Condition]++
+		tempSum += city.TempC
+		humiditySum += city.Humidity
+		if city.WindKph > maxWind {
+			maxWind = city.WindKph
+		}
+		extreme = extreme || city.IsExtreme
+		collected++
+	}
+	if collected == 0 {
+		return nil
+	}
+
+	dominant := conditions[0]
+	for _, condition := range conditions[1:] {
+		if counts[condition] > counts[dominant] {
+			dominant = condition
+		}
+	}
+
+	return &RegionalWeather{
+		RegionID:  regionID,
+		Condition: dominant,
+		TempC:     tempSum / float64(collected),
+		Humidity:  humiditySum / collected,
+		WindKph:   maxWind,
+		IsExtreme: extreme,
+		Cities:    collected,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// WeatherInfo returns the region's weather in the shape the collectors and prompts use,
+// located at the region's name
+func (w *RegionalWeather) WeatherInfo() *WeatherInfo {
+	location := w.RegionID
+	if region, ok := GetRegionByID(w.RegionID); ok {
+		location = region.Name
+	}
+	return &WeatherInfo{
+		Condition: w.Condition,
+		Location:  location,
+		TempC:     w.TempC,
+		Humidity:  w.Humidity,
+		WindKph:   w.WindKph,
+		IsExtreme: w.IsExtreme,
+	}
+}
+
+// IsExtremeWeather reports whether a condition, temperature or wind speed in km/h is extreme.
+// Collectors and storage both use it, so stored weather reads back as extreme when it was.
+func IsExtremeWeather(condition string, tempC, windKph float64) bool {
+	return tempC > 35 || tempC < -10 || windKph > 72 ||
+		condition == "Thunderstorm" || condition == "Tornado" || condition == "Hurricane"
+}
@@ -0,0 +1,102 @@
+package data
+
+import "testing"
+
+func TestAggregateRegionWeather(t *testing.T) {
+	tests := []struct {
+		name      string
+		cities    []*WeatherInfo
+		condition string
+		tempC     float64
+		humidity  int
+		windKph   float64
+		extreme   bool
+		count     int
+	}{
+		{
+			name: "dominant condition",
+			cities: []*WeatherInfo{
+				{Condition: "Rain", TempC: 10, Humidity: 90, WindKph: 20},
+				{Condition: "Clear", TempC: 14, Humidity: 60, WindKph: 35},
+				{Condition: "Rain", TempC: 12, Humidity: 81, WindKph: 10},
+			},
+			condition: "Rain", tempC: 12, humidity: 77, windKph: 35, count: 3,
+		},
+		{
+			name: "tie goes to the earlier city",
+			cities: []*WeatherInfo{
+				{Condition: "Clouds", TempC: 20, WindKph: 5},
+				{Condition: "Clear", TempC: 25, WindKph: 15},
+				{Condition: "Clear", TempC: 24},
+				{Condition: "Clouds", TempC: 23},
+			},
+			condition: "Clouds", tempC: 23, windKph: 15, count: 4,
+		},
+		{
+			name: "one extreme city makes the region extreme",
+			cities: []*WeatherInfo{
+				{Condition: "Clear", TempC: 18, WindKph: 10},
+				{Condition: "Thunderstorm", TempC: 22, WindKph: 40, IsExtreme: true},
+				{Condition: "Clear", TempC: 20, WindKph: 12},
+			},
+			condition: "Clear", tempC: 20, windKph: 40, extreme: true, count: 3,
+		},
+		{
+			name: "nil cities are skipped",
+			cities: []*WeatherInfo{
+				nil,
+				{Condition: "Snow", TempC: -4, Humidity: 70, WindKph: 25},
+				nil,
+			},
+			condition: "Snow", tempC: -4, humidity: 70, windKph: 25, count: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AggregateRegionWeather("north_atlantic", tt.cities)
+			if got == nil {
+				t.Fatal("AggregateRegionWeather() = nil")
+			}
+			if got.RegionID != "north_atlantic" || got.Condition != tt.condition || got.TempC != tt.tempC ||
+				got.Humidity != tt.humidity || got.WindKph != tt.windKph || got.IsExtreme != tt.extreme || got.Cities != tt.count {
+				t.Errorf("AggregateRegionWeather() = %+v, want %s at %v°C, %d%% humidity, %v km/h wind, extreme %v, from %d cities",
+					got, tt.condition, tt.tempC, tt.humidity, tt.windKph, tt.extreme, tt.count)
+			}
+			if got.UpdatedAt.IsZero() {
+				t.Error("UpdatedAt is not set")
+			}
+		})
+	}
+
+	for _, cities := range [][]*WeatherInfo{nil, {}, {nil, nil}} {
+		if got := AggregateRegionWeather("north_atlantic", cities); got != nil {
+			t.Errorf("AggregateRegionWeather(%v) = %+v, want nil", cities, got)
+		}
+	}
+}
+
+func TestIsExtremeWeather(t *testing.T) {
+	tests := []struct {
+		condition string
+		tempC     float64
+		windKph   float64
+		want      bool
+	}{
+		{"Clear", 20, 10, false},
+		{"Thunderstorm", 20, 10, true},
+		{"Tornado", 20, 10, true},
+		{"Clear", 35.5, 10, true},
+		{"Clear", 35, 10, false},
+		{"Snow", -10.5, 10, true},
+		{"Snow", -10, 10, false},
+		{"Clouds", 15, 73, true},
+		{"Clouds", 15, 72, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsExtremeWeather(tt.condition, tt.tempC, tt.windKph); got != tt.want {
+			t.Errorf("IsExtremeWeather(%q, %v, %v) = %v, want %v", tt.condition, tt.tempC, tt.windKph, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package data_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fish-generate/internal/data"
+	"fish-generate/internal/storage"
+)
+
+// replayedFish is the scripted model's answer to every fish prompt
+const replayedFish = `{"name": "Replay Rockling", "description": "A patient fish that lives off yesterday's headlines and never misses a rerun.",
+"appearance": "Scales that flicker like an old screen", "color": "silver and blue", "diet": "recorded plankton", "habitat": "archived reefs",
+"effect": "Repeats the last catch", "favorite_weather": "clear", "existence_reason": "Evolved where the same tide comes back every day"}`
+
+// TestDataManagerReplaysFixtures runs the built-in collectors against the recorded API
+// responses in testdata/http, then checks that their data was stored and used for a fish
+func TestDataManagerReplaysFixtures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	transport, err := data.NewFixtureTransport(data.FixtureModeReplay, "../../testdata/http")
+	if err != nil {
+		t.Fatalf("NewFixtureTransport() error = %v", err)
+	}
+	configs := make(map[string]data.CollectorConfig)
+	for _, name := range data.DefaultCollectorRegistry().Names() {
+		configs[name] = data.CollectorConfig{
+			Enabled:    true,
+			Interval:   time.Hour,  // Only the initial collection runs during the test
+			APIKey:     "replayed", // Left out of fixture names, so any key matches
+			HTTP:       data.HTTPOptions{Transport: transport},
+			Resilience: data.ResilienceSettings{MaxAttempts: 1},
+		}
+	}
+	collectors, err := data.DefaultCollectorRegistry().Build(configs)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	memory, err := storage.NewMemoryDB("")
+	if err != nil {
+		t.Fatalf("NewMemoryDB() error = %v", err)
+	}
+	db := storage.NewMongoDBAdapter(memory)
+
+	provider := data.NewScriptedProvider(replayedFish, replayedFish, replayedFish)
+	manager := data.NewDataManager(data.CollectionSettings{
+		Collectors:         collectors,
+		TestMode:           true, // Generates a fish as soon as the initial data is in
+		GenerationCooldown: time.Hour,
+		FishLLM:            data.LLMTask{Provider: provider},
+	}, db, "")
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	for _, health := range manager.CollectorHealth() {
+		if health.ConsecutiveFailures > 0 {
+			t.Errorf("collector %s failed against the fixtures: %s", health.Name, health.LastError)
+		}
+	}
+
+	for _, region := range data.PredefinedRegions() {
+		weather := manager.RegionWeather(region.ID)
+		if weather == nil || weather.Cities == 0 || weather.Condition == "" {
+			t.Errorf("weather of region %s = %+v, want it aggregated from the replayed cities", region.ID, weather)
+		}
+	}
+	if manager.Astronomy() == nil {
+		t.Error("Astronomy() = nil, want the computed moon and tides")
+	}
+
+	news, err := db.GetRecentNewsData(ctx, 100)
+	if err != nil || len(news) == 0 {
+		t.Fatalf("GetRecentNewsData() = %d items, %v; want the replayed headlines", len(news), err)
+	}
+	for _, asset := range []string{"btc", "gold"} {
+		if prices, err := db.GetRecentPriceData(ctx, asset, 1); err != nil || len(prices) == 0 {
+			t.Errorf("GetRecentPriceData(%s) = %v, %v; want the replayed price", asset, prices, err)
+		}
+	}
+
+	prompts := provider.Prompts()
+	if len(prompts) == 0 {
+		t.Fatal("the model was never asked for a fish")
+	}
+	page, err := db.QueryFish(ctx, storage.FishQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("QueryFish() error = %v", err)
+	}
+	if len(page.Fish) != 1 || page.Fish[0].Name != "Replay Rockling" || page.Fish[0].Status != data.FishStatusPublished {
+		t.Fatalf("stored fish = %+v, want the published Replay Rockling", page.Fish)
+	}
+	if len(page.Fish[0].UsedArticles) == 0 {
+		t.Error("the fish has no used articles, want the replayed news that inspired it")
+	}
+}
@@ -0,0 +1,383 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Health states of a collector
+const (
+	CollectorHealthy  = "healthy"  // The last collection succeeded at the first attempt
+	CollectorDegraded = "degraded" // The last collection needed retries or failed, or the API asked to back off
+	CollectorOpen     = "open"     // The circuit breaker is open and collections fail without calling the API
+)
+
+// ErrCircuitOpen is returned by collections skipped because the collector's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrNoNewData is returned by collectors whose API answered but had nothing new. It is
+// neither retried nor counted against the collector's health.
+var ErrNoNewData = errors.New("no new data")
+
+// secretQueryParam matches credentials in URLs quoted by request errors
+var secretQueryParam = regexp.MustCompile(`(?i)\b(apikey|api_key|appid|key|token)=[^&\s"]*`)
+
+// StatusError is returned by collectors when an API answers with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // Delay requested by the Retry-After header; zero when there was none
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status code %d", e.StatusCode)
+}
+
+// Retryable reports whether the request may succeed when it is sent again
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusRequestTimeout || e.StatusCode >= 500
+}
+
+// checkStatus returns a StatusError for responses other than 200 OK
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// ResilienceSettings configure the retries and circuit breaker of a ResilientCollector.
+// Zero fields take the value of DefaultResilienceSettings.
+type ResilienceSettings struct {
+	MaxAttempts      int           // Attempts per collection, including the first
+	BaseDelay        time.Duration // Delay before the first retry, doubled for every later one
+	MaxDelay         time.Duration // Longest wait between attempts; a longer Retry-After ends the collection instead
+	FailureThreshold int           // Failed collections in a row that open the circuit breaker
+	OpenTimeout      time.Duration // Time the breaker stays open before a trial collection is let through
+}
+
+// DefaultResilienceSettings returns three attempts per collection, starting one second apart,
+// and a breaker that opens after five failed collections in a row for ten minutes
+func DefaultResilienceSettings() ResilienceSettings {
+	return ResilienceSettings{
+		MaxAttempts:      3,
+		BaseDelay:        time.Second,
+		MaxDelay:         30 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      10 * time.Minute,
+	}
+}
+
+// withDefaults fills the zero fields from the default settings
+func (s ResilienceSettings) withDefaults() ResilienceSettings {
+	defaults := DefaultResilienceSettings()
+	if s.MaxAttempts <= 0 {
+		s.MaxAttempts = defaults.MaxAttempts
+	}
+	if s.BaseDelay <= 0 {
+		s.BaseDelay = defaults.BaseDelay
+	}
+	if s.MaxDelay <= 0 {
+		s.MaxDelay = defaults.MaxDelay
+	}
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = defaults.FailureThreshold
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = defaults.OpenTimeout
+	}
+	return s
+}
+
+// CollectorHealth is the health of one collector as reported by the API
+type CollectorHealth struct {
+	Name                string     `json:"name"`
+	Type                DataType   `json:"type"`
+	State               string     `json:"state"`                // One of the Collector* health states
+	ConsecutiveFailures int        `json:"consecutive_failures"` // Failed collections since the last success
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // When the breaker, a Retry-After or the API quota lets collections through again
+}
+
+// ResilientCollector wraps a collector with retries, exponential backoff with jitter and a
+// circuit breaker. Rate limits and server errors are retried, honoring Retry-After; other
+// client errors are not. Once FailureThreshold collections in a row have failed, the breaker
+// opens and collections fail fast until OpenTimeout has passed, after which a single trial
+// collection decides whether it closes again.
+type ResilientCollector struct {
+	name      string
+	collector DataCollector
+	settings  ResilienceSettings
+
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	lastError           string
+	lastErrorAt         time.Time
+	lastSuccessAt       time.Time
+	retryAt             time.Time // Collections before this time fail fast
+	trialRunning        bool      // A trial collection of an open breaker is in progress
+}
+
+// NewResilientCollector wraps a collector under the name its health is reported by
+func NewResilientCollector(name string, collector DataCollector, settings ResilienceSettings) *ResilientCollector {
+	return &ResilientCollector{
+		name:      name,
+		collector: collector,
+		settings:  settings.withDefaults(),
+		state:     CollectorHealthy,
+	}
+}
+
+// Unwrap returns the wrapped collector
+func (c *ResilientCollector) Unwrap() DataCollector {
+	return c.collector
+}
+
+// GetType returns the type of data collected by the wrapped collector
+func (c *ResilientCollector) GetType() DataType {
+	return c.collector.GetType()
+}
+
+// Start begins periodic collection through the retries and breaker
+func (c *ResilientCollector) Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent) {
+	runCollector(ctx, c, interval, eventCh)
+}
+
+// Collect runs the wrapped collector, retrying failures that may be temporary
+func (c *ResilientCollector) Collect(ctx context.Context) (*DataEvent, error) {
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	var retryAfter time.Duration
+	for attempt := 1; ; attempt++ {
+		var event *DataEvent
+		event, err = c.collector.Collect(ctx)
+		if err == nil {
+			c.recordSuccess(attempt)
+			return event, nil
+		}
+		if errors.Is(err, ErrNoNewData) {
+			c.recordSuccess(attempt)
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			c.releaseTrial()
+			return nil, err
+		}
+		var quotaErr *QuotaError
+		if errors.As(err, &quotaErr) {
+			// The API was not called, so this is not a failure of the source
+			c.holdUntil(quotaErr.RetryAt)
+			return nil, err
+		}
+
+		var retryable bool
+		retryable, retryAfter = classifyCollectError(err)
+		if !retryable || attempt >= c.settings.MaxAttempts || c.isTrial() || retryAfter > c.settings.MaxDelay {
+			// A longer Retry-After than a collection may wait holds collections back until then
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		log.Printf("Collector %s failed (attempt %d of %d), retrying in %v: %v", c.name, attempt, c.settings.MaxAttempts, delay.Round(time.Millisecond), err)
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			c.releaseTrial()
+			return nil, err
+		}
+	}
+
+	c.recordFailure(err, retryAfter)
+	return nil, err
+}
+
+// Health returns the current health of the collector
+func (c *ResilientCollector) Health() CollectorHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	health := CollectorHealth{
+		Name:                c.name,
+		Type:                c.collector.GetType(),
+		State:               c.state,
+		ConsecutiveFailures: c.consecutiveFailures,
+		LastError:           c.lastError,
+	}
+	if !c.lastErrorAt.IsZero() {
+		at := c.lastErrorAt
+		health.LastErrorAt = &at
+	}
+	if !c.lastSuccessAt.IsZero() {
+		at := c.lastSuccessAt
+		health.LastSuccessAt = &at
+	}
+	if c.retryAt.After(time.Now()) {
+		at := c.retryAt
+		health.RetryAt = &at
+	}
+	return health
+}
+
+// admit fails fast while the breaker is open or a Retry-After or quota holds the collector
+// back, and lets a single trial collection through once the breaker's timeout has passed
+func (c *ResilientCollector) admit() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := time.Now(); now.Before(c.retryAt) {
+		if c.state == CollectorOpen {
+			return fmt.Errorf("collector %s: %w until %s", c.name, ErrCircuitOpen, c.retryAt.Format(time.RFC3339))
+		}
+		return fmt.Errorf("collector %s is held back until %s by a Retry-After or its API quota", c.name, c.retryAt.Format(time.RFC3339))
+	}
+	if c.state == CollectorOpen {
+		if c.trialRunning {
+			return fmt.Errorf("collector %s: %w, trial collection in progress", c.name, ErrCircuitOpen)
+		}
+		c.trialRunning = true
+		log.Printf("Circuit breaker of collector %s is half-open, trying one collection", c.name)
+	}
+	return nil
+}
+
+// isTrial reports whether the running collection is the trial of an open breaker, which is not retried
+func (c *ResilientCollector) isTrial() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.trialRunning
+}
+
+// releaseTrial lets another trial through after a collection that was cancelled
+func (c *ResilientCollector) releaseTrial() {
+	c.mu.Lock()
+	c.trialRunning = false
+	c.mu.Unlock()
+}
+
+// holdUntil holds collections back without counting a failure, e.g. while an API quota defers calls
+func (c *ResilientCollector) holdUntil(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if at.After(c.retryAt) {
+		c.retryAt = at
+	}
+	c.trialRunning = false
+}
+
+// recordSuccess closes the breaker and marks the collector healthy, or degraded when it took retries
+func (c *ResilientCollector) recordSuccess(attempts int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == CollectorOpen {
+		log.Printf("Circuit breaker of collector %s closed", c.name)
+	}
+	c.state = CollectorHealthy
+	if attempts > 1 {
+		c.state = CollectorDegraded
+	}
+	c.consecutiveFailures = 0
+	c.lastSuccessAt = time.Now()
+	c.retryAt = time.Time{}
+	c.trialRunning = false
+}
+
+// recordFailure counts a failed collection and opens the breaker once the threshold is reached.
+// A positive wait holds further collections back for as long as the API asked.
+func (c *ResilientCollector) recordFailure(err error, wait time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.consecutiveFailures++
+	c.lastError = secretQueryParam.ReplaceAllString(err.Error(), "$1=REDACTED")
+	c.lastErrorAt = now
+
+	if c.trialRunning || c.consecutiveFailures >= c.settings.FailureThreshold {
+		if wait < c.settings.OpenTimeout {
+			wait = c.settings.OpenTimeout
+		}
+		if c.state != CollectorOpen {
+			log.Printf("Circuit breaker of collector %s opened after %d failed collections: %v", c.name, c.consecutiveFailures, err)
+		}
+		c.state = CollectorOpen
+	} else {
+		c.state = CollectorDegraded
+	}
+	c.trialRunning = false
+	if wait > 0 {
+		c.retryAt = now.Add(wait)
+	}
+}
+
+// backoff returns the delay before the retry following an attempt: the base delay doubled
+// for every earlier retry, capped at the maximum, and randomized down to half of that so
+// collectors that failed together do not retry together
+func (c *ResilientCollector) backoff(attempt int) time.Duration {
+	delay := c.settings.BaseDelay
+	for i := 1; i < attempt && delay < c.settings.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > c.settings.MaxDelay {
+		delay = c.settings.MaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// classifyCollectError reports whether a collection error may go away on retry, and the
+// delay the API asked for. Rate limits, timeouts and server errors are retried, other
+// status codes are not, and errors without a status code are assumed to be transient.
+func classifyCollectError(err error) (bool, time.Duration) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Retryable(), statusErr.RetryAfter
+	}
+	return true, 0
+}
+
+// sleepContext waits for the duration or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,174 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeCollector returns the scripted errors in order, then succeeds
+type fakeCollector struct {
+	errs  []error
+	calls int
+}
+
+func (c *fakeCollector) Collect(ctx context.Context) (*DataEvent, error) {
+	c.calls++
+	if c.calls <= len(c.errs) && c.errs[c.calls-1] != nil {
+		return nil, c.errs[c.calls-1]
+	}
+	return &DataEvent{Type: NewsData, Timestamp: time.Now()}, nil
+}
+
+func (c *fakeCollector) GetType() DataType {
+	return NewsData
+}
+
+func (c *fakeCollector) Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent) {
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"0", 0},
+		{"-5", 0},
+		{"soon", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyCollectError(t *testing.T) {
+	tests := []struct {
+		err        error
+		retryable  bool
+		retryAfter time.Duration
+	}{
+		{&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}, true, time.Minute},
+		{&StatusError{StatusCode: http.StatusServiceUnavailable}, true, 0},
+		{&StatusError{StatusCode: http.StatusRequestTimeout}, true, 0},
+		{&StatusError{StatusCode: http.StatusUnauthorized}, false, 0},
+		{&StatusError{StatusCode: http.StatusNotFound}, false, 0},
+		{errors.New("connection reset"), true, 0},
+	}
+
+	for _, tt := range tests {
+		retryable, retryAfter := classifyCollectError(tt.err)
+		if retryable != tt.retryable || retryAfter != tt.retryAfter {
+			t.Errorf("classifyCollectError(%v) = %v, %v; want %v, %v", tt.err, retryable, retryAfter, tt.retryable, tt.retryAfter)
+		}
+	}
+}
+
+func TestResilientCollectorBackoff(t *testing.T) {
+	c := NewResilientCollector("test", &fakeCollector{}, ResilienceSettings{BaseDelay: time.Second, MaxDelay: 5 * time.Second})
+
+	// The delay doubles from the base delay up to the maximum, jittered down to half of that
+	for attempt, ceiling := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		for i := 0; i < 50; i++ {
+			if got := c.backoff(attempt + 1); got < ceiling/2 || got > ceiling {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", attempt+1, got, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestResilientCollectorBreaker(t *testing.T) {
+	ctx := context.Background()
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	badRequest := &StatusError{StatusCode: http.StatusBadRequest}
+	fake := &fakeCollector{}
+	c := NewResilientCollector("test", fake, ResilienceSettings{
+		MaxAttempts:      2,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         2 * time.Millisecond,
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	})
+
+	// expire stands in for a clock passing the breaker timeout or a Retry-After
+	expire := func() {
+		c.mu.Lock()
+		c.retryAt = time.Now().Add(-time.Second)
+		c.mu.Unlock()
+	}
+	collect := func(step string, errs []error, wantErr error, wantCalls int, wantState string) {
+		t.Helper()
+		fake.errs, fake.calls = errs, 0
+		_, err := c.Collect(ctx)
+		if !errors.Is(err, wantErr) || (wantErr == nil && err != nil) {
+			t.Errorf("%s: Collect() error = %v, want %v", step, err, wantErr)
+		}
+		if fake.calls != wantCalls {
+			t.Errorf("%s: the API was called %d times, want %d", step, fake.calls, wantCalls)
+		}
+		if health := c.Health(); health.State != wantState {
+			t.Errorf("%s: state = %s, want %s", step, health.State, wantState)
+		}
+	}
+
+	collect("first attempt succeeds", nil, nil, 1, CollectorHealthy)
+	collect("retry succeeds", []error{unavailable}, nil, 2, CollectorDegraded)
+	collect("nothing new", []error{ErrNoNewData}, ErrNoNewData, 1, CollectorHealthy)
+	collect("client error is not retried", []error{badRequest}, badRequest, 1, CollectorDegraded)
+	collect("threshold opens the breaker", []error{unavailable, unavailable}, unavailable, 2, CollectorOpen)
+	if health := c.Health(); health.RetryAt == nil || time.Until(*health.RetryAt) < 59*time.Minute || health.ConsecutiveFailures != 2 {
+		t.Errorf("open breaker health = %+v, want 2 failures and a retry in an hour", health)
+	}
+	collect("open breaker fails fast", nil, ErrCircuitOpen, 0, CollectorOpen)
+
+	expire()
+	collect("failed trial is not retried and reopens", []error{unavailable}, unavailable, 1, CollectorOpen)
+	collect("reopened breaker fails fast", nil, ErrCircuitOpen, 0, CollectorOpen)
+
+	expire()
+	collect("trial success closes the breaker", nil, nil, 1, CollectorHealthy)
+	if health := c.Health(); health.ConsecutiveFailures != 0 || health.RetryAt != nil || health.LastSuccessAt == nil {
+		t.Errorf("closed breaker health = %+v, want no failures or retry time", health)
+	}
+}
+
+func TestResilientCollectorHoldsBack(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeCollector{}
+	c := NewResilientCollector("test", fake, ResilienceSettings{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+	// A Retry-After longer than a collection may wait ends it and holds the next ones back
+	fake.errs = []error{&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}}
+	if _, err := c.Collect(ctx); err == nil || fake.calls != 1 {
+		t.Fatalf("Collect() = %v after %d calls, want the rate limit error after one call", err, fake.calls)
+	}
+	if health := c.Health(); health.State != CollectorDegraded || health.RetryAt == nil || time.Until(*health.RetryAt) < 59*time.Second {
+		t.Errorf("health = %+v, want degraded and held back for a minute", health)
+	}
+	if _, err := c.Collect(ctx); err == nil || errors.Is(err, ErrCircuitOpen) || fake.calls != 1 {
+		t.Errorf("Collect() while held back = %v after %d calls, want a hold without calling the API", err, fake.calls)
+	}
+
+	// A deferred quota holds collections back without counting as a failure
+	c.mu.Lock()
+	c.retryAt = time.Time{}
+	c.mu.Unlock()
+	fake.errs, fake.calls = []error{&QuotaError{Source: "newsapi", RetryAt: time.Now().Add(time.Hour)}}, 0
+	var quotaErr *QuotaError
+	if _, err := c.Collect(ctx); !errors.As(err, &quotaErr) || fake.calls != 1 {
+		t.Fatalf("Collect() = %v after %d calls, want the QuotaError after one call", err, fake.calls)
+	}
+	if health := c.Health(); health.ConsecutiveFailures != 1 || health.RetryAt == nil || time.Until(*health.RetryAt) < 59*time.Minute {
+		t.Errorf("health = %+v, want the failure count unchanged and held back for an hour", health)
+	}
+}
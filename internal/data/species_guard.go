@@ -0,0 +1,254 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Reasons a new fish collides with an existing species
+const (
+	SpeciesMatchExactName   = "exact_name"   // Names are equal after normalization
+	SpeciesMatchSimilarName = "similar_name" // Normalized edit distance is small
+	SpeciesMatchNameTokens  = "name_tokens"  // Names share most of their words
+	SpeciesMatchDescription = "description"  // Descriptions share most of their words
+)
+
+// SpeciesSummary is the part of a stored fish the uniqueness guard compares against
+type SpeciesSummary struct {
+	ID          string `bson:"id" json:"id"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+	VariantOf   string `bson:"variant_of,omitempty" json:"variant_of,omitempty"`
+}
+
+// SpeciesCatalog lists the species a new fish must not duplicate
+type SpeciesCatalog interface {
+	GetSpeciesSummaries(ctx context.Context) ([]SpeciesSummary, error)
+}
+
+// SpeciesRenamer asks for a new name for a fish whose name is already taken
+type SpeciesRenamer interface {
+	RenameFish(ctx context.Context, fish *FishRecord, takenNames []string) (string, error)
+}
+
+// SpeciesGuardSettings configures when two fish count as the same species.
+// Similarities range from 0 (nothing in common) to 1 (identical).
+type SpeciesGuardSettings struct {
+	NameSimilarity        float64 // Minimum normalized edit-distance similarity of two names
+	NameTokenOverlap      float64 // Minimum share of name words in common
+	DescriptionSimilarity float64 // Minimum share of description words in common
+	RenameAttempts        int     // New names requested before a colliding fish becomes a variant
+}
+
+// DefaultSpeciesGuardSettings returns the thresholds used when none are configured
+func DefaultSpeciesGuardSettings() SpeciesGuardSettings {
+	return SpeciesGuardSettings{
+		NameSimilarity:        0.85,
+		NameTokenOverlap:      0.75,
+		DescriptionSimilarity: 0.6,
+		RenameAttempts:        2,
+	}
+}
+
+// SpeciesMatch is an existing species a new fish collides with
+type SpeciesMatch struct {
+	Species SpeciesSummary
+	Reason  string  // One of the SpeciesMatch* reasons
+	Score   float64 // Similarity that triggered the match
+}
+
+// SpeciesGuard keeps generated fish from duplicating species already in the catalog.
+// A nil SpeciesGuard accepts every fish.
+type SpeciesGuard struct {
+	catalog  SpeciesCatalog
+	settings SpeciesGuardSettings
+}
+
+// NewSpeciesGuard creates a guard that compares new fish against the catalog
+func NewSpeciesGuard(catalog SpeciesCatalog, settings SpeciesGuardSettings) *SpeciesGuard {
+	return &SpeciesGuard{
+		catalog:  catalog,
+		settings: settings,
+	}
+}
+
+// Check returns the first existing species the fish collides with, or nil when it is unique
+func (g *SpeciesGuard) Check(ctx context.Context, fish *FishRecord) (*SpeciesMatch, error) {
+	if g == nil || g.catalog == nil {
+		return nil, nil
+	}
+
+	species, err := g.catalog.GetSpeciesSummaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load species for uniqueness check: %v", err)
+	}
+	return g.match(fish, species), nil
+}
+
+// match compares the fish with every species, preferring name matches over description matches
+func (g *SpeciesGuard) match(fish *FishRecord, species []SpeciesSummary) *SpeciesMatch {
+	name := normalizeSpeciesName(fish.Name)
+	nameTokens := wordSet(name, 1)
+	descriptionTokens := wordSet(normalizeSpeciesName(fish.Description), 3)
+
+	var descriptionMatch *SpeciesMatch
+	for _, existing := range species {
+		if existing.ID != "" && existing.ID == fish.ID {
+			continue
+		}
+
+		existingName := normalizeSpeciesName(existing.Name)
+		if name != "" && name == existingName {
+			return &SpeciesMatch{Species: existing, Reason: SpeciesMatchExactName, Score: 1}
+		}
+		if score := nameSimilarity(name, existingName); score >= g.settings.NameSimilarity {
+			return &SpeciesMatch{Species: existing, Reason: SpeciesMatchSimilarName, Score: score}
+		}
+		if score := jaccard(nameTokens, wordSet(existingName, 1)); score >= g.settings.NameTokenOverlap {
+			return &SpeciesMatch{Species: existing, Reason: SpeciesMatchNameTokens, Score: score}
+		}
+
+		if g.settings.DescriptionSimilarity > 0 {
+			score := jaccard(descriptionTokens, wordSet(normalizeSpeciesName(existing.Description), 3))
+			if score >= g.settings.DescriptionSimilarity && (descriptionMatch == nil || score > descriptionMatch.Score) {
+				descriptionMatch = &SpeciesMatch{Species: existing, Reason: SpeciesMatchDescription, Score: score}
+			}
+		}
+	}
+	return descriptionMatch
+}
+
+// Resolve makes sure the fish does not duplicate an existing species before it is saved.
+// A fish whose name is taken gets a new name from renamer, which may be nil; a fish that
+// still collides, or whose description matches an existing species, is marked as a
+// variant of that species. Lookup failures are logged and the fish is accepted as is.
+func (g *SpeciesGuard) Resolve(ctx context.Context, fish *FishRecord, renamer SpeciesRenamer) {
+	if g == nil {
+		return
+	}
+
+	match, err := g.Check(ctx, fish)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+
+	var taken []string
+	for attempt := 1; match != nil && match.Reason != SpeciesMatchDescription && renamer != nil && attempt <= g.settings.RenameAttempts; attempt++ {
+		log.Printf("Fish name %q collides with %q (%s, %.2f); requesting a new name (attempt %d)",
+			fish.Name, match.Species.Name, match.Reason, match.Score, attempt)
+		taken = append(taken, fish.Name, match.Species.Name)
+
+		name, err := renamer.RenameFish(ctx, fish, taken)
+		if err != nil {
+			log.Printf("Warning: failed to rename fish %q: %v", fish.Name, err)
+			break
+		}
+		fish.Name = name
+
+		if match, err = g.Check(ctx, fish); err != nil {
+			log.Printf("Warning: %v", err)
+			return
+		}
+	}
+
+	if match == nil {
+		return
+	}
+
+	// Variants always point at the original species, never at another variant
+	fish.VariantOf = match.Species.ID
+	if match.Species.VariantOf != "" {
+		fish.VariantOf = match.Species.VariantOf
+	}
+	log.Printf("Fish %q is a variant of %q (%s, %.2f)", fish.Name, match.Species.Name, match.Reason, match.Score)
+}
+
+// normalizeSpeciesName lowercases text and reduces it to letters and digits separated by single spaces
+func normalizeSpeciesName(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// nameSimilarity is one minus the edit distance of two names divided by the longer length
+func nameSimilarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := len(ar)
+	if len(br) > longest {
+		longest = len(br)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ar, br))/float64(longest)
+}
+
+// levenshtein counts the insertions, deletions and substitutions that turn a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// wordSet returns the distinct words of normalized text that have at least minLength letters
+func wordSet(text string, minLength int) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		if len([]rune(word)) >= minLength {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// jaccard is the number of words two sets share divided by the number of distinct words in both
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// minInt returns the smaller of two ints
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,194 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// fakeSpeciesCatalog returns the same species, or error, on every lookup
+type fakeSpeciesCatalog struct {
+	species []SpeciesSummary
+	err     error
+}
+
+func (c *fakeSpeciesCatalog) GetSpeciesSummaries(ctx context.Context) ([]SpeciesSummary, error) {
+	return c.species, c.err
+}
+
+// fakeSpeciesRenamer hands out the given names in order and remembers the names to avoid
+type fakeSpeciesRenamer struct {
+	names []string
+	taken [][]string
+}
+
+func (r *fakeSpeciesRenamer) RenameFish(ctx context.Context, fish *FishRecord, takenNames []string) (string, error) {
+	r.taken = append(r.taken, append([]string(nil), takenNames...))
+	if len(r.taken) > len(r.names) {
+		return "", errors.New("out of names")
+	}
+	return r.names[len(r.taken)-1], nil
+}
+
+// testSpecies is a catalog with one original species and one variant of it
+func testSpecies() []SpeciesSummary {
+	return []SpeciesSummary{
+		{ID: "root", Name: "Glimmer Trout", Description: "A silver fish that glows under the winter moon"},
+		{ID: "variant", Name: "Ember Carp", Description: "A red carp that warms the shallows", VariantOf: "root"},
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"forelle", "förelle", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 0},
+		{"glimmer trout", "glimmer trout", 1},
+		{"abcd", "abce", 0.75},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		if got := nameSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("nameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "glimmer trout", 0},
+		{"glimmer trout", "trout glimmer", 1},
+		{"glimmer trout", "trout ember", 1.0 / 3},
+		{"glimmer trout", "ember carp", 0},
+	}
+
+	for _, tt := range tests {
+		if got := jaccard(wordSet(tt.a, 1), wordSet(tt.b, 1)); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("jaccard(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if got := wordSet("a fish of the sea", 3); !reflect.DeepEqual(got, map[string]bool{"fish": true, "the": true, "sea": true}) {
+		t.Errorf("wordSet() = %v, want the words of at least 3 letters", got)
+	}
+}
+
+func TestSpeciesGuardMatch(t *testing.T) {
+	guard := NewSpeciesGuard(nil, DefaultSpeciesGuardSettings())
+	unrelated := SpeciesSummary{ID: "other", Name: "Bright Perch", Description: "A silver fish that glows under the summer moon"}
+
+	tests := []struct {
+		name        string
+		fish        FishRecord
+		species     []SpeciesSummary
+		wantID      string // Empty when the fish is unique
+		wantReason  string
+		wantScoreAt float64 // Minimum score of the match
+	}{
+		{"exact name after normalization", FishRecord{Name: "  glimmer-TROUT!"}, testSpecies(), "root", SpeciesMatchExactName, 1},
+		{"similar name", FishRecord{Name: "Glimmer Trouts"}, testSpecies(), "root", SpeciesMatchSimilarName, 0.9},
+		{"same words in another order", FishRecord{Name: "Trout Glimmer"}, testSpecies(), "root", SpeciesMatchNameTokens, 1},
+		{"similar description", FishRecord{Name: "Dusk Pike", Description: "A silver fish that glows under the autumn moon"},
+			testSpecies(), "root", SpeciesMatchDescription, 0.6},
+		{"closest description wins", FishRecord{Name: "Dusk Pike", Description: "A silver fish that glows under the summer moon"},
+			append(testSpecies(), unrelated), "other", SpeciesMatchDescription, 1},
+		{"name match beats an earlier description match", FishRecord{Name: "Ember Carp", Description: "A silver fish that glows under the summer moon"},
+			append([]SpeciesSummary{unrelated}, testSpecies()...), "variant", SpeciesMatchExactName, 1},
+		{"the fish itself is skipped", FishRecord{ID: "root", Name: "Glimmer Trout"}, testSpecies(), "", "", 0},
+		{"unique fish", FishRecord{Name: "Dusk Pike", Description: "It hums quietly near old piers"}, testSpecies(), "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fish := tt.fish
+			match := guard.match(&fish, tt.species)
+			if tt.wantID == "" {
+				if match != nil {
+					t.Errorf("match() = %+v, want nil", match)
+				}
+				return
+			}
+			if match == nil || match.Species.ID != tt.wantID || match.Reason != tt.wantReason || match.Score < tt.wantScoreAt {
+				t.Errorf("match() = %+v, want %s by %s scoring at least %v", match, tt.wantID, tt.wantReason, tt.wantScoreAt)
+			}
+		})
+	}
+}
+
+func TestSpeciesGuardResolve(t *testing.T) {
+	settings := DefaultSpeciesGuardSettings()
+	settings.NameTokenOverlap = 0.5
+
+	tests := []struct {
+		name          string
+		fish          FishRecord
+		catalog       *fakeSpeciesCatalog
+		renamer       *fakeSpeciesRenamer // Nil to resolve without renaming
+		wantName      string
+		wantVariantOf string
+		wantRenames   int
+	}{
+		{"unique fish", FishRecord{Name: "Dusk Pike"}, &fakeSpeciesCatalog{species: testSpecies()},
+			&fakeSpeciesRenamer{}, "Dusk Pike", "", 0},
+		{"renamed to a free name", FishRecord{Name: "Glimmer Trout"}, &fakeSpeciesCatalog{species: testSpecies()},
+			&fakeSpeciesRenamer{names: []string{"Dusk Pike"}}, "Dusk Pike", "", 1},
+		{"configured token overlap", FishRecord{Name: "Ember Carp Fish"}, &fakeSpeciesCatalog{species: testSpecies()},
+			&fakeSpeciesRenamer{names: []string{"Dusk Pike"}}, "Dusk Pike", "", 1},
+		{"still taken after every rename", FishRecord{Name: "Glimmer Trout"}, &fakeSpeciesCatalog{species: testSpecies()},
+			&fakeSpeciesRenamer{names: []string{"Glimmer Trouts", "Ember Carp"}}, "Ember Carp", "root", 2},
+		{"variant of a variant points at the root", FishRecord{Name: "Ember Carp"}, &fakeSpeciesCatalog{species: testSpecies()},
+			nil, "Ember Carp", "root", 0},
+		{"description match is not renamed", FishRecord{Name: "Dusk Pike", Description: "A silver fish that glows under the autumn moon"},
+			&fakeSpeciesCatalog{species: testSpecies()}, &fakeSpeciesRenamer{names: []string{"Bright Perch"}}, "Dusk Pike", "root", 0},
+		{"rename failure keeps the name", FishRecord{Name: "Glimmer Trout"}, &fakeSpeciesCatalog{species: testSpecies()},
+			&fakeSpeciesRenamer{}, "Glimmer Trout", "root", 1},
+		{"catalog failure accepts the fish", FishRecord{Name: "Glimmer Trout"}, &fakeSpeciesCatalog{err: errors.New("storage is down")},
+			&fakeSpeciesRenamer{}, "Glimmer Trout", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fish := tt.fish
+			var renamer SpeciesRenamer
+			if tt.renamer != nil {
+				renamer = tt.renamer
+			}
+			NewSpeciesGuard(tt.catalog, settings).Resolve(context.Background(), &fish, renamer)
+
+			if fish.Name != tt.wantName || fish.VariantOf != tt.wantVariantOf {
+				t.Errorf("Resolve() left %q variant of %q, want %q variant of %q", fish.Name, fish.VariantOf, tt.wantName, tt.wantVariantOf)
+			}
+			if tt.renamer != nil && len(tt.renamer.taken) != tt.wantRenames {
+				t.Errorf("Resolve() requested %d new names, want %d", len(tt.renamer.taken), tt.wantRenames)
+			}
+		})
+	}
+}
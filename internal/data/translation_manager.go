@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,16 +21,27 @@ type TranslationSettings struct {
 	Enabled  bool          // Whether translation is enabled
 	Interval time.Duration // How often to check for untranslated fish
 	ApiKey   string        // API key for Gemini
+	Locales  []Locale      // Languages every fish is translated into
+	LLM      LLMTask       // Provider used for translation; Gemini with ApiKey when unset
 }
 
 // DatabaseTranslationClient is an interface for database operations related to translation
 type DatabaseTranslationClient interface {
-	GetFishByID(ctx context.Context, id string) (map[string]interface{}, error)
+	GetFishRecord(ctx context.Context, id string) (*FishRecord, error)
 	SaveTranslatedFish(ctx context.Context, translatedFish *TranslatedFish) error
-	GetUntranslatedFishIDs(ctx context.Context, limit int) ([]string, error)
+	GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error)
 }
 
-// TranslationManager handles the translation of fish content to Vietnamese
+// maxTranslationBackoff caps the wait before a fish whose translation failed is tried again
+const maxTranslationBackoff = 24 * time.Hour
+
+// translationFailure records the failed translations of one fish into one locale
+type translationFailure struct {
+	attempts int
+	retryAt  time.Time
+}
+
+// TranslationManager handles the translation of fish content into every configured locale
 type TranslationManager struct {
 	settings         TranslationSettings
 	db               DatabaseTranslationClient
@@ -38,16 +50,22 @@ type TranslationManager struct {
 	mu               sync.Mutex
 	wg               sync.WaitGroup
 	isRunning        bool
+	failures         map[string]*translationFailure // By locale and fish ID; only used by the translation goroutine
 }
 
 // NewTranslationManager creates a new translation manager
 func NewTranslationManager(settings TranslationSettings, db DatabaseTranslationClient) *TranslationManager {
+	if len(settings.Locales) == 0 {
+		settings.Locales, _ = ParseLocales(DefaultTranslationLocales)
+	}
+
 	return &TranslationManager{
 		settings:         settings,
 		db:               db,
-		translatorClient: NewTranslatorClient(settings.ApiKey),
+		translatorClient: NewTranslatorClientWithLLM(settings.LLM.WithDefaults(LLMTaskTranslation, settings.ApiKey)),
 		cancelFuncs:      make([]context.CancelFunc, 0),
 		isRunning:        false,
+		failures:         make(map[string]*translationFailure),
 	}
 }
 
@@ -79,7 +97,8 @@ func (t *TranslationManager) Start(ctx context.Context) error {
 		ticker := time.NewTicker(t.settings.Interval)
 		defer ticker.Stop()
 
-		logTranslate("Translation service started with interval: %v", t.settings.Interval)
+		logTranslate("Translation service started with interval: %v, locales: %s",
+			t.settings.Interval, strings.Join(LocaleCodes(t.settings.Locales), ", "))
 
 		// Run translation immediately on startup
 		t.translateNextFish(baseCtx)
@@ -99,52 +118,105 @@ func (t *TranslationManager) Start(ctx context.Context) error {
 	return nil
 }
 
-// translateNextFish finds and translates the next untranslated fish
+// translateNextFish translates the next untranslated fish into each configured locale
 func (t *TranslationManager) translateNextFish(ctx context.Context) {
-	// Get IDs of untranslated fish (limit to 1)
-	untranslatedIDs, err := t.db.GetUntranslatedFishIDs(ctx, 1)
+	for _, locale := range t.settings.Locales {
+		if ctx.Err() != nil {
+			return
+		}
+		t.translateNextFishForLocale(ctx, locale)
+	}
+}
+
+// translateNextFishForLocale finds and translates the next published fish that has no
+// translation in the locale. Fish whose translation failed are skipped until their backoff
+// has passed, so one fish that keeps failing does not hold up the rest of the locale.
+func (t *TranslationManager) translateNextFishForLocale(ctx context.Context, locale Locale) {
+	// Fetch one fish more than are backing off, so at least one can be tried if any is left
+	now := time.Now()
+	backingOff := 0
+	for key, failure := range t.failures {
+		if strings.HasPrefix(key, locale.Code+"/") && now.Before(failure.retryAt) {
+			backingOff++
+		}
+	}
+	untranslatedIDs, err := t.db.GetUntranslatedFishIDs(ctx, locale.Code, backingOff+1)
 	if err != nil {
-		logError("Failed to get untranslated fish IDs: %v", err)
+		logError("Failed to get fish IDs without %s translation: %v", locale.Code, err)
 		return
 	}
 
-	if len(untranslatedIDs) == 0 {
-		logTranslate("No untranslated fish found")
+	fishID := ""
+	for _, id := range untranslatedIDs {
+		if failure := t.failures[locale.Code+"/"+id]; failure == nil || !now.Before(failure.retryAt) {
+			fishID = id
+			break
+		}
+	}
+	if fishID == "" {
+		if len(untranslatedIDs) == 0 {
+			logTranslate("No fish left to translate into %s", locale.Name)
+		}
 		return
 	}
+	logTranslate("Translating fish with ID %s into %s", fishID, locale.Name)
 
-	// Get the first untranslated fish
-	fishID := untranslatedIDs[0]
-	logTranslate("Translating fish with ID: %s", fishID)
+	if err := t.translateFish(ctx, fishID, locale); err != nil {
+		t.recordFailure(fishID, locale, err)
+		return
+	}
+	delete(t.failures, locale.Code+"/"+fishID)
+}
+
+// recordFailure backs off a fish whose translation into a locale failed, doubling the wait
+// after every failure from one translation interval up to maxTranslationBackoff
+func (t *TranslationManager) recordFailure(fishID string, locale Locale, err error) {
+	key := locale.Code + "/" + fishID
+	failure := t.failures[key]
+	if failure == nil {
+		failure = &translationFailure{}
+		t.failures[key] = failure
+	}
+	failure.attempts++
 
+	backoff := t.settings.Interval
+	for i := 1; i < failure.attempts && backoff < maxTranslationBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff <= 0 || backoff > maxTranslationBackoff {
+		backoff = maxTranslationBackoff
+	}
+	failure.retryAt = time.Now().Add(backoff)
+
+	logError("Translation of fish %s into %s failed (attempt %d), retrying in %v: %v",
+		fishID, locale.Name, failure.attempts, backoff, err)
+}
+
+// translateFish translates one fish into a locale and saves the translation
+func (t *TranslationManager) translateFish(ctx context.Context, fishID string, locale Locale) error {
 	// Get fish data from database
-	fishData, err := t.db.GetFishByID(ctx, fishID)
+	fish, err := t.db.GetFishRecord(ctx, fishID)
 	if err != nil {
-		logError("Failed to get fish data: %v", err)
-		return
+		return fmt.Errorf("failed to get fish data: %v", err)
 	}
 
 	// Extract fields to translate
 	fields := TranslationFields{
-		Name:            extractStringField(fishData, "name"),
-		Description:     extractStringField(fishData, "description"),
-		Color:           extractStringField(fishData, "color"),
-		Diet:            extractStringField(fishData, "diet"),
-		Habitat:         extractStringField(fishData, "habitat"),
-		Effect:          extractStringField(fishData, "effect"),
-		FavoriteWeather: extractStringField(fishData, "favorite_weather"),
-		ExistenceReason: extractStringField(fishData, "existence_reason"),
+		Name:            fish.Name,
+		Description:     fish.Description,
+		Color:           fish.Color,
+		Diet:            fish.Diet,
+		Habitat:         fish.Habitat,
+		Effect:          fish.Effect,
+		FavoriteWeather: fish.FavoriteWeather,
+		ExistenceReason: fish.ExistenceReason,
 		PlayerEffect:    "Affects player abilities based on fish value", // Default player effect
 	}
 
-	// Create context with API key
-	translationCtx := context.WithValue(ctx, "gemini_api_key", t.settings.ApiKey)
-
 	// Translate fish content
-	translatedFields, err := t.translatorClient.TranslateFish(translationCtx, fields)
+	translatedFields, err := t.translatorClient.TranslateFish(ctx, fields, locale)
 	if err != nil {
-		logError("Translation failed: %v", err)
-		return
+		return err
 	}
 
 	// Convert TranslationFields to TranslatedFish
@@ -158,17 +230,18 @@ func (t *TranslationManager) translateNextFish(ctx context.Context) {
 		Effect:          translatedFields.Effect,
 		FavoriteWeather: translatedFields.FavoriteWeather,
 		ExistenceReason: translatedFields.ExistenceReason,
+		Language:        locale.Code,
 		TranslatedAt:    time.Now(),
 	}
 
 	// Save translated fish to database
 	err = t.db.SaveTranslatedFish(ctx, translatedFish)
 	if err != nil {
-		logError("Failed to save translated fish: %v", err)
-		return
+		return fmt.Errorf("failed to save translated fish: %v", err)
 	}
 
-	logTranslate("Successfully translated fish: %s -> %s", fields.Name, translatedFish.Name)
+	logTranslate("Successfully translated fish into %s: %s -> %s", locale.Name, fields.Name, translatedFish.Name)
+	return nil
 }
 
 // Stop stops the translation process
@@ -191,16 +264,6 @@ func (t *TranslationManager) Stop() {
 	logTranslate("Translation Manager stopped")
 }
 
-// Helper function to extract string fields from fish data
-func extractStringField(fishData map[string]interface{}, field string) string {
-	if value, ok := fishData[field]; ok {
-		if strValue, ok := value.(string); ok {
-			return strValue
-		}
-	}
-	return ""
-}
-
 // LoadTranslationSettings loads translation settings from environment variables
 func LoadTranslationSettings() TranslationSettings {
 	enabled := os.Getenv("ENABLE_TRANSLATION") == "1"
@@ -214,9 +277,21 @@ func LoadTranslationSettings() TranslationSettings {
 		}
 	}
 
+	// Get target locales (default: Vietnamese only)
+	localeList := os.Getenv("TRANSLATION_LOCALES")
+	if localeList == "" {
+		localeList = DefaultTranslationLocales
+	}
+	locales, err := ParseLocales(localeList)
+	if err != nil {
+		logError("Invalid TRANSLATION_LOCALES, using %s: %v", DefaultTranslationLocales, err)
+		locales, _ = ParseLocales(DefaultTranslationLocales)
+	}
+
 	return TranslationSettings{
 		Enabled:  enabled,
 		Interval: time.Duration(intervalMin) * time.Minute,
 		ApiKey:   os.Getenv("GEMINI_API_KEY"),
+		Locales:  locales,
 	}
 }
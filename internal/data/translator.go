@@ -9,9 +9,6 @@ import (
 	"sync"
 	"time"
 	"unicode/utf8"
-
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 )
 
 // TranslationFields represents the fields from a fish that need translation
@@ -40,167 +37,86 @@ type TranslatedFish struct {
 	Effect          string    `json:"effect"`
 	FavoriteWeather string    `json:"favorite_weather"`
 	ExistenceReason string    `json:"existence_reason"`
+	Language        string    `json:"language"` // Locale code, e.g. "vi"
 	TranslatedAt    time.Time `json:"translated_at"`
 }
 
-// TranslatorClient handles translation of fish content to Vietnamese
+// TranslatorClient handles translation of fish content into the configured locales
 type TranslatorClient struct {
-	apiKey     string
-	client     *genai.Client
-	model      string
-	clientOnce sync.Once
-	mu         sync.Mutex
+	task     string
+	provider LLMProvider
+	options  LLMOptions
+	usage    *LLMUsageTracker
+	prompts  *PromptLibrary
+	mu       sync.Mutex
 }
 
 // NewTranslatorClient creates a new translator client
 func NewTranslatorClient(apiKey string) *TranslatorClient {
-	return &TranslatorClient{
-		apiKey: apiKey,
-		model:  "gemma-3-27b-it", // Using the Pro model for translation tasks
-	}
+	return NewTranslatorClientWithLLM(LLMTask{}.WithDefaults(LLMTaskTranslation, apiKey))
 }
 
-// initClient initializes the Gemini client (only once)
-func (t *TranslatorClient) initClient(ctx context.Context) error {
-	var initErr error
-
-	t.clientOnce.Do(func() {
-		// Extract API key from context or use the one provided at init
-		apiKey := t.apiKey
-		if ctxKey, ok := ctx.Value("gemini_api_key").(string); ok && ctxKey != "" {
-			apiKey = ctxKey
-		}
-
-		if apiKey == "" {
-			initErr = fmt.Errorf("no API key provided for Gemini client")
-			return
-		}
-
-		client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-		if err != nil {
-			initErr = fmt.Errorf("failed to create Gemini client: %w", err)
-			return
-		}
-
-		t.client = client
-		log.Printf("Gemini client initialized for translation using model: %s", t.model)
-	})
-
-	return initErr
+// NewTranslatorClientWithLLM creates a translator client that uses the given provider and options
+func NewTranslatorClientWithLLM(llm LLMTask) *TranslatorClient {
+	client := &TranslatorClient{
+		task:     llm.Name,
+		provider: llm.Provider,
+		options:  llm.Options,
+		usage:    llm.Usage,
+		prompts:  llm.Prompts,
+	}
+	if client.prompts == nil {
+		client.prompts = DefaultPromptLibrary()
+	}
+	return client
 }
 
-// TranslateFish translates the provided fish fields to Vietnamese
-func (t *TranslatorClient) TranslateFish(ctx context.Context, fields TranslationFields) (*TranslationFields, error) {
+// TranslateFish translates the provided fish fields into the given locale
+func (t *TranslatorClient) TranslateFish(ctx context.Context, fields TranslationFields, locale Locale) (*TranslationFields, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	// Initialize the client if it's not initialized yet
-	if err := t.initClient(ctx); err != nil {
+	// Build the translation prompt
+	prompt, _, err := t.buildTranslationPrompt(fields, locale)
+	if err != nil {
 		return nil, err
 	}
 
-	// Build the translation prompt
-	prompt := t.buildTranslationPrompt(fields)
-
-	// Create a model instance
-	model := t.client.GenerativeModel(t.model)
-
-	// Configure the model
-	model.SetTemperature(0.2) // Low temperature for more consistent translations
-	model.SetTopP(0.95)       // Balanced top-p for translation quality
-	model.SetTopK(40)         // Standard top-k
+	if err := t.usage.CheckBudget(ctx); err != nil {
+		return nil, err
+	}
 
 	// Send the translation request
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	start := time.Now()
+	resp, err := t.provider.Generate(ctx, prompt, t.options)
+	latency := time.Since(start)
 	if err != nil {
+		t.usage.RecordCall(ctx, t.task, t.provider.Name(), t.options, nil, latency, LLMOutcomeError, err)
 		return nil, fmt.Errorf("translation request failed: %w", err)
 	}
 
-	// Check if we got a valid response
-	if resp == nil || len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from translation service")
-	}
-
-	// Extract the response text
-	responsePart := resp.Candidates[0].Content.Parts[0]
-	responseText, ok := responsePart.(genai.Text)
-	if !ok || string(responseText) == "" {
-		return nil, fmt.Errorf("empty or invalid text in translation response")
-	}
-
 	// Parse the response to extract the translated fields
-	translatedFields, err := t.parseTranslationResponse(string(responseText))
+	translatedFields, err := t.parseTranslationResponse(resp.Text)
 	if err != nil {
+		t.usage.RecordCall(ctx, t.task, t.provider.Name(), t.options, resp, latency, LLMOutcomeInvalid, err)
+		invalidateLLMResponse(ctx, t.provider, prompt, t.options)
 		return nil, fmt.Errorf("failed to parse translation response: %w", err)
 	}
 
-	return translatedFields, nil
-}
-
-// buildTranslationPrompt creates a prompt for the Gemini API to translate fish content
-func (t *TranslatorClient) buildTranslationPrompt(fields TranslationFields) string {
-	prompt := fmt.Sprintf(`
-You are a professional translator specializing in Vietnamese translations for a fish-themed game.
-Please translate the following fish description fields from English to Vietnamese.
-Maintain the tone and style, but adapt cultural references as needed for Vietnamese speakers.
-Format your response as a valid JSON object containing only the translated fields.
-
-Original fields:
-- Name: %s
-- Description: %s
-- Color: %s
-- Diet: %s
-- Habitat: %s
-- Favorite Weather: %s
-- Existence Reason: %s
-- Effect: %s
-- Player Effect: %s
-`, fields.Name, fields.Description, fields.Color, fields.Diet,
-		fields.Habitat, fields.FavoriteWeather, fields.ExistenceReason,
-		fields.Effect, fields.PlayerEffect)
-
-	// Add each stat effect for translation if available
-	if len(fields.StatEffectTexts) > 0 {
-		prompt += "\nIMPORTANT - Stat Effects (each one MUST be translated individually):\n"
-		for i, effectText := range fields.StatEffectTexts {
-			if effectText != "" {
-				prompt += fmt.Sprintf("- Stat_Effect_%d: %s\n", i+1, effectText)
-			}
-		}
-		prompt += "\nAll stat effects must be translated individually with their own separate translation in the response JSON.\n"
-	}
-
-	prompt += `
-Return only a JSON object with the translated fields in this exact format:
-{
-  "name": "[Vietnamese translation]",
-  "description": "[Vietnamese translation]",
-  "color": "[Vietnamese translation]",
-  "diet": "[Vietnamese translation]",
-  "habitat": "[Vietnamese translation]",
-  "favorite_weather": "[Vietnamese translation]",
-  "existence_reason": "[Vietnamese translation]",
-  "effect": "[Vietnamese translation]",
-  "player_effect": "[Vietnamese translation]"
-`
-
-	// Add stat effect fields to the expected JSON response
-	if len(fields.StatEffectTexts) > 0 {
-		for i := range fields.StatEffectTexts {
-			if fields.StatEffectTexts[i] != "" {
-				prompt += fmt.Sprintf(",\n  \"stat_effect_%d\": \"[Vietnamese translation of Stat_Effect_%d]\"", i+1, i+1)
-			}
-		}
+	// Apply the locale's own validation rules
+	if err := locale.ValidateTranslation(translatedFields); err != nil {
+		t.usage.RecordCall(ctx, t.task, t.provider.Name(), t.options, resp, latency, LLMOutcomeInvalid, err)
+		invalidateLLMResponse(ctx, t.provider, prompt, t.options)
+		return nil, err
 	}
 
-	prompt += "\n}"
-
-	// Add final instruction to emphasize importance of translating all stat effects
-	if len(fields.StatEffectTexts) > 0 {
-		prompt += "\n\nCritical: Ensure EACH stat effect gets its own translation. Do not merge stat effects."
-	}
+	t.usage.RecordCall(ctx, t.task, t.provider.Name(), t.options, resp, latency, "", nil)
+	return translatedFields, nil
+}
 
-	return prompt
+// buildTranslationPrompt renders the translation template and returns the prompt and its version
+func (t *TranslatorClient) buildTranslationPrompt(fields TranslationFields, locale Locale) (string, string, error) {
+	return t.prompts.Render(LLMTaskTranslation, TranslationPrompt{Locale: locale, Fields: fields})
 }
 
 // SanitizeUTF8 ensures that all strings are valid UTF-8 before storing in MongoDB
@@ -426,8 +342,9 @@ func (t *TranslatorClient) parseTranslationResponse(response string) (*Translati
 
 // Close releases resources used by the translator client
 func (t *TranslatorClient) Close() {
-	if t.client != nil {
-		t.client.Close()
-		log.Println("Translator client closed")
+	if err := t.provider.Close(); err != nil {
+		log.Printf("Error closing translator client: %v", err)
+		return
 	}
+	log.Println("Translator client closed")
 }
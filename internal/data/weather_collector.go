@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -25,69 +26,121 @@ type OpenWeatherMapResponse struct {
 	Name string `json:"name"`
 }
 
-// WeatherCollector collects real weather data from OpenWeatherMap
-type WeatherCollector struct {
+// RegionWeather is the weather of one city in a fishing region. The manager aggregates the
+// cities of each region into its RegionalWeather.
+type RegionWeather struct {
+	RegionID string
+	CityID   string
+	Weather  *WeatherInfo
+}
+
+// RegionWeatherCollector collects the weather of every city in the fishing regions from
+// OpenWeatherMap at once
+type RegionWeatherCollector struct {
 	apiKey      string
-	cityIDs     []string // List of city IDs to cycle through
-	currentCity int      // Index of the current city
+	regions     []Region
 	client      *http.Client
+	httpOptions HTTPOptions
 }
 
-// NewWeatherCollector creates a new weather data collector using the OpenWeatherMap API
-func NewWeatherCollector(apiKey string) *WeatherCollector {
-	// List of major city IDs (you can expand or customize this list)
-	cityIDs := []string{
-		"5128581", // New York
-		"2643743", // London
-		"1850147", // Tokyo
-		"2147714", // Sydney
-		"2988507", // Paris
-		"2950159", // Berlin
-		"3451190", // Rio de Janeiro
-	}
+// NewRegionWeatherCollector creates a weather collector for the cities of the given regions
+func NewRegionWeatherCollector(apiKey string, regions []Region) *RegionWeatherCollector {
+	return NewRegionWeatherCollectorWithHTTP(apiKey, regions, HTTPOptions{})
+}
 
-	return &WeatherCollector{
+// NewRegionWeatherCollectorWithHTTP creates a region weather collector that makes its requests through the given options
+func NewRegionWeatherCollectorWithHTTP(apiKey string, regions []Region, opts HTTPOptions) *RegionWeatherCollector {
+	return &RegionWeatherCollector{
 		apiKey:      apiKey,
-		cityIDs:     cityIDs,
-		currentCity: 0,
-		client:      &http.Client{Timeout: 10 * time.Second},
+		regions:     regions,
+		client:      opts.client(),
+		httpOptions: opts,
 	}
 }
 
-// Collect retrieves real weather data from OpenWeatherMap API
-func (c *WeatherCollector) Collect(ctx context.Context) (*DataEvent, error) {
-	// Cycle through cities
-	cityID := c.cityIDs[c.currentCity]
-	c.currentCity = (c.currentCity + 1) % len(c.cityIDs)
+// Collect retrieves the weather of each city, skipping cities whose weather is unavailable.
+// The event value is a []*RegionWeather.
+func (c *RegionWeatherCollector) Collect(ctx context.Context) (*DataEvent, error) {
+	collected, err := c.collectRegions(ctx, c.regions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataEvent{
+		Type:      WeatherData,
+		Value:     collected,
+		Timestamp: time.Now(),
+		Source:    "openweathermap-api",
+	}, nil
+}
 
+// collectRegions retrieves the weather of each city in the regions, skipping cities whose
+// weather is unavailable. A city listed by several regions is requested once.
+func (c *RegionWeatherCollector) collectRegions(ctx context.Context, regions []Region) ([]*RegionWeather, error) {
+	var collected []*RegionWeather
+	cities := make(map[string]*WeatherInfo) // Weather of the cities requested so far; nil when unavailable
+	var lastErr error
+regions:
+	for _, region := range regions {
+		for _, cityID := range region.CityIDs {
+			weatherInfo, requested := cities[cityID]
+			if !requested {
+				var err error
+				weatherInfo, _, err = c.collectCity(ctx, cityID)
+				cities[cityID] = weatherInfo
+				if err != nil {
+					logError("Error collecting weather data for city %s in region %s: %v", cityID, region.ID, err)
+					lastErr = err
+					// The remaining cities would hit the same rate limit or quota
+					var statusErr *StatusError
+					var quotaErr *QuotaError
+					if (errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests) || errors.As(err, &quotaErr) {
+						break regions
+					}
+					continue
+				}
+			}
+			if weatherInfo != nil {
+				collected = append(collected, &RegionWeather{RegionID: region.ID, CityID: cityID, Weather: weatherInfo})
+			}
+		}
+	}
+	if len(collected) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("no weather data collected: %w", lastErr)
+	}
+	return collected, nil
+}
+
+// collectCity retrieves the current weather of one OpenWeatherMap city
+func (c *RegionWeatherCollector) collectCity(ctx context.Context, cityID string) (*WeatherInfo, *OpenWeatherMapResponse, error) {
 	// Construct API URL
-	url := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/weather?id=%s&units=metric&appid=%s",
+	url := c.httpOptions.url("https://api.openweathermap.org", fmt.Sprintf(
+		"/data/2.5/weather?id=%s&units=metric&appid=%s",
 		cityID, c.apiKey,
-	)
+	))
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+		return nil, nil, fmt.Errorf("error creating request: %v", err)
 	}
 
 	// Make the request
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+	if err := checkStatus(resp); err != nil {
+		return nil, nil, err
 	}
 
 	// Parse the response
 	var owmResponse OpenWeatherMapResponse
 	if err := json.NewDecoder(resp.Body).Decode(&owmResponse); err != nil {
-		return nil, fmt.Errorf("error decoding response: %v", err)
+		return nil, nil, fmt.Errorf("error decoding response: %v", err)
 	}
 
 	// Extract weather condition
@@ -96,17 +149,12 @@ func (c *WeatherCollector) Collect(ctx context.Context) (*DataEvent, error) {
 		condition = owmResponse.Weather[0].Main
 	}
 
-	// Determine if the weather is extreme
-	isExtreme := false
-	if owmResponse.Main.Temp > 35 || owmResponse.Main.Temp < -10 ||
-		owmResponse.Wind.Speed > 20 || condition == "Thunderstorm" ||
-		condition == "Tornado" || condition == "Hurricane" {
-		isExtreme = true
-	}
-
 	// Convert wind speed from m/s to km/h
 	windKph := owmResponse.Wind.Speed * 3.6
 
+	// Determine if the weather is extreme
+	isExtreme := IsExtremeWeather(condition, owmResponse.Main.Temp, windKph)
+
 	// Create WeatherInfo
 	weatherInfo := &WeatherInfo{
 		Condition: condition,
@@ -117,46 +165,16 @@ func (c *WeatherCollector) Collect(ctx context.Context) (*DataEvent, error) {
 		IsExtreme: isExtreme,
 	}
 
-	return &DataEvent{
-		Type:      WeatherData,
-		Value:     weatherInfo,
-		Timestamp: time.Now(),
-		Source:    "openweathermap-api",
-		Raw:       owmResponse,
-	}, nil
+	return weatherInfo, &owmResponse, nil
 }
 
 // GetType returns the type of data collected
-func (c *WeatherCollector) GetType() DataType {
+func (c *RegionWeatherCollector) GetType() DataType {
 	return WeatherData
 }
 
-// Start begins periodic collection of weather data
-func (c *WeatherCollector) Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent) {
-	log.Printf("Starting OpenWeatherMap collector with interval %v", interval)
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	// Collect immediately on start
-	event, err := c.Collect(ctx)
-	if err == nil {
-		eventCh <- event
-	} else {
-		log.Printf("Error collecting weather data: %v", err)
-	}
-
-	for {
-		select {
-		case <-ticker.C:
-			event, err := c.Collect(ctx)
-			if err == nil {
-				eventCh <- event
-			} else {
-				log.Printf("Error collecting weather data: %v", err)
-			}
-		case <-ctx.Done():
-			log.Println("Weather collector stopped")
-			return
-		}
-	}
+// Start begins periodic collection of weather data for every region
+func (c *RegionWeatherCollector) Start(ctx context.Context, interval time.Duration, eventCh chan<- *DataEvent) {
+	log.Printf("Starting OpenWeatherMap region collector with interval %v", interval)
+	runCollector(ctx, c, interval, eventCh)
 }
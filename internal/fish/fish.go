@@ -3,6 +3,9 @@ package fish
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"fish-generate/internal/data"
 )
 
 // Rarity represents the rarity level of a fish
@@ -16,19 +19,8 @@ const (
 	Legendary Rarity = "Legendary"
 )
 
-// Fish represents a fish species with its characteristics
-type Fish struct {
-	Name             string      `json:"name"`
-	Rarity           Rarity      `json:"rarity"`
-	Size             float64     `json:"size"`  // in meters
-	Value            float64     `json:"value"` // in USD
-	Description      string      `json:"description"`
-	Effect           string      `json:"effect"`            // Human-readable effect description
-	DataSource       string      `json:"data_source"`       // what data source influenced this fish
-	IsAIGenerated    bool        `json:"is_ai_generated"`   // whether this fish was generated by AI
-	StatEffects      StatEffects `json:"stat_effects"`      // Specific effects on game stats
-	GenerationReason string      `json:"generation_reason"` // why this fish was generated
-}
+// Fish is the canonical fish record shared with the storage and API layers
+type Fish = data.FishRecord
 
 // NewFish creates a new fish with the given characteristics
 func NewFish(name string, rarity Rarity, size, value float64, description, effect, dataSource string, reason string) *Fish {
@@ -40,20 +32,21 @@ func NewFish(name string, rarity Rarity, size, value float64, description, effec
 
 	return &Fish{
 		Name:             name,
-		Rarity:           rarity,
-		Size:             size,
+		Rarity:           string(rarity),
+		Length:           size,
 		Value:            value,
 		Description:      description,
 		Effect:           effect,
+		GeneratedAt:      time.Now(),
 		DataSource:       dataSource,
 		IsAIGenerated:    isAIGenerated,
-		StatEffects:      statEffects,
+		StatEffects:      statEffects.Records(),
 		GenerationReason: reason,
 	}
 }
 
 // GetFishReport returns a formatted report for a single fish
-func (f *Fish) GetFishReport() string {
+func GetFishReport(f *Fish) string {
 	var sb strings.Builder
 
 	// Determine source type and prepare header
@@ -72,13 +65,13 @@ func (f *Fish) GetFishReport() string {
 	sb.WriteString(headerStyle + "\n")
 	sb.WriteString(fmt.Sprintf("Name: %s\n", f.Name))
 	sb.WriteString(fmt.Sprintf("Rarity: %s\n", f.Rarity))
-	sb.WriteString(fmt.Sprintf("Size: %.2f meters\n", f.Size))
+	sb.WriteString(fmt.Sprintf("Size: %.2f meters\n", f.Length))
 	sb.WriteString(fmt.Sprintf("Value: $%.2f\n", f.Value))
 	sb.WriteString(fmt.Sprintf("Source: %s\n", sourceLabel))
 	sb.WriteString(fmt.Sprintf("Generated due to: %s\n", f.GenerationReason))
 	sb.WriteString(fmt.Sprintf("Description: %s\n", f.Description))
 	sb.WriteString(fmt.Sprintf("Effect: %s\n", f.Effect))
-	sb.WriteString(fmt.Sprintf("Game Stats: %s\n", FormatEffects(f.StatEffects)))
+	sb.WriteString(fmt.Sprintf("Game Stats: %s\n", FormatEffects(StatEffectsFromRecords(f.StatEffects))))
 
 	// Add bottom border that matches the header
 	sb.WriteString(strings.Repeat("=", len(headerStyle)) + "\n")
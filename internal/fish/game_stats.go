@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+
+	"fish-generate/internal/data"
 )
 
 // StatType represents types of game statistics that can be affected by fish
@@ -80,6 +82,46 @@ func FormatEffects(effects StatEffects) string {
 	return result
 }
 
+// Records converts the effects into the stat effect entries stored on a fish record
+func (effects StatEffects) Records() []data.FishStatEffect {
+	records := make([]data.FishStatEffect, len(effects))
+	for i, effect := range effects {
+		modifier := effect.Value
+		if effect.IsPercent {
+			modifier = effect.Value / 100.0
+		}
+
+		records[i] = data.FishStatEffect{
+			EffectType:  "player",
+			Modifier:    modifier,
+			Description: FormatEffects(StatEffects{effect}),
+			Stat:        string(effect.Stat),
+			Value:       effect.Value,
+			IsPercent:   effect.IsPercent,
+			Duration:    effect.Duration,
+		}
+	}
+	return records
+}
+
+// StatEffectsFromRecords returns the game stat effects stored on a fish record.
+// Entries without a stat, such as AI environment effects, are skipped.
+func StatEffectsFromRecords(records []data.FishStatEffect) StatEffects {
+	effects := make(StatEffects, 0, len(records))
+	for _, record := range records {
+		if record.Stat == "" {
+			continue
+		}
+		effects = append(effects, StatEffect{
+			Stat:      StatType(record.Stat),
+			Value:     record.Value,
+			IsPercent: record.IsPercent,
+			Duration:  record.Duration,
+		})
+	}
+	return effects
+}
+
 // formatStatName returns a human-readable name for a stat type
 func formatStatName(stat StatType) string {
 	switch stat {
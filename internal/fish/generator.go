@@ -18,15 +18,15 @@ type Generator struct {
 	rand             *rand.Rand
 	geminiClient     *data.GeminiClient
 	useAI            bool
-	geminiAPIKey     string
 	options          GeneratorOptions
 }
 
 // GeneratorOptions provides configuration options for the fish generator
 type GeneratorOptions struct {
-	GeminiAPIKey string // API key for Google Gemini
-	UseAI        bool   // Whether to use AI for fish generation
-	TestMode     bool   // Whether to run in test mode
+	GeminiAPIKey string       // API key for Google Gemini
+	UseAI        bool         // Whether to use AI for fish generation
+	TestMode     bool         // Whether to run in test mode
+	LLM          data.LLMTask // Provider for AI fish; Gemini with GeminiAPIKey when unset
 }
 
 // NewGenerator creates a new fish generator
@@ -56,9 +56,8 @@ func NewGenerator(opts ...GeneratorOptions) *Generator {
 		g.useAI = opt.UseAI
 		g.options = opt
 
-		if opt.UseAI && opt.GeminiAPIKey != "" {
-			g.geminiClient = data.NewGeminiClient(opt.GeminiAPIKey)
-			g.geminiAPIKey = opt.GeminiAPIKey
+		if opt.UseAI && (opt.GeminiAPIKey != "" || opt.LLM.Provider != nil) {
+			g.geminiClient = data.NewGeminiClientWithLLM(opt.LLM.WithDefaults(data.LLMTaskFishFromNews, opt.GeminiAPIKey))
 		}
 	}
 
@@ -293,23 +292,15 @@ func (g *Generator) GenerateFromNews(ctx context.Context, newsItem *data.NewsIte
 	return g.generateRuleBasedFishFromNews(newsItem, reason)
 }
 
-// generateAIFishFromNews creates a fish using the Gemini API
+// generateAIFishFromNews creates a fish using the configured LLM provider
 func (g *Generator) generateAIFishFromNews(ctx context.Context, newsItem *data.NewsItem, reason string) (*Fish, error) {
-	// Create a new context with the API key
-	apiKeyCtx := context.WithValue(ctx, "gemini_api_key", g.geminiAPIKey)
-
-	// Generate fish using Gemini
-	aiResponse, err := g.geminiClient.GenerateFishFromNews(apiKeyCtx, newsItem)
+	// Generate fish using the LLM
+	aiResponse, err := g.geminiClient.GenerateFishFromNews(ctx, newsItem)
 	if err != nil {
-		return nil, fmt.Errorf("Gemini API error: %w", err)
-	}
-
-	// Validate the response
-	if aiResponse.Name == "" || aiResponse.Description == "" || aiResponse.Effect == "" {
-		return nil, fmt.Errorf("incomplete AI response")
+		return nil, fmt.Errorf("LLM error: %w", err)
 	}
 
-	// Convert rarity string to enum
+	// The response was validated against data.NewsFishSchema, so rarity is one of the declared values
 	var rarity Rarity
 	switch strings.ToLower(aiResponse.Rarity) {
 	case "common":
@@ -327,23 +318,31 @@ func (g *Generator) generateAIFishFromNews(ctx context.Context, newsItem *data.N
 		rarity = Uncommon
 	}
 
-	// Enhance description with appearance if provided
-	fullDescription := aiResponse.Description
-	if aiResponse.Appearance != "" {
-		fullDescription = fullDescription + " " + aiResponse.Appearance
-	}
-
 	// Create fish with AI-generated attributes
-	return NewFish(
+	fish := NewFish(
 		aiResponse.Name,
 		rarity,
 		aiResponse.Size,
 		aiResponse.Value,
-		fullDescription,
+		aiResponse.Description,
 		aiResponse.Effect,
 		"news-ai",
 		reason,
-	), nil
+	)
+
+	// Keep the remaining AI fields instead of folding them into the description
+	fish.Appearance = aiResponse.Appearance
+	fish.Color = aiResponse.Color
+	fish.Habitat = aiResponse.Habitat
+	fish.Diet = aiResponse.Diet
+	fish.FavoriteWeather = aiResponse.FavoriteWeather
+	fish.CatchChance = aiResponse.CatchChance
+	fish.ExistenceReason = aiResponse.ExistenceReason
+	fish.OriginContext = aiResponse.OriginContext
+	fish.PromptVersion = aiResponse.PromptVersion
+	fish.UsedArticles = []data.UsedArticle{data.NewUsedArticle(newsItem, fish.GeneratedAt, false)}
+
+	return fish, nil
 }
 
 // generateRuleBasedFishFromNews is the original news-based fish generation logic
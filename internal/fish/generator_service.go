@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 	"time"
+
+	"fish-generate/internal/data"
 )
 
 // WeatherInfo contains basic weather data for fish generation
@@ -22,24 +24,21 @@ type FishGenerationService struct {
 	}
 }
 
-// NewFishGenerationService creates a new fish generation service
-func NewFishGenerationService(apiKey string, storageAdapter StorageAdapter, dataManager interface {
+// NewFishGenerationService creates a new fish generation service.
+// AI generation is used when a Gemini API key is set or a non-Gemini provider is configured.
+func NewFishGenerationService(options ServiceOptions, dataManager interface {
 	GenerateFishFromContext(ctx context.Context, reason string) error
 }) *FishGenerationService {
-	// Create service options
-	options := ServiceOptions{
-		GeminiAPIKey:   apiKey,
-		UseAI:          apiKey != "",
-		StorageAdapter: storageAdapter,
-	}
+	llm := options.LLM
+	options.UseAI = options.GeminiAPIKey != "" || (llm.Provider != nil && llm.Provider.Name() != data.LLMProviderGemini)
 
 	// Create the underlying service
 	service := NewServiceSimple(options)
 
 	return &FishGenerationService{
 		service:        service,
-		apiKey:         apiKey,
-		storageAdapter: storageAdapter,
+		apiKey:         options.GeminiAPIKey,
+		storageAdapter: options.StorageAdapter,
 		dataManager:    dataManager,
 	}
 }
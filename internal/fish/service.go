@@ -29,21 +29,28 @@ type Service struct {
 	collectors     []data.DataCollector
 	dataEvents     chan *data.DataEvent
 	fishCreated    chan *Fish
-	fishStore      map[string][]*Fish // Store fish by data source type
-	useAI          bool               // Whether to use AI for fish generation
-	apiKey         string             // API key for AI generation
-	storageAdapter StorageAdapter     // For database operations (optional)
+	fishStore      map[string][]*Fish     // Store fish by data source type
+	useAI          bool                   // Whether to use AI for fish generation
+	apiKey         string                 // API key for AI generation
+	storageAdapter StorageAdapter         // For database operations (optional)
+	speciesGuard   *data.SpeciesGuard     // Checks new fish against the catalog before they are saved (optional)
+	moderator      *data.ContentModerator // Quarantines fish with unsafe content before they are saved (optional)
+	reviewFish     bool                   // Save new fish as drafts that an admin must approve
 }
 
 // ServiceOptions contains configuration options for the service
 type ServiceOptions struct {
-	GeminiAPIKey   string         // API key for Google Gemini
-	UseAI          bool           // Whether to use AI for fish generation
-	OpenWeatherKey string         // API key for OpenWeatherMap
-	EIAKey         string         // API key for EIA (Energy Information Administration)
-	NewsAPIKey     string         // API key for NewsAPI
-	TestMode       bool           // Whether to run in test mode (shorter intervals)
-	StorageAdapter StorageAdapter // Optional adapter for database operations
+	GeminiAPIKey        string                 // API key for Google Gemini
+	UseAI               bool                   // Whether to use AI for fish generation
+	OpenWeatherKey      string                 // API key for OpenWeatherMap
+	EIAKey              string                 // API key for EIA (Energy Information Administration)
+	NewsAPIKey          string                 // API key for NewsAPI
+	TestMode            bool                   // Whether to run in test mode (shorter intervals)
+	StorageAdapter      StorageAdapter         // Optional adapter for database operations
+	LLM                 data.LLMTask           // Provider for AI fish; Gemini with GeminiAPIKey when unset
+	SpeciesGuard        *data.SpeciesGuard     // Renames or marks as variants fish that duplicate existing species
+	Moderator           *data.ContentModerator // Quarantines fish with unsafe content instead of publishing them; optional
+	ReviewGeneratedFish bool                   // Save generated fish as drafts that an admin must approve
 }
 
 // NewService creates a new fish generation service
@@ -59,6 +66,7 @@ func NewService(collectors []data.DataCollector, opts ...ServiceOptions) *Servic
 		GeminiAPIKey: options.GeminiAPIKey,
 		UseAI:        options.UseAI,
 		TestMode:     options.TestMode,
+		LLM:          options.LLM,
 	}
 
 	return &Service{
@@ -70,6 +78,9 @@ func NewService(collectors []data.DataCollector, opts ...ServiceOptions) *Servic
 		useAI:          options.UseAI,
 		apiKey:         options.GeminiAPIKey,
 		storageAdapter: options.StorageAdapter,
+		speciesGuard:   options.SpeciesGuard,
+		moderator:      options.Moderator,
+		reviewFish:     options.ReviewGeneratedFish,
 	}
 }
 
@@ -84,6 +95,7 @@ func NewServiceSimple(opts ...ServiceOptions) *Service {
 		GeminiAPIKey: options.GeminiAPIKey,
 		UseAI:        options.UseAI,
 		TestMode:     options.TestMode,
+		LLM:          options.LLM,
 	}
 
 	return &Service{
@@ -94,6 +106,9 @@ func NewServiceSimple(opts ...ServiceOptions) *Service {
 		useAI:          options.UseAI,
 		apiKey:         options.GeminiAPIKey,
 		storageAdapter: options.StorageAdapter,
+		speciesGuard:   options.SpeciesGuard,
+		moderator:      options.Moderator,
+		reviewFish:     options.ReviewGeneratedFish,
 	}
 }
 
@@ -207,8 +222,27 @@ func (s *Service) GenerateFish(ctx context.Context, eventType data.DataType, val
 
 	// Save to database if we have a storage adapter
 	if s.storageAdapter != nil {
+		// AI fish can be renamed by the model; rule-based fish become variants of the species they repeat
+		var renamer data.SpeciesRenamer
+		if fish.IsAIGenerated && s.generator.geminiClient != nil {
+			renamer = s.generator.geminiClient
+		}
+		s.speciesGuard.Resolve(ctx, fish, renamer)
+
+		// Hold back fish with unsafe content, and every fish when review is required
+		fish.Status = data.FishStatusPublished
+		if s.reviewFish {
+			fish.Status = data.FishStatusDraft
+		}
+		if flags := s.moderator.Moderate(ctx, fish); len(flags) > 0 {
+			fish.Status = data.FishStatusQuarantined
+			fish.ModerationFlags = flags
+		}
+
 		if err := s.storageAdapter.SaveFishData(ctx, fish); err != nil {
 			log.Printf("Warning: failed to save fish to database: %v", err)
+		} else if fish.Status == data.FishStatusQuarantined {
+			log.Printf("Fish quarantined for review: %s (ID: %s, flagged by %s)", fish.Name, fish.ID, fish.ModerationFlags[0].Rule)
 		}
 	}
 
@@ -287,7 +321,7 @@ func (s *Service) processDataEvents(ctx context.Context) {
 
 			if fish != nil {
 				log.Printf("Generated %s fish: %s (Rarity: %s, Size: %.2f, Value: $%.2f)",
-					event.Type, fish.Name, fish.Rarity, fish.Size, fish.Value)
+					event.Type, fish.Name, fish.Rarity, fish.Length, fish.Value)
 			}
 		case <-ctx.Done():
 			return
@@ -369,12 +403,13 @@ func (s *Service) GetFishReport() string {
 
 	// Record stats
 	for _, fish := range allFish {
-		rarityCount[fish.Rarity]++
+		rarity := Rarity(fish.Rarity)
+		rarityCount[rarity]++
 		sourceCount[fish.DataSource]++
 
 		// Record stat effects
-		for _, effect := range fish.StatEffects {
-			rarityStats[fish.Rarity][effect.Stat]++
+		for _, effect := range StatEffectsFromRecords(fish.StatEffects) {
+			rarityStats[rarity][effect.Stat]++
 		}
 	}
 
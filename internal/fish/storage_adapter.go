@@ -5,11 +5,11 @@ import (
 	"fmt"
 )
 
-// StorageWrapper wraps a storage adapter that accepts interface{} and makes it compatible
-// with the fish.StorageAdapter interface that requires *Fish
+// StorageWrapper wraps a storage adapter and exposes only the methods
+// required by the fish.StorageAdapter interface
 type StorageWrapper struct {
 	adapter interface {
-		SaveFishData(ctx context.Context, fish interface{}) error
+		SaveFishData(ctx context.Context, fish *Fish) error
 		GetDailyFishCount(ctx context.Context) (int, error)
 		GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*Fish, error)
 		GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*Fish, error)
@@ -21,7 +21,7 @@ type StorageWrapper struct {
 func NewStorageWrapper(adapter interface{}) (*StorageWrapper, error) {
 	// Check if the adapter implements the necessary methods
 	if a, ok := adapter.(interface {
-		SaveFishData(ctx context.Context, fish interface{}) error
+		SaveFishData(ctx context.Context, fish *Fish) error
 		GetDailyFishCount(ctx context.Context) (int, error)
 		GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*Fish, error)
 		GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*Fish, error)
@@ -33,7 +33,7 @@ func NewStorageWrapper(adapter interface{}) (*StorageWrapper, error) {
 	return nil, fmt.Errorf("adapter does not implement required methods")
 }
 
-// SaveFishData converts the *Fish to interface{} and calls the underlying adapter
+// SaveFishData passes through to the underlying adapter
 func (w *StorageWrapper) SaveFishData(ctx context.Context, fish *Fish) error {
 	return w.adapter.SaveFishData(ctx, fish)
 }
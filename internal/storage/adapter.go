@@ -2,12 +2,11 @@ package storage
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"fish-generate/internal/data"
-	"fish-generate/internal/fish"
 )
 
 // DatabaseClient defines the interface for MongoDB operations
@@ -15,8 +14,11 @@ type DatabaseClient interface {
 	SaveWeatherData(ctx context.Context, weatherInfo *data.WeatherInfo, regionID, cityID string) error
 	SavePriceData(ctx context.Context, assetType string, price, volume, changePercent, volumeChange float64, source string) error
 	SaveNewsData(ctx context.Context, newsItem *data.NewsItem) error
-	SaveFishData(ctx context.Context, fishData interface{}) error
+	SaveFishData(ctx context.Context, fish *data.FishRecord) error
+	UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error
 	GetRecentWeatherData(ctx context.Context, regionID string, limit int) ([]*WeatherData, error)
+	SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error
+	GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error)
 	GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error)
 	GetRecentNewsData(ctx context.Context, limit int) ([]*NewsData, error)
 	GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*FishData, error)
@@ -27,12 +29,25 @@ type DatabaseClient interface {
 	GetGenerationQueue(ctx context.Context) ([]data.GenerationRequest, error)
 	GetDailyFishCount(ctx context.Context) (int, error)
 	GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*FishData, error)
-	GetFishByID(ctx context.Context, id string) (map[string]interface{}, error)
+	QueryFish(ctx context.Context, query FishQuery) ([]*FishData, error)
+	GetFishData(ctx context.Context, id string) (*FishData, error)
 	SaveTranslatedFish(ctx context.Context, translatedFish *data.TranslatedFish) error
-	GetTranslatedFish(ctx context.Context, originalID string) (*data.TranslatedFish, error)
-	GetUntranslatedFishIDs(ctx context.Context, limit int) ([]string, error)
-	GetUntranslatedFish(ctx context.Context, limit int) ([]map[string]interface{}, error)
-	UpdateFishWithTranslation(ctx context.Context, fishID interface{}, translatedFish map[string]interface{}) error
+	GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error)
+	GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error)
+	GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error)
+	SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error
+	RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error
+	DeleteLLMCacheEntry(ctx context.Context, key string) error
+	PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error)
+	SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error
+	GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error)
+	IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error
+	GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error)
+	GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error)
+	SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error
+	GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error)
+	IncrementAPIQuotaUsage(ctx context.Context, source, window string) error
+	GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error)
 }
 
 // MongoDBAdapter adapts the MongoDB interface to the internal data interfaces
@@ -60,9 +75,15 @@ func (a *MongoDBAdapter) SaveNewsData(ctx context.Context, newsItem *data.NewsIt
 	return a.db.SaveNewsData(ctx, newsItem)
 }
 
-// SaveFishData saves fish data to MongoDB
-func (a *MongoDBAdapter) SaveFishData(ctx context.Context, fishData interface{}) error {
-	return a.db.SaveFishData(ctx, fishData)
+// SaveFishData saves a fish record and sets its ID
+func (a *MongoDBAdapter) SaveFishData(ctx context.Context, fish *data.FishRecord) error {
+	return a.db.SaveFishData(ctx, fish)
+}
+
+// UpdateFishData replaces the stored fields of an existing fish record that still has
+// the given status, or of any fish when status is empty
+func (a *MongoDBAdapter) UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error {
+	return a.db.UpdateFishData(ctx, fish, status)
 }
 
 // GetRecentWeatherData retrieves recent weather data from MongoDB
@@ -75,15 +96,21 @@ func (a *MongoDBAdapter) GetRecentWeatherData(ctx context.Context, regionID stri
 	// Convert from MongoDB type to internal type
 	result := make([]*data.WeatherInfo, len(mongoData))
 	for i, item := range mongoData {
-		result[i] = &data.WeatherInfo{
-			Condition: item.Condition,
-			TempC:     item.TempC,
-			IsExtreme: item.TempC > 35 || item.TempC < -5, // Simple extreme weather detection
-		}
+		result[i] = convertToWeatherInfo(item)
 	}
 	return result, nil
 }
 
+// SaveRegionWeather replaces the aggregated weather of a region
+func (a *MongoDBAdapter) SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error {
+	return a.db.SaveRegionWeather(ctx, weather)
+}
+
+// GetRegionWeather returns the aggregated weather of a region, or nil if none was saved
+func (a *MongoDBAdapter) GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error) {
+	return a.db.GetRegionWeather(ctx, regionID)
+}
+
 // GetRecentPriceData retrieves recent price data from MongoDB
 func (a *MongoDBAdapter) GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error) {
 	return a.db.GetRecentPriceData(ctx, assetType, limit)
@@ -167,52 +194,22 @@ func getStringField(item interface{}, fieldName string) (string, bool) {
 	return "", false
 }
 
-// GetFishByRegion retrieves fish by region ID
-func (a *MongoDBAdapter) GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*fish.Fish, error) {
+// GetFishByRegion retrieves fish by region ID, including fish saved without a region
+func (a *MongoDBAdapter) GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*data.FishRecord, error) {
 	mongoData, err := a.db.GetFishByRegion(ctx, regionID, limit)
 	if err != nil {
 		return nil, err
 	}
-
-	// Convert from MongoDB type to internal type
-	result := make([]*fish.Fish, len(mongoData))
-	for i, item := range mongoData {
-		result[i] = &fish.Fish{
-			Name:             item.Name,
-			Rarity:           fish.Rarity(item.Rarity),
-			Size:             item.Length,
-			Value:            float64(item.Weight * 10), // Approximation
-			Description:      item.Description,
-			DataSource:       item.DataSource,
-			IsAIGenerated:    item.IsAIGenerated,
-			GenerationReason: item.GenerationReason,
-		}
-	}
-	return result, nil
+	return fishRecords(mongoData), nil
 }
 
 // GetFishByDataSource retrieves fish by data source
-func (a *MongoDBAdapter) GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*fish.Fish, error) {
+func (a *MongoDBAdapter) GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*data.FishRecord, error) {
 	mongoData, err := a.db.GetFishByDataSource(ctx, dataSource, limit)
 	if err != nil {
 		return nil, err
 	}
-
-	// Convert from MongoDB type to internal type
-	result := make([]*fish.Fish, len(mongoData))
-	for i, item := range mongoData {
-		result[i] = &fish.Fish{
-			Name:             item.Name,
-			Rarity:           fish.Rarity(item.Rarity),
-			Size:             item.Length,
-			Value:            float64(item.Weight * 10), // Approximation
-			Description:      item.Description,
-			DataSource:       item.DataSource,
-			IsAIGenerated:    item.IsAIGenerated,
-			GenerationReason: item.GenerationReason,
-		}
-	}
-	return result, nil
+	return fishRecords(mongoData), nil
 }
 
 // SaveUsedNewsIDs saves used news IDs to MongoDB
@@ -241,29 +238,35 @@ func (a *MongoDBAdapter) GetDailyFishCount(ctx context.Context) (int, error) {
 }
 
 // GetSimilarFish retrieves a similar fish from MongoDB
-func (a *MongoDBAdapter) GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*fish.Fish, error) {
+func (a *MongoDBAdapter) GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*data.FishRecord, error) {
 	mongoData, err := a.db.GetSimilarFish(ctx, dataSource, rarityLevel)
 	if err != nil {
 		return nil, err
 	}
+	return mongoData.Record(), nil
+}
 
-	// Convert from MongoDB type to internal type
-	result := &fish.Fish{
-		Name:             mongoData.Name,
-		Rarity:           fish.Rarity(mongoData.Rarity),
-		Size:             mongoData.Length,
-		Value:            float64(mongoData.Weight * 10), // Approximation
-		Description:      mongoData.Description,
-		DataSource:       mongoData.DataSource,
-		IsAIGenerated:    mongoData.IsAIGenerated,
-		GenerationReason: mongoData.GenerationReason,
+// QueryFish returns one page of the fish catalog
+func (a *MongoDBAdapter) QueryFish(ctx context.Context, query FishQuery) (*FishPage, error) {
+	query, err := query.normalize()
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+
+	rows, err := a.db.QueryFish(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return newFishPage(query, rows), nil
 }
 
-// GetFishByID retrieves a fish by its ID
-func (a *MongoDBAdapter) GetFishByID(ctx context.Context, id string) (map[string]interface{}, error) {
-	return a.db.GetFishByID(ctx, id)
+// GetFishRecord retrieves the full stored record of a fish by its ID
+func (a *MongoDBAdapter) GetFishRecord(ctx context.Context, id string) (*data.FishRecord, error) {
+	fishData, err := a.db.GetFishData(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return fishData.Record(), nil
 }
 
 // SaveTranslatedFish saves translated fish data to MongoDB
@@ -271,164 +274,95 @@ func (a *MongoDBAdapter) SaveTranslatedFish(ctx context.Context, translatedFish
 	return a.db.SaveTranslatedFish(ctx, translatedFish)
 }
 
-// GetTranslatedFish retrieves a translated fish by original ID
-func (a *MongoDBAdapter) GetTranslatedFish(ctx context.Context, originalID string) (*data.TranslatedFish, error) {
-	return a.db.GetTranslatedFish(ctx, originalID)
+// GetTranslatedFish retrieves the translation of a fish into the given language
+func (a *MongoDBAdapter) GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error) {
+	return a.db.GetTranslatedFish(ctx, originalID, language)
 }
 
-// GetUntranslatedFishIDs retrieves IDs of fish that haven't been translated yet
-func (a *MongoDBAdapter) GetUntranslatedFishIDs(ctx context.Context, limit int) ([]string, error) {
-	return a.db.GetUntranslatedFishIDs(ctx, limit)
+// GetUntranslatedFishIDs retrieves IDs of published fish that have no translation in the given language
+func (a *MongoDBAdapter) GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error) {
+	return a.db.GetUntranslatedFishIDs(ctx, language, limit)
 }
 
-// GetUntranslatedFish retrieves fish that haven't been translated to Vietnamese yet
-func (a *MongoDBAdapter) GetUntranslatedFish(ctx context.Context, limit int) ([]map[string]interface{}, error) {
-	return a.db.GetUntranslatedFish(ctx, limit)
+// GetLLMCacheEntry retrieves a cached LLM response
+func (a *MongoDBAdapter) GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error) {
+	return a.db.GetLLMCacheEntry(ctx, key)
 }
 
-// UpdateFishWithTranslation updates a fish document with translated fields
-func (a *MongoDBAdapter) UpdateFishWithTranslation(ctx context.Context, fishID interface{}, translatedFish map[string]interface{}) error {
-	return a.db.UpdateFishWithTranslation(ctx, fishID, translatedFish)
+// SaveLLMCacheEntry inserts or replaces a cached LLM response
+func (a *MongoDBAdapter) SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error {
+	return a.db.SaveLLMCacheEntry(ctx, entry)
 }
 
-// Helper functions to convert between MongoDB and data types
-
-// convertToFish converts MongoDB fish data to internal type
-func convertToFish(fishData *FishData) *fish.Fish {
-	// Extract stat effects
-	statEffects := make(fish.StatEffects, 0)
-	for _, effect := range fishData.StatEffects {
-		if statType, ok := effect["stat"].(string); ok {
-			statEffect := fish.StatEffect{
-				Stat: fish.StatType(statType),
-			}
-
-			// Extract value
-			if value, ok := effect["value"].(float64); ok {
-				statEffect.Value = value
-			}
+// RecordLLMCacheHit increments the hit count of a cached LLM response
+func (a *MongoDBAdapter) RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error {
+	return a.db.RecordLLMCacheHit(ctx, key, at)
+}
 
-			// Extract is_percentage
-			if isPercentage, ok := effect["is_percentage"].(bool); ok {
-				statEffect.IsPercent = isPercentage
-			}
+// DeleteLLMCacheEntry removes a cached LLM response
+func (a *MongoDBAdapter) DeleteLLMCacheEntry(ctx context.Context, key string) error {
+	return a.db.DeleteLLMCacheEntry(ctx, key)
+}
 
-			// Extract duration
-			if duration, ok := effect["duration"].(float64); ok {
-				statEffect.Duration = int(duration)
-			}
+// PruneLLMCache removes expired LLM responses and the oldest ones beyond maxEntries
+func (a *MongoDBAdapter) PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error) {
+	return a.db.PruneLLMCache(ctx, now, maxEntries)
+}
 
-			statEffects = append(statEffects, statEffect)
-		}
-	}
+// SaveLLMUsage records a single LLM call
+func (a *MongoDBAdapter) SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error {
+	return a.db.SaveLLMUsage(ctx, record)
+}
 
-	reason := fishData.GenerationReason
-	if reason == "" {
-		reason = "Unknown reason" // Provide default for older records
-	}
+// GetLLMUsageTotals aggregates LLM calls per UTC day, task and model
+func (a *MongoDBAdapter) GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error) {
+	return a.db.GetLLMUsageTotals(ctx, from, to)
+}
 
-	return &fish.Fish{
-		Name:             fishData.Name,
-		Description:      fishData.Description,
-		Rarity:           fish.Rarity(fishData.Rarity),
-		Size:             fishData.Length,
-		Value:            float64(int(fishData.Length * 10 * float64(rarityToMultiplier(fishData.Rarity)))),
-		Effect:           generateEffectFromStatEffects(statEffects),
-		DataSource:       fishData.DataSource,
-		IsAIGenerated:    fishData.IsAIGenerated,
-		StatEffects:      statEffects,
-		GenerationReason: reason,
-	}
+// IncrementExperimentCounter adds one to a counter of an experiment variant
+func (a *MongoDBAdapter) IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error {
+	return a.db.IncrementExperimentCounter(ctx, experiment, variant, counter)
 }
 
-// rarityToMultiplier converts a rarity string to a value multiplier
-func rarityToMultiplier(rarity string) int {
-	switch rarity {
-	case "Common":
-		return 1
-	case "Uncommon":
-		return 3
-	case "Rare":
-		return 6
-	case "Epic":
-		return 12
-	case "Legendary":
-		return 25
-	default:
-		return 1
-	}
+// GetExperimentCounts returns the counters of every variant of an experiment
+func (a *MongoDBAdapter) GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error) {
+	return a.db.GetExperimentCounts(ctx, experiment)
 }
 
-// generateEffectFromStatEffects creates a human-readable effect description
-func generateEffectFromStatEffects(effects fish.StatEffects) string {
-	if len(effects) == 0 {
-		return "No special effects."
-	}
+// GetSpeciesSummaries returns the ID, name and description of every stored fish
+func (a *MongoDBAdapter) GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error) {
+	return a.db.GetSpeciesSummaries(ctx)
+}
 
-	effectDescriptions := []string{}
-	for _, effect := range effects {
-		effectDesc := formatStatEffect(effect)
-		effectDescriptions = append(effectDescriptions, effectDesc)
-	}
+// SaveFishAuditEntry records an admin action on a fish
+func (a *MongoDBAdapter) SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error {
+	return a.db.SaveFishAuditEntry(ctx, entry)
+}
 
-	return strings.Join(effectDescriptions, " ")
+// GetFishAuditEntries returns the audit trail of a fish, or of every fish when fishID is empty, newest first
+func (a *MongoDBAdapter) GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error) {
+	return a.db.GetFishAuditEntries(ctx, fishID, limit)
 }
 
-// formatStatEffect formats a single stat effect
-func formatStatEffect(effect fish.StatEffect) string {
-	valueStr := ""
-	if effect.IsPercent {
-		valueStr = fmt.Sprintf("%.0f%%", effect.Value)
-	} else {
-		valueStr = fmt.Sprintf("%.0f", effect.Value)
-	}
+// IncrementAPIQuotaUsage adds one call to a source in a quota window
+func (a *MongoDBAdapter) IncrementAPIQuotaUsage(ctx context.Context, source, window string) error {
+	return a.db.IncrementAPIQuotaUsage(ctx, source, window)
+}
 
-	switch effect.Stat {
-	case fish.CatchChance:
-		return fmt.Sprintf("Increases catch chance by %s for %d seconds", valueStr, effect.Duration)
-	case fish.CriticalCatch:
-		return fmt.Sprintf("Increases critical catch rate by %s for %d seconds", valueStr, effect.Duration)
-	case fish.Luck:
-		return fmt.Sprintf("Increases fishing luck by %s for %d seconds", valueStr, effect.Duration)
-	case fish.StaminaRegen:
-		return fmt.Sprintf("Increases stamina regeneration by %s for %d seconds", valueStr, effect.Duration)
-	case fish.SellValue:
-		return fmt.Sprintf("Increases sell value of fish by %s for %d seconds", valueStr, effect.Duration)
-	case fish.MarketDemand:
-		return fmt.Sprintf("Increases market demand by %s for %d seconds", valueStr, effect.Duration)
-	case fish.BaitCost:
-		return fmt.Sprintf("Reduces bait cost by %s for %d seconds", valueStr, effect.Duration)
-	case fish.ExploreSpeed:
-		return fmt.Sprintf("Increases exploration speed by %s for %d seconds", valueStr, effect.Duration)
-	case fish.AreaAccess:
-		return fmt.Sprintf("Grants access to restricted fishing areas for %d seconds", effect.Duration)
-	case fish.WeatherResist:
-		return fmt.Sprintf("Increases weather resistance by %s for %d seconds", valueStr, effect.Duration)
-	case fish.StorageSpace:
-		return fmt.Sprintf("Increases storage capacity by %s for %d seconds", valueStr, effect.Duration)
-	case fish.PreserveDuration:
-		return fmt.Sprintf("Increases fish preservation time by %s for %d seconds", valueStr, effect.Duration)
-	case fish.CollectionBonus:
-		return fmt.Sprintf("Increases collection bonuses by %s for %d seconds", valueStr, effect.Duration)
-	default:
-		return fmt.Sprintf("Unknown effect on %s by %s for %d seconds", effect.Stat, valueStr, effect.Duration)
-	}
+// GetAPIQuotaUsage returns the calls made to a source in a quota window
+func (a *MongoDBAdapter) GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error) {
+	return a.db.GetAPIQuotaUsage(ctx, source, window)
 }
 
-// Convert stat effects from fish.StatEffects to []map[string]interface{}
-func convertStatEffects(effects fish.StatEffects) []map[string]interface{} {
-	result := make([]map[string]interface{}, len(effects))
+// Helper functions to convert between MongoDB and data types
 
-	for i, effect := range effects {
-		result[i] = map[string]interface{}{
-			"stat":          string(effect.Stat),
-			"value":         effect.Value,
-			"is_percentage": effect.IsPercent,
-			"duration":      effect.Duration,
-		}
+// fishRecords converts stored fish documents to canonical fish records
+func fishRecords(items []*FishData) []*data.FishRecord {
+	records := make([]*data.FishRecord, len(items))
+	for i, item := range items {
+		records[i] = item.Record()
 	}
-
-	return result
+	return records
 }
 
 // convertToWeatherInfo converts MongoDB weather data to internal type
@@ -439,7 +373,7 @@ func convertToWeatherInfo(mongoData *WeatherData) *data.WeatherInfo {
 		Humidity:  int(mongoData.Humidity),
 		WindKph:   mongoData.WindSpeed * 3.6, // Convert m/s to km/h
 		Location:  mongoData.RegionID,
-		IsExtreme: isExtremeWeather(mongoData.Condition, mongoData.TempC),
+		IsExtreme: data.IsExtremeWeather(mongoData.Condition, mongoData.TempC, mongoData.WindSpeed*3.6),
 	}
 }
 
@@ -456,98 +390,6 @@ func convertToNewsItem(mongoData *NewsData) *data.NewsItem {
 	}
 }
 
-// Helper functions for extracting data from text descriptions
-
-// extractColorFromDescription attempts to extract a color from fish description
-func extractColorFromDescription(description string) string {
-	colorKeywords := map[string]bool{
-		"red": true, "blue": true, "green": true, "yellow": true, "orange": true,
-		"purple": true, "black": true, "white": true, "silver": true, "gold": true,
-		"pink": true, "brown": true, "gray": true, "grey": true, "teal": true,
-		"crimson": true, "azure": true, "turquoise": true, "violet": true,
-	}
-
-	words := strings.Fields(strings.ToLower(description))
-	for _, word := range words {
-		clean := strings.Trim(word, ",.;:!?")
-		if colorKeywords[clean] {
-			return clean
-		}
-	}
-
-	// Default color if none found
-	return "silver"
-}
-
-// extractHabitatFromDescription attempts to extract a habitat from fish description
-func extractHabitatFromDescription(description string) string {
-	habitatKeywords := map[string]string{
-		"deep": "deep sea", "reef": "coral reef", "shallow": "shallow waters",
-		"coastal": "coastal waters", "river": "riverine", "lake": "lake",
-		"tropic": "tropical", "arctic": "arctic", "polar": "polar",
-		"warm": "warm waters", "cold": "cold waters", "fresh": "freshwater",
-		"salt": "saltwater", "ocean": "oceanic", "sea": "sea",
-		"cave": "underwater caves", "rocky": "rocky bottoms", "sandy": "sandy bottoms",
-	}
-
-	lowerDesc := strings.ToLower(description)
-	for keyword, habitat := range habitatKeywords {
-		if strings.Contains(lowerDesc, keyword) {
-			return habitat
-		}
-	}
-
-	// Default habitat if none found
-	return "oceanic"
-}
-
-// extractDietFromDescription attempts to extract a diet from fish description
-func extractDietFromDescription(description string) string {
-	dietKeywords := map[string]string{
-		"plankton": "plankton", "algae": "algae", "plant": "herbivore",
-		"crab": "crustaceans", "shrimp": "crustaceans", "worm": "worms",
-		"insect": "insects", "larva": "larvae", "fish": "piscivore",
-		"mollusk": "mollusks", "omnivore": "omnivore", "carnivore": "carnivore",
-		"herbivore": "herbivore", "coral": "coral polyps", "eat": "omnivore",
-		"feed": "omnivore", "prey": "carnivore", "hunt": "carnivore",
-	}
-
-	lowerDesc := strings.ToLower(description)
-	for keyword, diet := range dietKeywords {
-		if strings.Contains(lowerDesc, keyword) {
-			return diet
-		}
-	}
-
-	// Default diet if none found
-	return "omnivore"
-}
-
-// isExtremeWeather determines if weather conditions are extreme
-func isExtremeWeather(condition string, tempC float64) bool {
-	extremeConditions := map[string]bool{
-		"thunderstorm": true,
-		"tornado":      true,
-		"hurricane":    true,
-		"blizzard":     true,
-		"hail":         true,
-	}
-
-	// Check for extreme temperatures
-	if tempC > 40 || tempC < -20 {
-		return true
-	}
-
-	// Check for extreme conditions
-	for extreme := range extremeConditions {
-		if strings.Contains(strings.ToLower(condition), extreme) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // getCategoryFromKeywords determines a category from keywords
 func getCategoryFromKeywords(keywords []string) string {
 	categories := map[string][]string{
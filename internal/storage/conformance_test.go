@@ -0,0 +1,395 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"fish-generate/internal/data"
+)
+
+// closableDB is a backend that can be closed and opened again from what it persisted
+type closableDB interface {
+	DatabaseClient
+	Close(ctx context.Context) error
+}
+
+// storageBackends are the backends every conformance test runs against
+var storageBackends = []struct {
+	name string
+	file string // Name of the file the backend persists to
+	open func(t *testing.T, path string) (closableDB, error)
+}{
+	{"memory", "snapshot.json", func(t *testing.T, path string) (closableDB, error) {
+		return NewMemoryDB(path)
+	}},
+	{"sqlite", "fish.db", func(t *testing.T, path string) (closableDB, error) {
+		if path == "" {
+			path = filepath.Join(t.TempDir(), "x.db")
+		}
+		return NewSQLiteDB(path)
+	}},
+}
+
+// forEachBackend runs a test against every backend. An empty path opens a fresh database.
+func forEachBackend(t *testing.T, test func(t *testing.T, open func(path string) closableDB, file string)) {
+	for _, backend := range storageBackends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			open := func(path string) closableDB {
+				t.Helper()
+				db, err := backend.open(t, path)
+				if err != nil {
+					t.Fatalf("opening %s database: %v", backend.name, err)
+				}
+				t.Cleanup(func() { db.Close(context.Background()) })
+				return db
+			}
+			test(t, open, backend.file)
+		})
+	}
+}
+
+// saveFish saves a fish with the given name, status and generation time
+func saveFish(t *testing.T, db DatabaseClient, name, status string, generatedAt time.Time) *data.FishRecord {
+	t.Helper()
+	fish := &data.FishRecord{
+		Name:        name,
+		Description: "A fish for the storage conformance tests",
+		Rarity:      "Common",
+		Length:      0.5,
+		Weight:      1.2,
+		DataSource:  "test",
+		RegionID:    "pacific",
+		GeneratedAt: generatedAt,
+		Status:      status,
+	}
+	if err := db.SaveFishData(context.Background(), fish); err != nil {
+		t.Fatalf("SaveFishData(%s) error = %v", name, err)
+	}
+	return fish
+}
+
+func TestQueryFishPagesAcrossEqualGenerationTimes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		db := open("")
+		adapter := NewMongoDBAdapter(db)
+
+		tied := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+		var want []string
+		for i := 0; i < 5; i++ {
+			want = append(want, saveFish(t, db, fmt.Sprintf("Tied Tetra %d", i), "", tied).ID)
+		}
+		older := saveFish(t, db, "Older Orfe", "", tied.Add(-time.Hour)).ID
+		newer := saveFish(t, db, "Newer Nase", "", tied.Add(time.Hour)).ID
+		sort.Strings(want)
+
+		for _, descending := range []bool{false, true} {
+			t.Run(fmt.Sprintf("descending=%v", descending), func(t *testing.T) {
+				var got []string
+				query := FishQuery{Descending: descending, Limit: 2}
+				for pages := 0; ; pages++ {
+					if pages > 10 {
+						t.Fatal("QueryFish() never returned the last page")
+					}
+					page, err := adapter.QueryFish(ctx, query)
+					if err != nil {
+						t.Fatalf("QueryFish() error = %v", err)
+					}
+					for _, fish := range page.Fish {
+						got = append(got, fish.ID)
+					}
+					if page.NextCursor == "" {
+						break
+					}
+					query.Cursor = page.NextCursor
+				}
+
+				// Fish with the same generation time are ordered by ID in the requested direction
+				expected := append(append([]string{older}, want...), newer)
+				if descending {
+					for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+						expected[i], expected[j] = expected[j], expected[i]
+					}
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("paged IDs = %v, want %v", got, expected)
+				}
+			})
+		}
+	})
+}
+
+func TestQueryFishFiltersByStatus(t *testing.T) {
+	statuses := []string{data.FishStatusDraft, data.FishStatusQuarantined, data.FishStatusPublished, data.FishStatusRetired}
+
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		db := open("")
+		adapter := NewMongoDBAdapter(db)
+
+		now := time.Now()
+		ids := make(map[string]string)
+		for i, status := range statuses {
+			ids[status] = saveFish(t, db, "Status Shad "+status, status, now.Add(time.Duration(i)*time.Second)).ID
+		}
+
+		for _, status := range statuses {
+			page, err := adapter.QueryFish(ctx, FishQuery{Statuses: []string{status}})
+			if err != nil {
+				t.Fatalf("QueryFish(%s) error = %v", status, err)
+			}
+			if len(page.Fish) != 1 || page.Fish[0].ID != ids[status] || page.Fish[0].Status != status {
+				t.Errorf("QueryFish(%s) = %+v, want only the %s fish", status, page.Fish, status)
+			}
+		}
+
+		page, err := adapter.QueryFish(ctx, FishQuery{})
+		if err != nil {
+			t.Fatalf("QueryFish() error = %v", err)
+		}
+		if len(page.Fish) != 1 || page.Fish[0].ID != ids[data.FishStatusPublished] {
+			t.Errorf("QueryFish() without statuses = %+v, want only the published fish", page.Fish)
+		}
+
+		page, err = adapter.QueryFish(ctx, FishQuery{Statuses: statuses})
+		if err != nil {
+			t.Fatalf("QueryFish(all statuses) error = %v", err)
+		}
+		if len(page.Fish) != len(statuses) {
+			t.Errorf("QueryFish(all statuses) returned %d fish, want %d", len(page.Fish), len(statuses))
+		}
+	})
+}
+
+func TestGetUntranslatedFishIDs(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		db := open("")
+
+		now := time.Now()
+		oldest := saveFish(t, db, "Old Ide", "", now.Add(-3*time.Hour)).ID
+		translated := saveFish(t, db, "Spoken Sprat", "", now.Add(-2*time.Hour)).ID
+		newest := saveFish(t, db, "New Nerka", "", now.Add(-time.Hour)).ID
+		saveFish(t, db, "Draft Dace", data.FishStatusDraft, now)
+
+		for _, translation := range []*data.TranslatedFish{
+			{OriginalID: translated, Name: "Cá Trích Nói", Language: "vi", TranslatedAt: now},
+			{OriginalID: newest, Name: "Nerka Nouveau", Language: "fr", TranslatedAt: now},
+		} {
+			if err := db.SaveTranslatedFish(ctx, translation); err != nil {
+				t.Fatalf("SaveTranslatedFish(%s) error = %v", translation.Language, err)
+			}
+		}
+
+		tests := []struct {
+			language string
+			limit    int
+			want     []string
+		}{
+			{"vi", 10, []string{newest, oldest}},
+			{"vi", 1, []string{newest}},
+			{"vi", 0, []string{newest, oldest}},
+			{"vi", -1, []string{newest, oldest}},
+			{"fr", 0, []string{translated, oldest}},
+			{"de", 2, []string{newest, translated}},
+		}
+		for _, tt := range tests {
+			got, err := db.GetUntranslatedFishIDs(ctx, tt.language, tt.limit)
+			if err != nil {
+				t.Fatalf("GetUntranslatedFishIDs(%s, %d) error = %v", tt.language, tt.limit, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUntranslatedFishIDs(%s, %d) = %v, want %v", tt.language, tt.limit, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestReopenKeepsData(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, file string) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), file)
+		db := open(path)
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		fish := saveFish(t, db, "Lasting Ling", "", now)
+		if err := db.SaveTranslatedFish(ctx, &data.TranslatedFish{OriginalID: fish.ID, Name: "Cá Bền", Language: "vi", TranslatedAt: now}); err != nil {
+			t.Fatalf("SaveTranslatedFish() error = %v", err)
+		}
+		if err := db.SaveLLMCacheEntry(ctx, &data.LLMCacheEntry{
+			Key: "prompt-key", Provider: "scripted", Model: "test", Response: "cached", CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+		}); err != nil {
+			t.Fatalf("SaveLLMCacheEntry() error = %v", err)
+		}
+		if err := db.RecordLLMCacheHit(ctx, "prompt-key", now); err != nil {
+			t.Fatalf("RecordLLMCacheHit() error = %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := db.IncrementAPIQuotaUsage(ctx, "newsapi", "2026-10-15"); err != nil {
+				t.Fatalf("IncrementAPIQuotaUsage() error = %v", err)
+			}
+		}
+		if err := db.SaveFishAuditEntry(ctx, &data.FishAuditEntry{
+			FishID: fish.ID, FishName: fish.Name, Admin: "ops", Action: data.FishAuditRetire, Reason: "duplicate", At: now,
+			Changes: []data.FishFieldChange{{Field: "status", Old: data.FishStatusPublished, New: data.FishStatusRetired}},
+		}); err != nil {
+			t.Fatalf("SaveFishAuditEntry() error = %v", err)
+		}
+		if err := db.Close(ctx); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		// Reopening twice must not apply anything again or lose data
+		for reopen := 1; reopen <= 2; reopen++ {
+			db = open(path)
+
+			stored, err := db.GetFishData(ctx, fish.ID)
+			if err != nil || stored.Name != fish.Name || stored.Status != data.FishStatusPublished || !stored.GeneratedAt.Equal(now) {
+				t.Errorf("reopen %d: GetFishData() = %+v, %v; want the saved fish", reopen, stored, err)
+			}
+			translation, err := db.GetTranslatedFish(ctx, fish.ID, "vi")
+			if err != nil || translation == nil || translation.Name != "Cá Bền" {
+				t.Errorf("reopen %d: GetTranslatedFish() = %+v, %v; want the saved translation", reopen, translation, err)
+			}
+			entry, err := db.GetLLMCacheEntry(ctx, "prompt-key")
+			if err != nil || entry == nil || entry.Response != "cached" || entry.Hits != 1 {
+				t.Errorf("reopen %d: GetLLMCacheEntry() = %+v, %v; want the cached response with one hit", reopen, entry, err)
+			}
+			if calls, err := db.GetAPIQuotaUsage(ctx, "newsapi", "2026-10-15"); err != nil || calls != 3 {
+				t.Errorf("reopen %d: GetAPIQuotaUsage() = %d, %v; want 3", reopen, calls, err)
+			}
+			audit, err := db.GetFishAuditEntries(ctx, fish.ID, 10)
+			if err != nil || len(audit) != 1 || audit[0].Action != data.FishAuditRetire || len(audit[0].Changes) != 1 {
+				t.Errorf("reopen %d: GetFishAuditEntries() = %+v, %v; want the saved entry", reopen, audit, err)
+			}
+
+			if err := db.Close(ctx); err != nil {
+				t.Fatalf("reopen %d: Close() error = %v", reopen, err)
+			}
+		}
+	})
+}
+
+func TestSQLiteMigrationsApplyOnce(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "x.db")
+	db, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	defer db.Close(ctx)
+
+	// Running the migrations again, directly and by reopening, changes nothing
+	if err := db.migrate(ctx); err != nil {
+		t.Fatalf("migrate() again error = %v", err)
+	}
+	reopened, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("reopening NewSQLiteDB() error = %v", err)
+	}
+	reopened.Close(ctx)
+
+	var applied, version int
+	row := db.db.QueryRowContext(ctx, `SELECT COUNT(*), MAX(version) FROM schema_migrations`)
+	if err := row.Scan(&applied, &version); err != nil {
+		t.Fatalf("reading schema_migrations: %v", err)
+	}
+	latest := sqliteMigrations[len(sqliteMigrations)-1].Version
+	if applied != len(sqliteMigrations) || version != latest {
+		t.Errorf("schema_migrations has %d rows up to version %d, want %d up to %d", applied, version, len(sqliteMigrations), latest)
+	}
+}
+
+func TestRecentWeatherReadsBackExtremeWeather(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		adapter := NewMongoDBAdapter(open(""))
+
+		cities := map[string]*data.WeatherInfo{
+			"calm":  {Condition: "Clear", TempC: 21},
+			"storm": {Condition: "Thunderstorm", TempC: 24},
+			"heat":  {Condition: "Clear", TempC: 38},
+		}
+		for cityID, weather := range cities {
+			weather.IsExtreme = data.IsExtremeWeather(weather.Condition, weather.TempC, weather.WindKph)
+			if err := adapter.SaveWeatherData(ctx, weather, "pacific", cityID); err != nil {
+				t.Fatalf("SaveWeatherData(%s) error = %v", cityID, err)
+			}
+		}
+
+		stored, err := adapter.GetRecentWeatherData(ctx, "pacific", 10)
+		if err != nil || len(stored) != len(cities) {
+			t.Fatalf("GetRecentWeatherData() = %d readings, %v; want %d", len(stored), err, len(cities))
+		}
+		for _, weather := range stored {
+			if want := weather.TempC > 35 || weather.Condition == "Thunderstorm"; weather.IsExtreme != want {
+				t.Errorf("stored %s at %v°C has IsExtreme %v, want %v", weather.Condition, weather.TempC, weather.IsExtreme, want)
+			}
+		}
+	})
+}
+
+func TestGetFishByRegionIncludesFishWithoutRegion(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		db := open("")
+
+		now := time.Now()
+		pacific := saveFish(t, db, "Pacific Pollock", "", now).ID
+		atlantic := saveFish(t, db, "Atlantic Alewife", "", now.Add(-time.Minute))
+		legacy := &data.FishRecord{Name: "Legacy Loach", Rarity: "Common", GeneratedAt: now.Add(-time.Hour)}
+		if err := db.SaveFishData(ctx, legacy); err != nil {
+			t.Fatalf("SaveFishData() error = %v", err)
+		}
+		atlantic.RegionID = "north_atlantic"
+		if err := db.UpdateFishData(ctx, atlantic, ""); err != nil {
+			t.Fatalf("UpdateFishData() error = %v", err)
+		}
+
+		fish, err := db.GetFishByRegion(ctx, "pacific", 10)
+		if err != nil {
+			t.Fatalf("GetFishByRegion() error = %v", err)
+		}
+		var got []string
+		for _, f := range fish {
+			got = append(got, f.ID.Hex())
+		}
+		if want := []string{pacific, legacy.ID}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFishByRegion(pacific) = %v, want the pacific fish and the fish without a region %v", got, want)
+		}
+	})
+}
+
+func TestUpdateFishDataChecksStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, open func(string) closableDB, _ string) {
+		ctx := context.Background()
+		db := open("")
+		fish := saveFish(t, db, "Draft Dory", data.FishStatusDraft, time.Now())
+
+		// A second admin approves the fish after the first one read it as a draft
+		approved := *fish
+		approved.Status = data.FishStatusPublished
+		if err := db.UpdateFishData(ctx, &approved, data.FishStatusDraft); err != nil {
+			t.Fatalf("UpdateFishData() of the draft error = %v", err)
+		}
+		fish.Status = data.FishStatusRetired
+		if err := db.UpdateFishData(ctx, fish, data.FishStatusDraft); !errors.Is(err, ErrFishStatusChanged) {
+			t.Errorf("UpdateFishData() of the approved fish = %v, want ErrFishStatusChanged", err)
+		}
+		if got, err := db.GetFishData(ctx, fish.ID); err != nil || got.Status != data.FishStatusPublished {
+			t.Errorf("GetFishData() = %+v, %v; want the fish still published", got, err)
+		}
+
+		missing := &data.FishRecord{ID: "000000000000000000000000", Name: "Missing Minnow", Status: data.FishStatusRetired}
+		if err := db.UpdateFishData(ctx, missing, data.FishStatusDraft); !errors.Is(err, ErrFishNotFound) {
+			t.Errorf("UpdateFishData() of a missing fish = %v, want ErrFishNotFound", err)
+		}
+	})
+}
@@ -2,16 +2,25 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"fish-generate/internal/data"
-	"fish-generate/internal/fish"
 )
 
+// ErrFishNotFound is returned when no fish exists for the requested ID
+var ErrFishNotFound = errors.New("fish not found")
+
+// ErrFishStatusChanged is returned when a fish no longer has the status an update was based on
+var ErrFishStatusChanged = errors.New("fish status changed")
+
 // StorageAdapter defines the main interface for storage operations
 type StorageAdapter interface {
 	// Weather data operations
 	SaveWeatherData(ctx context.Context, weatherInfo *data.WeatherInfo, regionID, cityID string) error
 	GetRecentWeatherData(ctx context.Context, regionID string, limit int) ([]*data.WeatherInfo, error)
+	SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error
+	GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error)
 
 	// Price data operations
 	SavePriceData(ctx context.Context, assetType string, price, volume, changePercent, volumeChange float64, source string) error
@@ -22,12 +31,19 @@ type StorageAdapter interface {
 	GetRecentNewsData(ctx context.Context, limit int) ([]*data.NewsItem, error)
 
 	// Fish data operations
-	SaveFishData(ctx context.Context, fishData interface{}) error
+	SaveFishData(ctx context.Context, fish *data.FishRecord) error
+	UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error
 	GetDailyFishCount(ctx context.Context) (int, error)
-	GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*fish.Fish, error)
-	GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*fish.Fish, error)
-	GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*fish.Fish, error)
-	GetFishByID(ctx context.Context, id string) (map[string]interface{}, error)
+	GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*data.FishRecord, error)
+	GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*data.FishRecord, error)
+	GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*data.FishRecord, error)
+	GetFishRecord(ctx context.Context, id string) (*data.FishRecord, error)
+	QueryFish(ctx context.Context, query FishQuery) (*FishPage, error)
+	GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error)
+
+	// Audit trail of admin actions on fish
+	SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error
+	GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error)
 
 	// Persistence operations for news and generation queue
 	SaveUsedNewsIDs(ctx context.Context, usedIDs map[string]bool) error
@@ -37,8 +53,25 @@ type StorageAdapter interface {
 
 	// Translation operations
 	SaveTranslatedFish(ctx context.Context, translatedFish *data.TranslatedFish) error
-	GetTranslatedFish(ctx context.Context, originalID string) (*data.TranslatedFish, error)
-	GetUntranslatedFishIDs(ctx context.Context, limit int) ([]string, error)
-	GetUntranslatedFish(ctx context.Context, limit int) ([]map[string]interface{}, error)
-	UpdateFishWithTranslation(ctx context.Context, fishID interface{}, translatedFish map[string]interface{}) error
+	GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error)
+	GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error)
+
+	// LLM response cache operations
+	GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error)
+	SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error
+	RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error
+	DeleteLLMCacheEntry(ctx context.Context, key string) error
+	PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error)
+
+	// LLM usage accounting operations
+	SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error
+	GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error)
+
+	// Prompt experiment operations
+	IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error
+	GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error)
+
+	// API quota operations
+	IncrementAPIQuotaUsage(ctx context.Context, source, window string) error
+	GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error)
 }
@@ -0,0 +1,1108 @@
+package storage
+
+import (
+	"context"
+	"fish-generate/internal/data"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryDB implements DatabaseClient entirely in memory.
+// It is used for local development and tests when MongoDB is not configured,
+// and can optionally snapshot its state to a JSON file so restarts keep data.
+type MemoryDB struct {
+	mu           sync.RWMutex
+	snapshotPath string
+
+	weather       []*WeatherData
+	regionWeather map[string]*data.RegionalWeather // Keyed by region ID
+	prices        []*PriceData
+	news          []*NewsData
+	fish          []bson.M // Stored as documents so translation fields can be attached
+	usedNews      map[string]time.Time
+	queue         []QueuedGenerationRecord
+	translated    []*TranslatedFishData
+	dailyCounts   map[string]int
+	llmCache      map[string]*data.LLMCacheEntry
+	llmUsage      []*data.LLMUsageRecord
+	experiments   map[string]*data.ExperimentVariantCounts // Keyed by experimentCounterKey
+	fishAudit     []*data.FishAuditEntry
+	apiQuota      map[string]*data.APIQuotaUsage // Keyed by apiQuotaKey
+}
+
+// memorySnapshot is the on-disk representation of a MemoryDB.
+// It is encoded as MongoDB Extended JSON so ObjectIDs and dates survive round-trips.
+type memorySnapshot struct {
+	Weather       []*WeatherData                  `bson:"weather"`
+	RegionWeather []*data.RegionalWeather         `bson:"region_weather"`
+	Prices        []*PriceData                    `bson:"prices"`
+	News          []*NewsData                     `bson:"news"`
+	Fish          []bson.M                        `bson:"fish"`
+	UsedNews      []UsedNewsRecord                `bson:"used_news"`
+	Queue         []QueuedGenerationRecord        `bson:"generation_queue"`
+	Translated    []*TranslatedFishData           `bson:"translated_fish"`
+	DailyCounts   []FishLimitRecord               `bson:"daily_counts"`
+	LLMCache      []*data.LLMCacheEntry           `bson:"llm_cache"`
+	LLMUsage      []*data.LLMUsageRecord          `bson:"llm_usage"`
+	Experiments   []*data.ExperimentVariantCounts `bson:"experiment_counters"`
+	FishAudit     []*data.FishAuditEntry          `bson:"fish_audit"`
+	APIQuota      []*data.APIQuotaUsage           `bson:"api_quota"`
+	SavedAt       time.Time                       `bson:"saved_at"`
+}
+
+// NewMemoryDB creates a new in-memory database.
+// If snapshotPath is not empty, existing state is loaded from it and every change is written back.
+func NewMemoryDB(snapshotPath string) (*MemoryDB, error) {
+	m := &MemoryDB{
+		snapshotPath:  snapshotPath,
+		regionWeather: make(map[string]*data.RegionalWeather),
+		usedNews:      make(map[string]time.Time),
+		dailyCounts:   make(map[string]int),
+		llmCache:      make(map[string]*data.LLMCacheEntry),
+		experiments:   make(map[string]*data.ExperimentVariantCounts),
+		apiQuota:      make(map[string]*data.APIQuotaUsage),
+	}
+
+	if snapshotPath != "" {
+		if err := m.loadSnapshot(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Close writes a final snapshot if snapshotting is enabled
+func (m *MemoryDB) Close(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveSnapshot()
+}
+
+// loadSnapshot restores state from the snapshot file if it exists
+func (m *MemoryDB) loadSnapshot() error {
+	raw, err := os.ReadFile(m.snapshotPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("No memory snapshot found at %s, starting empty", m.snapshotPath)
+			return nil
+		}
+		return fmt.Errorf("failed to read memory snapshot: %v", err)
+	}
+
+	var snapshot memorySnapshot
+	if err := bson.UnmarshalExtJSON(raw, true, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode memory snapshot: %v", err)
+	}
+
+	m.weather = snapshot.Weather
+	m.prices = snapshot.Prices
+	m.news = snapshot.News
+	m.queue = snapshot.Queue
+	m.translated = snapshot.Translated
+	m.llmUsage = snapshot.LLMUsage
+	m.fishAudit = snapshot.FishAudit
+
+	m.fish = make([]bson.M, 0, len(snapshot.Fish))
+	for _, doc := range snapshot.Fish {
+		doc = normalizeDocument(doc)
+		if _, ok := doc["status"]; !ok {
+			doc["status"] = data.FishStatusPublished
+		}
+		m.fish = append(m.fish, doc)
+	}
+	for _, weather := range snapshot.RegionWeather {
+		m.regionWeather[weather.RegionID] = weather
+	}
+	for _, record := range snapshot.UsedNews {
+		m.usedNews[record.NewsID] = record.UsedAt
+	}
+	for _, record := range snapshot.DailyCounts {
+		m.dailyCounts[record.Date] = record.Count
+	}
+	for _, entry := range snapshot.LLMCache {
+		m.llmCache[entry.Key] = entry
+	}
+	for _, counts := range snapshot.Experiments {
+		m.experiments[experimentCounterKey(counts.Experiment, counts.Variant)] = counts
+	}
+	for _, usage := range snapshot.APIQuota {
+		m.apiQuota[apiQuotaKey(usage.Source, usage.Window)] = usage
+	}
+
+	log.Printf("Loaded memory snapshot from %s (%d fish, %d news, %d weather records)",
+		m.snapshotPath, len(m.fish), len(m.news), len(m.weather))
+	return nil
+}
+
+// saveSnapshot writes the current state to the snapshot file. Callers must hold the lock.
+func (m *MemoryDB) saveSnapshot() error {
+	if m.snapshotPath == "" {
+		return nil
+	}
+
+	snapshot := memorySnapshot{
+		Weather:    m.weather,
+		Prices:     m.prices,
+		News:       m.news,
+		Fish:       m.fish,
+		Queue:      m.queue,
+		Translated: m.translated,
+		LLMUsage:   m.llmUsage,
+		FishAudit:  m.fishAudit,
+		SavedAt:    time.Now(),
+	}
+	for _, weather := range m.regionWeather {
+		snapshot.RegionWeather = append(snapshot.RegionWeather, weather)
+	}
+	for newsID, usedAt := range m.usedNews {
+		snapshot.UsedNews = append(snapshot.UsedNews, UsedNewsRecord{
+			NewsID:     newsID,
+			UsedAt:     usedAt,
+			RecordType: "used_news",
+		})
+	}
+	for date, count := range m.dailyCounts {
+		snapshot.DailyCounts = append(snapshot.DailyCounts, FishLimitRecord{
+			Date:       date,
+			Count:      count,
+			RecordType: "daily_fish_count",
+		})
+	}
+	for _, entry := range m.llmCache {
+		snapshot.LLMCache = append(snapshot.LLMCache, entry)
+	}
+	for _, counts := range m.experiments {
+		snapshot.Experiments = append(snapshot.Experiments, counts)
+	}
+	for _, usage := range m.apiQuota {
+		snapshot.APIQuota = append(snapshot.APIQuota, usage)
+	}
+
+	raw, err := bson.MarshalExtJSON(snapshot, true, false)
+	if err != nil {
+		return fmt.Errorf("failed to encode memory snapshot: %v", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated snapshot
+	if dir := filepath.Dir(m.snapshotPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %v", err)
+		}
+	}
+	tmpPath := m.snapshotPath + ".tmp"
+	if err := os.WriteFile(tmpPath, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write memory snapshot: %v", err)
+	}
+	if err := os.Rename(tmpPath, m.snapshotPath); err != nil {
+		return fmt.Errorf("failed to replace memory snapshot: %v", err)
+	}
+
+	return nil
+}
+
+// persist saves a snapshot after a mutation, logging rather than failing the write.
+// Callers must hold the lock.
+func (m *MemoryDB) persist() {
+	if err := m.saveSnapshot(); err != nil {
+		log.Printf("Warning: failed to save memory snapshot: %v", err)
+	}
+}
+
+// SaveWeatherData saves weather data, replacing the previous reading for the same region and city
+func (m *MemoryDB) SaveWeatherData(ctx context.Context, weatherInfo *data.WeatherInfo, regionID, cityID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wi := &WeatherData{
+		ID:        primitive.NewObjectID(),
+		RegionID:  regionID,
+		CityID:    cityID,
+		Condition: weatherInfo.Condition,
+		TempC:     weatherInfo.TempC,
+		Timestamp: time.Now(),
+		Source:    "internal", // Default source
+	}
+
+	for i, existing := range m.weather {
+		if existing.RegionID == regionID && existing.CityID == cityID {
+			wi.ID = existing.ID
+			m.weather[i] = wi
+			m.persist()
+			return nil
+		}
+	}
+
+	m.weather = append(m.weather, wi)
+	m.persist()
+	return nil
+}
+
+// SavePriceData saves or updates price data for an asset type
+func (m *MemoryDB) SavePriceData(ctx context.Context, assetType string, price, volume, changePercent, volumeChange float64, source string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	priceData := &PriceData{
+		ID:            primitive.NewObjectID(),
+		AssetType:     assetType,
+		Price:         price,
+		Volume:        volume,
+		ChangePercent: changePercent,
+		VolumeChange:  volumeChange,
+		Timestamp:     time.Now(),
+		Source:        source,
+	}
+
+	for i, existing := range m.prices {
+		if existing.AssetType == assetType {
+			priceData.ID = existing.ID
+			m.prices[i] = priceData
+			m.persist()
+			return nil
+		}
+	}
+
+	m.prices = append(m.prices, priceData)
+	m.persist()
+	return nil
+}
+
+// SaveNewsData saves news data, replacing any item with the same source and headline
+func (m *MemoryDB) SaveNewsData(ctx context.Context, newsItem *data.NewsItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	newsData := &NewsData{
+		ID:          primitive.NewObjectID(),
+		Headline:    newsItem.Headline,
+		Content:     newsItem.GetContent(),
+		Source:      newsItem.Source,
+		URL:         newsItem.URL,
+		PublishedAt: newsItem.PublishedAt,
+		Sentiment:   newsItem.Sentiment,
+		Keywords:    newsItem.Keywords,
+		Timestamp:   time.Now(),
+	}
+
+	for i, existing := range m.news {
+		if existing.Source == newsData.Source && existing.Headline == newsData.Headline {
+			newsData.ID = existing.ID
+			m.news[i] = newsData
+			m.persist()
+			return nil
+		}
+	}
+
+	m.news = append(m.news, newsData)
+	m.persist()
+	return nil
+}
+
+// SaveFishData saves a fish record and sets its ID
+func (m *MemoryDB) SaveFishData(ctx context.Context, fish *data.FishRecord) error {
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return err
+	}
+
+	doc, err := fishDataToDocument(fishData)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fish = append(m.fish, doc)
+	m.dailyCounts[time.Now().Format("2006-01-02")]++
+	m.persist()
+
+	fish.ID = fishData.ID.Hex()
+	fish.GeneratedAt = fishData.GeneratedAt
+	fish.Status = fishData.Status
+	log.Printf("Fish data saved: %s (ID: %s)", fishData.Name, fish.ID)
+	return nil
+}
+
+// UpdateFishData replaces the stored fields of an existing fish record, keeping
+// fields only the document has, like translations. When status is set, the stored fish
+// must still have it, or ErrFishStatusChanged is returned.
+func (m *MemoryDB) UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error {
+	if fish == nil || fish.ID == "" {
+		return fmt.Errorf("fish to update has no ID")
+	}
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFishNotFound, err)
+	}
+
+	update, err := fishDataToDocument(fishData)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	doc := m.findFishDocument(fishData.ID)
+	if doc == nil {
+		return fmt.Errorf("%w: %s", ErrFishNotFound, fish.ID)
+	}
+	if current, _ := doc["status"].(string); status != "" && current != status {
+		return fmt.Errorf("%w: %s is %s, not %s", ErrFishStatusChanged, fish.ID, current, status)
+	}
+	for _, field := range fishRecordFields {
+		delete(doc, field)
+	}
+	for key, value := range update {
+		doc[key] = value
+	}
+	m.persist()
+
+	fish.Status = fishData.Status
+	return nil
+}
+
+// GetRecentWeatherData retrieves recent weather data for a specific region
+func (m *MemoryDB) GetRecentWeatherData(ctx context.Context, regionID string, limit int) ([]*WeatherData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*WeatherData
+	for _, item := range m.weather {
+		if regionID == "" || item.RegionID == regionID {
+			copied := *item
+			results = append(results, &copied)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	return limitSlice(results, limit), nil
+}
+
+// SaveRegionWeather replaces the aggregated weather of a region
+func (m *MemoryDB) SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error {
+	stored := *weather
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.regionWeather[weather.RegionID] = &stored
+	m.persist()
+	return nil
+}
+
+// GetRegionWeather returns the aggregated weather of a region, or nil if none was saved
+func (m *MemoryDB) GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	weather, ok := m.regionWeather[regionID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *weather
+	return &copied, nil
+}
+
+// GetRecentPriceData retrieves recent price data for a specific asset type
+func (m *MemoryDB) GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matches []*PriceData
+	for _, item := range m.prices {
+		if assetType == "" || item.AssetType == assetType {
+			matches = append(matches, item)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Timestamp.After(matches[j].Timestamp)
+	})
+	matches = limitSlice(matches, limit)
+
+	// Return the same keys MongoDB would produce for a decoded price document
+	results := make([]map[string]interface{}, len(matches))
+	for i, item := range matches {
+		results[i] = map[string]interface{}{
+			"_id":            item.ID,
+			"asset_type":     item.AssetType,
+			"price":          item.Price,
+			"volume":         item.Volume,
+			"change_percent": item.ChangePercent,
+			"volume_change":  item.VolumeChange,
+			"timestamp":      item.Timestamp,
+			"source":         item.Source,
+		}
+	}
+
+	return results, nil
+}
+
+// GetRecentNewsData retrieves recent news data
+func (m *MemoryDB) GetRecentNewsData(ctx context.Context, limit int) ([]*NewsData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := make([]*NewsData, 0, len(m.news))
+	for _, item := range m.news {
+		copied := *item
+		results = append(results, &copied)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	return limitSlice(results, limit), nil
+}
+
+// GetSimilarFish retrieves the newest fish matching the data source and rarity
+func (m *MemoryDB) GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*FishData, error) {
+	if dataSource == "" && rarityLevel == "" {
+		return nil, fmt.Errorf("at least one filter parameter (dataSource or rarityLevel) must be provided")
+	}
+
+	results, err := m.findFish(func(f *FishData) bool {
+		return f.Status == data.FishStatusPublished &&
+			(dataSource == "" || f.DataSource == dataSource) &&
+			(rarityLevel == "" || f.Rarity == rarityLevel)
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("failed to find similar fish: no matching fish")
+	}
+
+	return results[0], nil
+}
+
+// GetFishByRegion retrieves fish for a specific region, including fish saved without a region
+func (m *MemoryDB) GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*FishData, error) {
+	// Default limit if not specified
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return m.findFish(func(f *FishData) bool {
+		return f.Status == data.FishStatusPublished && (regionID == "" || f.RegionID == regionID || f.RegionID == "")
+	}, limit)
+}
+
+// GetFishByDataSource retrieves fish from a specific data source
+func (m *MemoryDB) GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*FishData, error) {
+	return m.findFish(func(f *FishData) bool {
+		return f.Status == data.FishStatusPublished && f.DataSource == dataSource
+	}, limit)
+}
+
+// QueryFish returns fish matching the query in sort order, starting after the query cursor.
+// Up to query.Limit+1 fish are returned so the caller can tell whether another page exists.
+func (m *MemoryDB) QueryFish(ctx context.Context, query FishQuery) ([]*FishData, error) {
+	cursor, err := query.after()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := m.findFish(query.matches, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// less orders fish by sort key, then ID, in the requested direction
+	less := func(a *FishData, aKey float64, b *FishData, bKey float64) bool {
+		aID, bID := a.ID.Hex(), b.ID.Hex()
+		if query.Descending {
+			aKey, bKey, aID, bID = bKey, aKey, bID, aID
+		}
+		if aKey != bKey {
+			return aKey < bKey
+		}
+		return aID < bID
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return less(matches[i], fishSortKey(matches[i], query.SortBy), matches[j], fishSortKey(matches[j], query.SortBy))
+	})
+
+	var last *FishData
+	if cursor != nil {
+		lastID, err := primitive.ObjectIDFromHex(cursor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %v", err)
+		}
+		last = &FishData{ID: lastID}
+	}
+
+	results := make([]*FishData, 0, query.Limit+1)
+	for _, fish := range matches {
+		// Skip everything up to and including the last fish of the previous page
+		if last != nil && !less(last, cursor.Key, fish, fishSortKey(fish, query.SortBy)) {
+			continue
+		}
+		results = append(results, fish)
+		if len(results) > query.Limit {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// findFish returns decoded fish matching the predicate, newest first
+func (m *MemoryDB) findFish(match func(*FishData) bool, limit int) ([]*FishData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*FishData
+	for _, doc := range m.fish {
+		fishData, err := documentToFishData(doc)
+		if err != nil {
+			return nil, err
+		}
+		if match(fishData) {
+			results = append(results, fishData)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].GeneratedAt.After(results[j].GeneratedAt)
+	})
+	return limitSlice(results, limit), nil
+}
+
+// GetSpeciesSummaries returns the ID, name and description of every stored fish
+func (m *MemoryDB) GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error) {
+	fish, err := m.findFish(func(*FishData) bool { return true }, 0)
+	if err != nil {
+		return nil, err
+	}
+	return speciesSummaries(fish), nil
+}
+
+// GetDailyFishCount returns the number of fish generated today
+func (m *MemoryDB) GetDailyFishCount(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.dailyCounts[time.Now().Format("2006-01-02")], nil
+}
+
+// SaveUsedNewsIDs marks the given news IDs as used
+func (m *MemoryDB) SaveUsedNewsIDs(ctx context.Context, usedIDs map[string]bool) error {
+	if len(usedIDs) == 0 {
+		return nil // Nothing to save
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for newsID := range usedIDs {
+		m.usedNews[newsID] = now
+	}
+	m.persist()
+	return nil
+}
+
+// GetUsedNewsIDs retrieves all used news IDs
+func (m *MemoryDB) GetUsedNewsIDs(ctx context.Context) (map[string]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]bool, len(m.usedNews))
+	for newsID := range m.usedNews {
+		result[newsID] = true
+	}
+	return result, nil
+}
+
+// SaveGenerationQueue replaces the pending generation queue
+func (m *MemoryDB) SaveGenerationQueue(ctx context.Context, queue []data.GenerationRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queue = make([]QueuedGenerationRecord, 0, len(queue))
+	for _, request := range queue {
+		m.queue = append(m.queue, QueuedGenerationRecord{
+			ID:         primitive.NewObjectID(),
+			Reason:     request.Reason,
+			AddedAt:    request.AddedAt,
+			Status:     "pending",
+			RecordType: "generation_request",
+		})
+	}
+	m.persist()
+	return nil
+}
+
+// GetGenerationQueue retrieves the pending generation requests, oldest first
+func (m *MemoryDB) GetGenerationQueue(ctx context.Context) ([]data.GenerationRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]QueuedGenerationRecord, len(m.queue))
+	copy(records, m.queue)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].AddedAt.Before(records[j].AddedAt)
+	})
+
+	var result []data.GenerationRequest
+	for _, record := range records {
+		result = append(result, data.GenerationRequest{
+			ID:      record.ID.Hex(),
+			Reason:  record.Reason,
+			AddedAt: record.AddedAt,
+		})
+	}
+	return result, nil
+}
+
+// SaveTranslatedFish saves the translated fish, replacing any earlier translation
+func (m *MemoryDB) SaveTranslatedFish(ctx context.Context, translatedFish *data.TranslatedFish) error {
+	if translatedFish.Language == "" {
+		return fmt.Errorf("translation language is required")
+	}
+
+	originalID, err := primitive.ObjectIDFromHex(translatedFish.OriginalID)
+	if err != nil {
+		return fmt.Errorf("invalid original fish ID: %v", err)
+	}
+
+	doc := &TranslatedFishData{
+		ID:              primitive.NewObjectID(),
+		OriginalID:      originalID,
+		Name:            translatedFish.Name,
+		Description:     translatedFish.Description,
+		Appearance:      translatedFish.Appearance,
+		Color:           translatedFish.Color,
+		Diet:            translatedFish.Diet,
+		Habitat:         translatedFish.Habitat,
+		Effect:          translatedFish.Effect,
+		FavoriteWeather: translatedFish.FavoriteWeather,
+		ExistenceReason: translatedFish.ExistenceReason,
+		TranslatedAt:    translatedFish.TranslatedAt,
+		Language:        translatedFish.Language,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.translated {
+		if existing.OriginalID == originalID && existing.Language == doc.Language {
+			doc.ID = existing.ID
+			m.translated[i] = doc
+			m.persist()
+			return nil
+		}
+	}
+
+	m.translated = append(m.translated, doc)
+	m.persist()
+	return nil
+}
+
+// GetTranslatedFish retrieves the translation of a fish into the given language
+func (m *MemoryDB) GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error) {
+	objID, err := primitive.ObjectIDFromHex(originalID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid original fish ID: %v", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, result := range m.translated {
+		if result.OriginalID == objID && result.Language == language {
+			return &data.TranslatedFish{
+				OriginalID:      originalID,
+				Name:            result.Name,
+				Description:     result.Description,
+				Appearance:      result.Appearance,
+				Color:           result.Color,
+				Diet:            result.Diet,
+				Habitat:         result.Habitat,
+				Effect:          result.Effect,
+				FavoriteWeather: result.FavoriteWeather,
+				ExistenceReason: result.ExistenceReason,
+				Language:        result.Language,
+				TranslatedAt:    result.TranslatedAt,
+			}, nil
+		}
+	}
+
+	return nil, nil // No translation found
+}
+
+// GetUntranslatedFishIDs retrieves IDs of published fish that have no translation in the given language, newest first.
+// A limit of zero or less returns every such fish.
+func (m *MemoryDB) GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	translatedIDs := make(map[primitive.ObjectID]bool, len(m.translated))
+	for _, t := range m.translated {
+		if t.Language == language {
+			translatedIDs[t.OriginalID] = true
+		}
+	}
+
+	untranslatedIDs := []string{}
+	for _, doc := range m.sortedFishDocuments() {
+		id, _ := doc["_id"].(primitive.ObjectID)
+		if status, _ := doc["status"].(string); status != data.FishStatusPublished {
+			continue
+		}
+		if !translatedIDs[id] {
+			untranslatedIDs = append(untranslatedIDs, id.Hex())
+			if limit > 0 && len(untranslatedIDs) >= limit {
+				break
+			}
+		}
+	}
+
+	return untranslatedIDs, nil
+}
+
+// GetFishData retrieves a fish by its ID
+func (m *MemoryDB) GetFishData(ctx context.Context, id string) (*FishData, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ID %s", ErrFishNotFound, id)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	doc := m.findFishDocument(objID)
+	if doc == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFishNotFound, id)
+	}
+
+	return documentToFishData(doc)
+}
+
+// GetLLMCacheEntry retrieves a cached LLM response by key
+func (m *MemoryDB) GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.llmCache[key]
+	if !ok {
+		return nil, nil // Not cached
+	}
+	result := *entry
+	return &result, nil
+}
+
+// SaveLLMCacheEntry inserts or replaces a cached LLM response
+func (m *MemoryDB) SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error {
+	stored := *entry
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.llmCache[entry.Key] = &stored
+	m.persist()
+	return nil
+}
+
+// RecordLLMCacheHit increments the hit count of a cached LLM response
+func (m *MemoryDB) RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.llmCache[key]; ok {
+		entry.Hits++
+		entry.LastHitAt = at
+		m.persist()
+	}
+	return nil
+}
+
+// DeleteLLMCacheEntry removes a cached LLM response
+func (m *MemoryDB) DeleteLLMCacheEntry(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.llmCache[key]; ok {
+		delete(m.llmCache, key)
+		m.persist()
+	}
+	return nil
+}
+
+// PruneLLMCache removes expired LLM responses, then the oldest ones beyond maxEntries
+func (m *MemoryDB) PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	entries := make([]*data.LLMCacheEntry, 0, len(m.llmCache))
+	for key, entry := range m.llmCache {
+		if !now.Before(entry.ExpiresAt) {
+			delete(m.llmCache, key)
+			removed++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if maxEntries > 0 && len(entries) > maxEntries {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		})
+		for _, entry := range entries[maxEntries:] {
+			delete(m.llmCache, entry.Key)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		m.persist()
+	}
+	return removed, nil
+}
+
+// SaveLLMUsage records a single LLM call
+func (m *MemoryDB) SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error {
+	stored := *record
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.llmUsage = append(m.llmUsage, &stored)
+	m.persist()
+	return nil
+}
+
+// GetLLMUsageTotals aggregates LLM calls made in [from, to) per UTC day, task and model
+func (m *MemoryDB) GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var records []*data.LLMUsageRecord
+	for _, record := range m.llmUsage {
+		if !record.Timestamp.Before(from) && record.Timestamp.Before(to) {
+			records = append(records, record)
+		}
+	}
+	return data.AggregateLLMUsage(records), nil
+}
+
+// IncrementExperimentCounter adds one to a counter of an experiment variant
+func (m *MemoryDB) IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := experimentCounterKey(experiment, variant)
+	counts, ok := m.experiments[key]
+	if !ok {
+		counts = &data.ExperimentVariantCounts{Experiment: experiment, Variant: variant}
+	}
+	if err := counts.Increment(counter); err != nil {
+		return err
+	}
+	m.experiments[key] = counts
+	m.persist()
+	return nil
+}
+
+// GetExperimentCounts returns the counters of every variant of an experiment, sorted by variant
+func (m *MemoryDB) GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make([]data.ExperimentVariantCounts, 0)
+	for _, variant := range m.experiments {
+		if variant.Experiment == experiment {
+			counts = append(counts, *variant)
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Variant < counts[j].Variant
+	})
+	return counts, nil
+}
+
+// IncrementAPIQuotaUsage adds one call to a source in a quota window
+func (m *MemoryDB) IncrementAPIQuotaUsage(ctx context.Context, source, window string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := apiQuotaKey(source, window)
+	usage, ok := m.apiQuota[key]
+	if !ok {
+		usage = &data.APIQuotaUsage{Source: source, Window: window}
+		m.apiQuota[key] = usage
+	}
+	usage.Calls++
+	m.persist()
+	return nil
+}
+
+// GetAPIQuotaUsage returns the calls made to a source in a quota window
+func (m *MemoryDB) GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if usage, ok := m.apiQuota[apiQuotaKey(source, window)]; ok {
+		return usage.Calls, nil
+	}
+	return 0, nil
+}
+
+// SaveFishAuditEntry records an admin action on a fish
+func (m *MemoryDB) SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error {
+	stored := *entry
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fishAudit = append(m.fishAudit, &stored)
+	m.persist()
+	return nil
+}
+
+// GetFishAuditEntries returns the audit trail of a fish, or of every fish when fishID is empty, newest first
+func (m *MemoryDB) GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]*data.FishAuditEntry, 0)
+	for i := len(m.fishAudit) - 1; i >= 0; i-- {
+		if fishID == "" || m.fishAudit[i].FishID == fishID {
+			copied := *m.fishAudit[i]
+			entries = append(entries, &copied)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.After(entries[j].At)
+	})
+	return limitSlice(entries, limit), nil
+}
+
+// experimentCounterKey identifies the counters of an experiment variant
+func experimentCounterKey(experiment, variant string) string {
+	return experiment + "\x00" + variant
+}
+
+// apiQuotaKey returns the map key of a source's calls in a quota window
+func apiQuotaKey(source, window string) string {
+	return source + "\x00" + window
+}
+
+// findFishDocument returns the stored document for an ID. Callers must hold the lock.
+func (m *MemoryDB) findFishDocument(id primitive.ObjectID) bson.M {
+	for _, doc := range m.fish {
+		if docID, ok := doc["_id"].(primitive.ObjectID); ok && docID == id {
+			return doc
+		}
+	}
+	return nil
+}
+
+// sortedFishDocuments returns fish documents ordered newest first. Callers must hold the lock.
+func (m *MemoryDB) sortedFishDocuments() []bson.M {
+	docs := make([]bson.M, len(m.fish))
+	copy(docs, m.fish)
+	sort.SliceStable(docs, func(i, j int) bool {
+		ti, _ := docs[i]["generated_at"].(time.Time)
+		tj, _ := docs[j]["generated_at"].(time.Time)
+		return ti.After(tj)
+	})
+	return docs
+}
+
+// fishDataToDocument encodes fish data the same way MongoDB would store it
+func fishDataToDocument(fishData *FishData) (bson.M, error) {
+	raw, err := bson.Marshal(fishData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fish data: %v", err)
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode fish document: %v", err)
+	}
+
+	return normalizeDocument(doc), nil
+}
+
+// documentToFishData decodes a stored fish document into FishData
+func documentToFishData(doc bson.M) (*FishData, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fish document: %v", err)
+	}
+
+	var fishData FishData
+	if err := bson.Unmarshal(raw, &fishData); err != nil {
+		return nil, fmt.Errorf("failed to decode fish data: %v", err)
+	}
+
+	return &fishData, nil
+}
+
+// normalizeDocument converts BSON container types into plain maps and slices,
+// and BSON datetimes into time.Time, so callers can use ordinary type assertions
+func normalizeDocument(doc bson.M) bson.M {
+	result := make(bson.M, len(doc))
+	for key, value := range doc {
+		result[key] = normalizeValue(value)
+	}
+	return result
+}
+
+// normalizeValue converts a single BSON value for normalizeDocument
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.M:
+		return map[string]interface{}(normalizeDocument(v))
+	case map[string]interface{}:
+		return map[string]interface{}(normalizeDocument(v))
+	case primitive.D:
+		doc := make(bson.M, len(v))
+		for _, elem := range v {
+			doc[elem.Key] = elem.Value
+		}
+		return map[string]interface{}(normalizeDocument(doc))
+	case primitive.A:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeValue(item)
+		}
+		return items
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeValue(item)
+		}
+		return items
+	case primitive.DateTime:
+		return v.Time()
+	default:
+		return v
+	}
+}
+
+// limitSlice truncates results to limit when limit is positive
+func limitSlice[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
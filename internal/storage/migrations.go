@@ -0,0 +1,552 @@
+package storage
+
+import (
+	"context"
+	"fish-generate/internal/data"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMigration is a single versioned, idempotent change to the MongoDB schema or data.
+// Migrations must be safe to re-run: a crash between Up and recording the version
+// means the migration will run again on the next start.
+type MongoMigration struct {
+	Version     int
+	Name        string
+	Description string
+
+	// Up applies the migration
+	Up func(ctx context.Context, m *MongoDB) error
+
+	// Preview optionally describes what Up would change, for dry runs
+	Preview func(ctx context.Context, m *MongoDB) (string, error)
+}
+
+// MigrationRecord is the document stored for each applied migration
+type MigrationRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Version    int                `bson:"version"`
+	Name       string             `bson:"name"`
+	AppliedAt  time.Time          `bson:"applied_at"`
+	DurationMS int64              `bson:"duration_ms"`
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Version     int
+	Name        string
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// mongoMigrations lists all migrations in order. Never renumber or edit an
+// applied migration; append a new one instead.
+var mongoMigrations = []MongoMigration{
+	{
+		Version:     1,
+		Name:        "create_collections",
+		Description: "Create all collections used by the service",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			return m.initializeCollections(ctx)
+		},
+	},
+	{
+		Version:     2,
+		Name:        "create_indexes",
+		Description: "Create indexes for every collection, including collections created before indexes were managed",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			for _, collName := range requiredCollections {
+				if err := m.createIndexesForCollection(ctx, collName); err != nil {
+					return fmt.Errorf("failed to create indexes for '%s': %v", collName, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     3,
+		Name:        "unset_blank_fish_region_id",
+		Description: "Remove blank region_id values, so fish saved before region_id was recorded have no region rather than an empty one",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			// The region of these fish is unknown; catches treat them as belonging to every region
+			result, err := m.collection(fishCollection).UpdateMany(ctx, blankRegionFilter,
+				bson.M{"$unset": bson.M{"region_id": ""}})
+			if err != nil {
+				return fmt.Errorf("failed to unset blank region_id: %v", err)
+			}
+			log.Printf("Unset blank region_id on %d fish", result.ModifiedCount)
+			return nil
+		},
+		Preview: func(ctx context.Context, m *MongoDB) (string, error) {
+			count, err := m.collection(fishCollection).CountDocuments(ctx, blankRegionFilter)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d fish with a blank region_id", count), nil
+		},
+	},
+	{
+		Version:     4,
+		Name:        "normalize_stat_effects",
+		Description: "Convert legacy stat_effects (single documents and stat/value entries) to the effect_type/modifier list",
+		Up:          normalizeStatEffects,
+		Preview: func(ctx context.Context, m *MongoDB) (string, error) {
+			count, err := m.collection(fishCollection).CountDocuments(ctx, legacyStatEffectsFilter)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d fish with legacy stat_effects", count), nil
+		},
+	},
+	{
+		Version:     5,
+		Name:        "drop_unused_state_collection",
+		Description: "Drop the empty 'state' collection created by older mongo-init.js scripts",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			coll := m.collection("state")
+			count, err := coll.CountDocuments(ctx, bson.M{})
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				log.Printf("Keeping 'state' collection: it contains %d documents", count)
+				return nil
+			}
+			return coll.Drop(ctx)
+		},
+	},
+	{
+		Version:     6,
+		Name:        "backfill_fish_value_and_effect",
+		Description: "Store value and effect on fish saved before they were part of the fish record",
+		Up:          backfillFishValueAndEffect,
+		Preview: func(ctx context.Context, m *MongoDB) (string, error) {
+			count, err := m.collection(fishCollection).CountDocuments(ctx, missingValueFilter)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d fish without value", count), nil
+		},
+	},
+	{
+		Version:     7,
+		Name:        "fish_catalog_indexes",
+		Description: "Store rarity_rank on every fish and create the catalog query indexes",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			coll := m.collection(fishCollection)
+			for _, rarity := range []string{"Common", "Uncommon", "Rare", "Epic", "Legendary"} {
+				_, err := coll.UpdateMany(ctx, bson.M{"rarity": rarity},
+					bson.M{"$set": bson.M{"rarity_rank": data.RarityRank(rarity)}})
+				if err != nil {
+					return fmt.Errorf("failed to set rarity_rank for %s fish: %v", rarity, err)
+				}
+			}
+			_, err := coll.UpdateMany(ctx, bson.M{"rarity_rank": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"rarity_rank": 0}})
+			if err != nil {
+				return fmt.Errorf("failed to set rarity_rank for remaining fish: %v", err)
+			}
+			return m.createIndexesForCollection(ctx, fishCollection)
+		},
+	},
+	{
+		Version:     8,
+		Name:        "per_language_translations",
+		Description: "Allow one translation per fish and language instead of one per fish",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			coll := m.collection(translatedCollection)
+			_, err := coll.UpdateMany(ctx, bson.M{"language": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"language": "vi"}})
+			if err != nil {
+				return fmt.Errorf("failed to backfill translation language: %v", err)
+			}
+
+			// The old unique index on original_id alone blocks a second language
+			indexes, err := coll.Indexes().ListSpecifications(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list translation indexes: %v", err)
+			}
+			for _, index := range indexes {
+				if index.Name == "original_id_1" {
+					if _, err := coll.Indexes().DropOne(ctx, index.Name); err != nil {
+						return fmt.Errorf("failed to drop index %s: %v", index.Name, err)
+					}
+				}
+			}
+			return m.createIndexesForCollection(ctx, translatedCollection)
+		},
+	},
+	{
+		Version:     9,
+		Name:        "llm_response_cache",
+		Description: "Create the llm_cache collection with its key and expiry indexes",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, llmCacheCollection)
+		},
+	},
+	{
+		Version:     10,
+		Name:        "llm_usage",
+		Description: "Create the llm_usage collection for token and cost accounting",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, llmUsageCollection)
+		},
+	},
+	{
+		Version:     11,
+		Name:        "fish_prompt_version_index",
+		Description: "Index fish by the prompt template version that generated them",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			return m.createIndexesForCollection(ctx, fishCollection)
+		},
+	},
+	{
+		Version:     12,
+		Name:        "prompt_experiments",
+		Description: "Create the experiment_counters collection and index fish by prompt experiment",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			if err := m.createIndexesForCollection(ctx, experimentCollection); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, fishCollection)
+		},
+	},
+	{
+		Version:     13,
+		Name:        "fish_variant_of_index",
+		Description: "Index fish by the species they are a variant of",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			return m.createIndexesForCollection(ctx, fishCollection)
+		},
+	},
+	{
+		Version:     14,
+		Name:        "fish_moderation_status",
+		Description: "Publish existing fish and index fish by status, for quarantining fish flagged by content moderation",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			_, err := m.collection(fishCollection).UpdateMany(ctx, bson.M{"status": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"status": data.FishStatusPublished}})
+			if err != nil {
+				return fmt.Errorf("failed to backfill fish status: %v", err)
+			}
+			return m.createIndexesForCollection(ctx, fishCollection)
+		},
+		Preview: func(ctx context.Context, m *MongoDB) (string, error) {
+			unpublished, err := m.collection(fishCollection).CountDocuments(ctx, bson.M{"status": bson.M{"$exists": false}})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d fish without status", unpublished), nil
+		},
+	},
+	{
+		Version:     15,
+		Name:        "fish_audit",
+		Description: "Create the fish_audit collection for admin changes to fish",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, fishAuditCollection)
+		},
+	},
+	{
+		Version:     16,
+		Name:        "api_quota",
+		Description: "Create the api_quota collection for per-source API call budgets",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, apiQuotaCollection)
+		},
+	},
+	{
+		Version:     17,
+		Name:        "region_weather",
+		Description: "Create the region_weather collection for the aggregated weather of each region",
+		Up: func(ctx context.Context, m *MongoDB) error {
+			if err := m.initializeCollections(ctx); err != nil {
+				return err
+			}
+			return m.createIndexesForCollection(ctx, regionWeatherCollection)
+		},
+	},
+}
+
+// blankRegionFilter matches fish whose region_id is present but empty or null
+var blankRegionFilter = bson.M{"region_id": bson.M{"$exists": true, "$in": bson.A{"", nil}}}
+
+// legacyStatEffectsFilter matches fish whose stat_effects is a single document
+// or contains entries in the old stat/value shape
+var legacyStatEffectsFilter = bson.M{"$or": bson.A{
+	bson.M{"stat_effects": bson.M{"$type": "object"}},
+	bson.M{"stat_effects": bson.M{"$elemMatch": bson.M{
+		"stat":        bson.M{"$exists": true},
+		"effect_type": bson.M{"$exists": false},
+	}}},
+}}
+
+// missingValueFilter matches fish saved before value was stored
+var missingValueFilter = bson.M{"value": bson.M{"$exists": false}}
+
+// collection returns a handle to a collection in the configured database
+func (m *MongoDB) collection(name string) *mongo.Collection {
+	return m.client.Database(m.database).Collection(name)
+}
+
+// MigrationStatuses lists every known migration and whether it has been applied
+func (m *MongoDB) MigrationStatuses(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(mongoMigrations))
+	for _, migration := range mongoMigrations {
+		status := MigrationStatus{
+			Version:     migration.Version,
+			Name:        migration.Name,
+			Description: migration.Description,
+		}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// SchemaVersion returns the highest applied migration version
+func (m *MongoDB) SchemaVersion(ctx context.Context) (int, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// ApplyMigrations runs every pending migration in version order.
+// With dryRun set, nothing is changed and a description of each pending migration is returned.
+func (m *MongoDB) ApplyMigrations(ctx context.Context, dryRun bool) ([]string, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []string
+	for _, migration := range mongoMigrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if dryRun {
+			line := fmt.Sprintf("%03d %s: %s", migration.Version, migration.Name, migration.Description)
+			if migration.Preview != nil {
+				preview, err := migration.Preview(ctx, m)
+				if err != nil {
+					return report, fmt.Errorf("failed to preview migration %d: %v", migration.Version, err)
+				}
+				line += " (" + preview + ")"
+			}
+			report = append(report, line)
+			continue
+		}
+
+		start := time.Now()
+		log.Printf("Applying MongoDB migration %d: %s", migration.Version, migration.Name)
+		if err := migration.Up(ctx, m); err != nil {
+			return report, fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+		}
+
+		record := MigrationRecord{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			AppliedAt:  time.Now(),
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		opts := options.Replace().SetUpsert(true)
+		_, err := m.collection(migrationsCollection).ReplaceOne(ctx, bson.M{"version": migration.Version}, record, opts)
+		if err != nil {
+			return report, fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+		}
+
+		report = append(report, fmt.Sprintf("%03d %s: applied in %dms", migration.Version, migration.Name, record.DurationMS))
+	}
+
+	return report, nil
+}
+
+// appliedMigrations returns the applied migration records keyed by version
+func (m *MongoDB) appliedMigrations(ctx context.Context) (map[int]MigrationRecord, error) {
+	cursor, err := m.collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []MigrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %v", err)
+	}
+
+	applied := make(map[int]MigrationRecord, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// normalizeStatEffects rewrites legacy stat_effects into a list of effect_type/modifier entries.
+// Original stat/value keys are kept so no information is lost.
+func normalizeStatEffects(ctx context.Context, m *MongoDB) error {
+	coll := m.collection(fishCollection)
+	cursor, err := coll.Find(ctx, legacyStatEffectsFilter,
+		options.Find().SetProjection(bson.M{"_id": 1, "stat_effects": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find legacy stat effects: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID          primitive.ObjectID `bson:"_id"`
+			StatEffects bson.RawValue      `bson:"stat_effects"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode fish stat effects: %v", err)
+		}
+
+		var entries []bson.M
+		switch doc.StatEffects.Type {
+		case bson.TypeEmbeddedDocument:
+			var single bson.M
+			if err := doc.StatEffects.Unmarshal(&single); err != nil {
+				return fmt.Errorf("failed to decode stat effect for fish %s: %v", doc.ID.Hex(), err)
+			}
+			entries = []bson.M{single}
+		case bson.TypeArray:
+			if err := doc.StatEffects.Unmarshal(&entries); err != nil {
+				return fmt.Errorf("failed to decode stat effects for fish %s: %v", doc.ID.Hex(), err)
+			}
+		default:
+			continue
+		}
+
+		for _, entry := range entries {
+			normalizeStatEffectEntry(entry)
+		}
+
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"stat_effects": entries}}); err != nil {
+			return fmt.Errorf("failed to update stat effects for fish %s: %v", doc.ID.Hex(), err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("Normalized stat_effects on %d fish", updated)
+	return nil
+}
+
+// normalizeStatEffectEntry adds effect_type, modifier and description to a legacy stat/value entry
+func normalizeStatEffectEntry(entry bson.M) {
+	if _, ok := entry["effect_type"]; ok {
+		return
+	}
+
+	entry["effect_type"] = "player"
+
+	value := 0.0
+	switch v := entry["value"].(type) {
+	case float64:
+		value = v
+	case int32:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	}
+
+	isPercent, _ := entry["is_percentage"].(bool)
+	if isPercent {
+		entry["modifier"] = value / 100.0
+	} else {
+		entry["modifier"] = value
+	}
+
+	if _, ok := entry["description"]; !ok {
+		if stat, ok := entry["stat"].(string); ok {
+			entry["description"] = fmt.Sprintf("Affects %s", stat)
+		}
+	}
+}
+
+// backfillFishValueAndEffect computes the value the generator would have given each
+// older fish, and copies the effect text out of its environment stat effect
+func backfillFishValueAndEffect(ctx context.Context, m *MongoDB) error {
+	coll := m.collection(fishCollection)
+	cursor, err := coll.Find(ctx, missingValueFilter)
+	if err != nil {
+		return fmt.Errorf("failed to find fish without value: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var fish FishData
+		if err := cursor.Decode(&fish); err != nil {
+			return fmt.Errorf("failed to decode fish: %v", err)
+		}
+
+		set := bson.M{
+			"value": math.Round(fish.Length*10*data.RarityValueMultiplier(fish.Rarity)*100) / 100,
+		}
+		if fish.Effect == "" {
+			for _, effect := range fish.StatEffects {
+				if effect.EffectType == "environment" && effect.Description != "" {
+					set["effect"] = effect.Description
+					break
+				}
+			}
+		}
+
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": fish.ID}, bson.M{"$set": set}); err != nil {
+			return fmt.Errorf("failed to backfill fish %s: %v", fish.ID.Hex(), err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("Backfilled value on %d fish", updated)
+	return nil
+}
@@ -18,17 +18,44 @@ import (
 
 // Define collection names
 const (
-	weatherCollection    = "weather"
-	priceCollection      = "prices"
-	newsCollection       = "news"
-	fishCollection       = "fish"
-	regionCollection     = "regions"
-	statsCollection      = "stats"
-	usedNewsCollection   = "used_news"
-	queueCollection      = "generation_queue"
-	translatedCollection = "translated_fish" // New collection for translated fish
+	weatherCollection       = "weather"
+	priceCollection         = "prices"
+	newsCollection          = "news"
+	fishCollection          = "fish"
+	regionCollection        = "regions"
+	statsCollection         = "stats"
+	usedNewsCollection      = "used_news"
+	queueCollection         = "generation_queue"
+	translatedCollection    = "translated_fish" // New collection for translated fish
+	migrationsCollection    = "schema_migrations"
+	llmCacheCollection      = "llm_cache"
+	llmUsageCollection      = "llm_usage"
+	experimentCollection    = "experiment_counters"
+	fishAuditCollection     = "fish_audit"
+	apiQuotaCollection      = "api_quota"
+	regionWeatherCollection = "region_weather"
 )
 
+// requiredCollections lists every collection the service uses
+var requiredCollections = []string{
+	weatherCollection,
+	priceCollection,
+	newsCollection,
+	fishCollection,
+	regionCollection,
+	statsCollection,
+	usedNewsCollection,
+	queueCollection,
+	translatedCollection,
+	migrationsCollection,
+	llmCacheCollection,
+	llmUsageCollection,
+	experimentCollection,
+	fishAuditCollection,
+	apiQuotaCollection,
+	regionWeatherCollection,
+}
+
 // WeatherData represents a weather data document in MongoDB
 type WeatherData struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"`
@@ -71,30 +98,54 @@ type NewsData struct {
 	Timestamp   time.Time          `bson:"timestamp"`
 }
 
-// FishData represents a generated fish document in MongoDB
+// FishData represents a generated fish document in MongoDB.
+// The fish fields come from the canonical data.FishRecord and are stored inline.
 type FishData struct {
-	ID               primitive.ObjectID       `bson:"_id,omitempty"`
-	Name             string                   `bson:"name"`
-	Description      string                   `bson:"description"`
-	Rarity           string                   `bson:"rarity"`
-	Length           float64                  `bson:"length"`
-	Weight           float64                  `bson:"weight"`
-	Color            string                   `bson:"color"`
-	Habitat          string                   `bson:"habitat"`
-	Diet             string                   `bson:"diet"`
-	GeneratedAt      time.Time                `bson:"generated_at"`
-	IsAIGenerated    bool                     `bson:"is_ai_generated"`
-	DataSource       string                   `bson:"data_source"`
-	RegionID         string                   `bson:"region_id,omitempty"`
-	FavoriteWeather  string                   `bson:"favorite_weather"`
-	CatchChance      float64                  `bson:"catch_chance"`
-	ExistenceReason  string                   `bson:"existence_reason"`
-	WeatherID        primitive.ObjectID       `bson:"weather_id,omitempty"`
-	NewsID           primitive.ObjectID       `bson:"news_id,omitempty"`
-	PriceIDs         []primitive.ObjectID     `bson:"price_ids,omitempty"`
-	StatEffects      []map[string]interface{} `bson:"stat_effects,omitempty"`
-	GenerationReason string                   `bson:"generation_reason,omitempty"`
-	UsedArticles     []map[string]interface{} `bson:"used_articles,omitempty"`
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	data.FishRecord `bson:",inline"`
+	RarityRank      int                  `bson:"rarity_rank"` // data.RarityRank, stored so the catalog can sort by rarity
+	WeatherID       primitive.ObjectID   `bson:"weather_id,omitempty"`
+	NewsID          primitive.ObjectID   `bson:"news_id,omitempty"`
+	PriceIDs        []primitive.ObjectID `bson:"price_ids,omitempty"`
+}
+
+// newFishData prepares a fish record for storage, sanitizing it and
+// assigning an ID and generation time when they are missing
+func newFishData(record *data.FishRecord) (*FishData, error) {
+	if record == nil {
+		return nil, fmt.Errorf("fish record is nil")
+	}
+
+	fishData := &FishData{FishRecord: *record}
+	fishData.Sanitize()
+	fishData.RarityRank = data.RarityRank(fishData.Rarity)
+	if fishData.Status == "" {
+		fishData.Status = data.FishStatusPublished
+	}
+
+	if record.ID != "" {
+		id, err := primitive.ObjectIDFromHex(record.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fish ID: %v", err)
+		}
+		fishData.ID = id
+	} else {
+		fishData.ID = primitive.NewObjectID()
+	}
+	if fishData.GeneratedAt.IsZero() {
+		fishData.GeneratedAt = time.Now()
+	}
+	// BSON dates have millisecond precision; truncate so the caller's copy matches what is read back
+	fishData.GeneratedAt = fishData.GeneratedAt.Truncate(time.Millisecond)
+
+	return fishData, nil
+}
+
+// Record returns the canonical fish record with its ID set
+func (f *FishData) Record() *data.FishRecord {
+	record := f.FishRecord
+	record.ID = f.ID.Hex()
+	return &record
 }
 
 // CollectionStats tracks statistics about each collection
@@ -147,7 +198,7 @@ type TranslatedFishData struct {
 	FavoriteWeather string             `bson:"favorite_weather"`
 	ExistenceReason string             `bson:"existence_reason"`
 	TranslatedAt    time.Time          `bson:"translated_at"`
-	Language        string             `bson:"language"` // e.g., "vi" for Vietnamese
+	Language        string             `bson:"language"` // Locale code, e.g. "vi" for Vietnamese
 }
 
 // MongoDB implements database operations using MongoDB
@@ -157,8 +208,32 @@ type MongoDB struct {
 	connected bool
 }
 
-// NewMongoDB creates a new MongoDB client
+// NewMongoDB creates a new MongoDB client and applies pending schema migrations
 func NewMongoDB(uri, database string) (*MongoDB, error) {
+	db, err := ConnectMongoDB(uri, database)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// Bring the schema up to date
+	report, err := db.ApplyMigrations(ctx, false)
+	for _, line := range report {
+		log.Printf("Migration %s", line)
+	}
+	if err != nil {
+		// Never run against a partly migrated schema
+		db.Close(ctx)
+		return nil, fmt.Errorf("failed to apply MongoDB migrations: %v", err)
+	}
+
+	return db, nil
+}
+
+// ConnectMongoDB creates a new MongoDB client without touching the schema
+func ConnectMongoDB(uri, database string) (*MongoDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -179,19 +254,11 @@ func NewMongoDB(uri, database string) (*MongoDB, error) {
 
 	log.Println("Connected to MongoDB successfully")
 
-	db := &MongoDB{
+	return &MongoDB{
 		client:    client,
 		database:  database,
 		connected: true,
-	}
-
-	// Initialize collections
-	if err := db.initializeCollections(ctx); err != nil {
-		log.Printf("Warning: Failed to initialize collections: %v", err)
-		// Continue anyway, as we'll try to create collections on-demand
-	}
-
-	return db, nil
+	}, nil
 }
 
 // initializeCollections ensures that all required collections exist
@@ -204,19 +271,6 @@ func (m *MongoDB) initializeCollections(ctx context.Context) error {
 		return fmt.Errorf("failed to list collections: %v", err)
 	}
 
-	// Check for each required collection
-	requiredCollections := []string{
-		weatherCollection,
-		priceCollection,
-		newsCollection,
-		fishCollection,
-		regionCollection,
-		statsCollection,
-		usedNewsCollection,
-		queueCollection,
-		translatedCollection, // Add translated fish collection
-	}
-
 	existingCollections := make(map[string]bool)
 	for _, name := range names {
 		existingCollections[name] = true
@@ -287,12 +341,23 @@ func (m *MongoDB) createIndexesForCollection(ctx context.Context, collectionName
 		return err
 
 	case fishCollection:
-		// Index on generation_time and region_id for faster fish queries
-		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-			Keys: bson.D{
-				{Key: "generated_at", Value: -1},
-				{Key: "region_id", Value: 1},
-			},
+		// Index on generation_time and region_id for faster fish queries,
+		// plus compound indexes backing the catalog filters and sort orders.
+		// Every sort index ends with _id to match QueryFish's keyset pagination.
+		_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "generated_at", Value: -1}, {Key: "region_id", Value: 1}}},
+			{Keys: bson.D{{Key: "generated_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "value", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "length", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "rarity_rank", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "region_id", Value: 1}, {Key: "rarity", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "data_source", Value: 1}, {Key: "rarity", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "is_ai_generated", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "favorite_weather", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "prompt_version", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "experiment", Value: 1}, {Key: "experiment_variant", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "variant_of", Value: 1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "generated_at", Value: -1}}},
 		})
 		return err
 
@@ -326,11 +391,22 @@ func (m *MongoDB) createIndexesForCollection(ctx context.Context, collectionName
 		})
 		return err
 
+	case migrationsCollection:
+		// One record per applied migration version
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "version", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
+
 	case translatedCollection:
-		// Index on original_id for fast lookups
+		// One translation per fish and language
 		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
 			Keys: bson.D{
 				{Key: "original_id", Value: 1},
+				{Key: "language", Value: 1},
 			},
 			Options: options.Index().SetUnique(true),
 		})
@@ -345,6 +421,57 @@ func (m *MongoDB) createIndexesForCollection(ctx context.Context, collectionName
 			},
 		})
 		return err
+
+	case llmCacheCollection:
+		// One response per cache key, removed by MongoDB once it expires,
+		// plus created_at for evicting the oldest entries
+		_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		})
+		return err
+
+	case llmUsageCollection:
+		// Index on timestamp for daily aggregates
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "timestamp", Value: -1},
+			},
+		})
+		return err
+
+	case experimentCollection:
+		// One counter document per experiment variant
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "experiment", Value: 1}, {Key: "variant", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
+
+	case fishAuditCollection:
+		// Audit trail of one fish, and of every fish, newest first
+		_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "fish_id", Value: 1}, {Key: "at", Value: -1}}},
+			{Keys: bson.D{{Key: "at", Value: -1}}},
+		})
+		return err
+
+	case apiQuotaCollection:
+		// One counter document per source and quota window
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "window", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
+
+	case regionWeatherCollection:
+		// One document per region with its latest weather
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "region_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
 	}
 
 	return nil
@@ -482,226 +609,17 @@ func (m *MongoDB) SaveNewsData(ctx context.Context, newsItem *data.NewsItem) err
 	return nil
 }
 
-// SaveFishData saves fish data to MongoDB
-func (m *MongoDB) SaveFishData(ctx context.Context, fish interface{}) error {
+// SaveFishData saves a fish record to MongoDB and sets its ID
+func (m *MongoDB) SaveFishData(ctx context.Context, fish *data.FishRecord) error {
 	// Make sure the fish collection exists
 	collection, err := m.ensureCollection(ctx, fishCollection)
 	if err != nil {
 		return fmt.Errorf("failed to ensure fish collection exists: %v", err)
 	}
 
-	// First, prepare the fish data with full sanitization
-	var fishData FishData
-	var usedArticles []map[string]interface{}
-
-	switch f := fish.(type) {
-	case FishData:
-		fishData = f
-		// Sanitize string fields
-		fishData.Name = data.SanitizeUTF8(fishData.Name)
-		fishData.Description = data.SanitizeUTF8(fishData.Description)
-		fishData.Rarity = data.SanitizeUTF8(fishData.Rarity)
-		fishData.Habitat = data.SanitizeUTF8(fishData.Habitat)
-		fishData.Diet = data.SanitizeUTF8(fishData.Diet)
-		fishData.Color = data.SanitizeUTF8(fishData.Color)
-		fishData.RegionID = data.SanitizeUTF8(fishData.RegionID)
-		fishData.DataSource = data.SanitizeUTF8(fishData.DataSource)
-		fishData.GenerationReason = data.SanitizeUTF8(fishData.GenerationReason)
-		fishData.FavoriteWeather = data.SanitizeUTF8(fishData.FavoriteWeather)
-		fishData.ExistenceReason = data.SanitizeUTF8(fishData.ExistenceReason)
-
-		// Sanitize UsedArticles deeply
-		for _, article := range fishData.UsedArticles {
-			sanitizedArticle := make(map[string]interface{})
-			for k, v := range article {
-				if strVal, ok := v.(string); ok {
-					sanitizedArticle[k] = data.SanitizeUTF8(strVal)
-				} else {
-					sanitizedArticle[k] = v
-				}
-			}
-			usedArticles = append(usedArticles, sanitizedArticle)
-		}
-		fishData.UsedArticles = usedArticles
-
-	case *FishData:
-		// Sanitize pointer to FishData
-		fishData = *f
-		// Sanitize string fields
-		fishData.Name = data.SanitizeUTF8(fishData.Name)
-		fishData.Description = data.SanitizeUTF8(fishData.Description)
-		fishData.Rarity = data.SanitizeUTF8(fishData.Rarity)
-		fishData.Habitat = data.SanitizeUTF8(fishData.Habitat)
-		fishData.Diet = data.SanitizeUTF8(fishData.Diet)
-		fishData.Color = data.SanitizeUTF8(fishData.Color)
-		fishData.RegionID = data.SanitizeUTF8(fishData.RegionID)
-		fishData.DataSource = data.SanitizeUTF8(fishData.DataSource)
-		fishData.GenerationReason = data.SanitizeUTF8(fishData.GenerationReason)
-		fishData.FavoriteWeather = data.SanitizeUTF8(fishData.FavoriteWeather)
-		fishData.ExistenceReason = data.SanitizeUTF8(fishData.ExistenceReason)
-
-		// Sanitize UsedArticles deeply
-		for _, article := range fishData.UsedArticles {
-			sanitizedArticle := make(map[string]interface{})
-			for k, v := range article {
-				if strVal, ok := v.(string); ok {
-					sanitizedArticle[k] = data.SanitizeUTF8(strVal)
-				} else {
-					sanitizedArticle[k] = v
-				}
-			}
-			usedArticles = append(usedArticles, sanitizedArticle)
-		}
-		fishData.UsedArticles = usedArticles
-
-	case map[string]interface{}:
-		// Extract fields from map with sanitization
-		if name, ok := f["name"].(string); ok {
-			fishData.Name = data.SanitizeUTF8(name)
-		}
-		if desc, ok := f["description"].(string); ok {
-			fishData.Description = data.SanitizeUTF8(desc)
-		}
-		if rarity, ok := f["rarity"].(string); ok {
-			fishData.Rarity = data.SanitizeUTF8(rarity)
-		}
-		if habitat, ok := f["habitat"].(string); ok {
-			fishData.Habitat = data.SanitizeUTF8(habitat)
-		}
-		if diet, ok := f["diet"].(string); ok {
-			fishData.Diet = data.SanitizeUTF8(diet)
-		}
-		if color, ok := f["color"].(string); ok {
-			fishData.Color = data.SanitizeUTF8(color)
-		}
-		if regionID, ok := f["region_id"].(string); ok {
-			fishData.RegionID = data.SanitizeUTF8(regionID)
-		}
-		if dataSource, ok := f["data_source"].(string); ok {
-			fishData.DataSource = data.SanitizeUTF8(dataSource)
-		}
-		if genReason, ok := f["generation_reason"].(string); ok {
-			fishData.GenerationReason = data.SanitizeUTF8(genReason)
-		}
-		if favWeather, ok := f["favorite_weather"].(string); ok {
-			fishData.FavoriteWeather = data.SanitizeUTF8(favWeather)
-		}
-		if existReason, ok := f["existence_reason"].(string); ok {
-			fishData.ExistenceReason = data.SanitizeUTF8(existReason)
-		}
-
-		// Numeric fields
-		if length, ok := f["length"].(float64); ok {
-			fishData.Length = length
-		}
-		if weight, ok := f["weight"].(float64); ok {
-			fishData.Weight = weight
-		}
-		if catchChance, ok := f["catch_chance"].(float64); ok {
-			fishData.CatchChance = catchChance
-		}
-
-		// Boolean fields
-		if isAI, ok := f["is_ai_generated"].(bool); ok {
-			fishData.IsAIGenerated = isAI
-		}
-
-		// Time fields
-		if genTime, ok := f["generated_at"].(time.Time); ok {
-			fishData.GeneratedAt = genTime
-		}
-
-		// Complex fields
-		if effects, ok := f["stat_effects"].([]map[string]interface{}); ok {
-			// Sanitize stat effects
-			sanitizedEffects := make([]map[string]interface{}, 0, len(effects))
-			for _, effect := range effects {
-				sanitizedEffect := make(map[string]interface{})
-				for k, v := range effect {
-					if strVal, ok := v.(string); ok {
-						sanitizedEffect[k] = data.SanitizeUTF8(strVal)
-					} else {
-						sanitizedEffect[k] = v
-					}
-				}
-				sanitizedEffects = append(sanitizedEffects, sanitizedEffect)
-			}
-			fishData.StatEffects = sanitizedEffects
-		}
-
-		// Deep sanitization for used articles
-		if articles, ok := f["used_articles"].([]map[string]interface{}); ok {
-			sanitizedArticles := make([]map[string]interface{}, 0, len(articles))
-			for _, article := range articles {
-				sanitizedArticle := make(map[string]interface{})
-				for k, v := range article {
-					if strVal, ok := v.(string); ok {
-						sanitizedArticle[k] = data.SanitizeUTF8(strVal)
-					} else {
-						sanitizedArticle[k] = v
-					}
-				}
-				sanitizedArticles = append(sanitizedArticles, sanitizedArticle)
-			}
-			fishData.UsedArticles = sanitizedArticles
-		}
-	default:
-		// Try to convert from a fish type that implements required methods
-		info, ok := fish.(interface {
-			GetName() string
-			GetDescription() string
-			GetRarity() string
-			GetLength() float64
-			GetWeight() float64
-			GetColor() string
-			GetHabitat() string
-			GetDiet() string
-			IsAIGenerated() bool
-			GetDataSource() string
-			GetStatEffects() interface{}
-		})
-
-		if !ok {
-			return fmt.Errorf("invalid fish type")
-		}
-
-		// Sanitize all string values during conversion
-		var statEffects []map[string]interface{}
-		if se, ok := info.GetStatEffects().([]map[string]interface{}); ok {
-			sanitizedEffects := make([]map[string]interface{}, 0, len(se))
-			for _, effect := range se {
-				sanitizedEffect := make(map[string]interface{})
-				for k, v := range effect {
-					if strVal, ok := v.(string); ok {
-						sanitizedEffect[k] = data.SanitizeUTF8(strVal)
-					} else {
-						sanitizedEffect[k] = v
-					}
-				}
-				sanitizedEffects = append(sanitizedEffects, sanitizedEffect)
-			}
-			statEffects = sanitizedEffects
-		}
-
-		fishData = FishData{
-			Name:          data.SanitizeUTF8(info.GetName()),
-			Description:   data.SanitizeUTF8(info.GetDescription()),
-			Rarity:        data.SanitizeUTF8(info.GetRarity()),
-			Length:        info.GetLength(),
-			Weight:        info.GetWeight(),
-			Color:         data.SanitizeUTF8(info.GetColor()),
-			Habitat:       data.SanitizeUTF8(info.GetHabitat()),
-			Diet:          data.SanitizeUTF8(info.GetDiet()),
-			GeneratedAt:   time.Now(),
-			IsAIGenerated: info.IsAIGenerated(),
-			DataSource:    data.SanitizeUTF8(info.GetDataSource()),
-			StatEffects:   statEffects,
-		}
-	}
-
-	// Set generation time if not already set
-	if fishData.GeneratedAt.IsZero() {
-		fishData.GeneratedAt = time.Now()
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return err
 	}
 
 	// Perform one final validation pass on all fields
@@ -721,10 +639,12 @@ func (m *MongoDB) SaveFishData(ctx context.Context, fish interface{}) error {
 	}
 
 	// Insert document
-	result, err := collection.InsertOne(ctx, fishData)
-	if err != nil {
+	if _, err := collection.InsertOne(ctx, fishData); err != nil {
 		return fmt.Errorf("failed to insert fish data: %v", err)
 	}
+	fish.ID = fishData.ID.Hex()
+	fish.GeneratedAt = fishData.GeneratedAt
+	fish.Status = fishData.Status
 
 	// Increment daily fish count
 	err = m.incrementDailyFishCount(ctx)
@@ -732,10 +652,70 @@ func (m *MongoDB) SaveFishData(ctx context.Context, fish interface{}) error {
 		log.Printf("Warning: failed to increment daily fish count: %v", err)
 	}
 
-	log.Printf("Fish data saved: %s (ID: %s)", fishData.Name, result.InsertedID.(primitive.ObjectID).Hex())
+	log.Printf("Fish data saved: %s (ID: %s)", fishData.Name, fish.ID)
+	return nil
+}
+
+// UpdateFishData replaces the stored fields of an existing fish record, keeping
+// fields only the document has, like translations. When status is set, the stored fish
+// must still have it, or ErrFishStatusChanged is returned.
+func (m *MongoDB) UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error {
+	if fish == nil || fish.ID == "" {
+		return fmt.Errorf("fish to update has no ID")
+	}
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFishNotFound, err)
+	}
+
+	update, err := fishUpdate(fishData)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": fishData.ID}
+	if status != "" {
+		filter["status"] = status
+	}
+	result, err := m.collection(fishCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update fish data: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		if status != "" {
+			if count, err := m.collection(fishCollection).CountDocuments(ctx, bson.M{"_id": fishData.ID}); err == nil && count > 0 {
+				return fmt.Errorf("%w: %s is no longer %s", ErrFishStatusChanged, fish.ID, status)
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrFishNotFound, fish.ID)
+	}
+	fish.Status = fishData.Status
 	return nil
 }
 
+// fishUpdate builds an update setting the fish record fields of a document.
+// Record fields left empty by omitempty are unset so cleared values don't linger.
+func fishUpdate(fishData *FishData) (bson.M, error) {
+	doc, err := fishDataToDocument(fishData)
+	if err != nil {
+		return nil, err
+	}
+	delete(doc, "_id")
+
+	unset := bson.M{}
+	for _, field := range fishRecordFields {
+		if _, ok := doc[field]; !ok {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$set": doc}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
 // validateDeepMap recursively sanitizes all string values in a map
 func validateDeepMap(m bson.M) {
 	for key, value := range m {
@@ -796,6 +776,29 @@ func (m *MongoDB) GetRecentWeatherData(ctx context.Context, regionID string, lim
 	return results, nil
 }
 
+// SaveRegionWeather replaces the aggregated weather of a region
+func (m *MongoDB) SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := m.collection(regionWeatherCollection).ReplaceOne(ctx, bson.M{"region_id": weather.RegionID}, weather, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save region weather: %v", err)
+	}
+	return nil
+}
+
+// GetRegionWeather returns the aggregated weather of a region, or nil if none was saved
+func (m *MongoDB) GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error) {
+	var weather data.RegionalWeather
+	err := m.collection(regionWeatherCollection).FindOne(ctx, bson.M{"region_id": regionID}).Decode(&weather)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // No weather collected yet
+		}
+		return nil, fmt.Errorf("failed to retrieve region weather: %v", err)
+	}
+	return &weather, nil
+}
+
 // GetRecentPriceData retrieves recent price data for a specific asset type
 func (m *MongoDB) GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error) {
 	collection := m.client.Database(m.database).Collection(priceCollection)
@@ -933,6 +936,7 @@ func (m *MongoDB) GetSimilarFish(ctx context.Context, dataSource string, rarityL
 	if len(filter) == 0 {
 		return nil, fmt.Errorf("at least one filter parameter (dataSource or rarityLevel) must be provided")
 	}
+	filter["status"] = data.FishStatusPublished
 
 	// Find fish that match the criteria
 	// Sort by most recently generated to get the newest matching fish
@@ -947,7 +951,7 @@ func (m *MongoDB) GetSimilarFish(ctx context.Context, dataSource string, rarityL
 	return &fish, nil
 }
 
-// GetFishByRegion retrieves fish for a specific region
+// GetFishByRegion retrieves fish for a specific region, including fish saved without a region
 func (m *MongoDB) GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*FishData, error) {
 	// Make sure the fish collection exists
 	collection, err := m.ensureCollection(ctx, fishCollection)
@@ -960,10 +964,10 @@ func (m *MongoDB) GetFishByRegion(ctx context.Context, regionID string, limit in
 		limit = 10
 	}
 
-	// Create filter for region
-	filter := bson.M{}
+	// Create filter for region; fish without a region belong to every region
+	filter := bson.M{"status": data.FishStatusPublished}
 	if regionID != "" {
-		filter["region_id"] = regionID
+		filter["region_id"] = bson.M{"$in": bson.A{regionID, "", nil}}
 	}
 
 	// Find fish matching the criteria
@@ -994,7 +998,7 @@ func (m *MongoDB) GetFishByDataSource(ctx context.Context, dataSource string, li
 		SetLimit(int64(limit))
 
 	// Execute the query
-	filter := bson.M{"data_source": dataSource}
+	filter := bson.M{"data_source": dataSource, "status": data.FishStatusPublished}
 
 	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -1011,6 +1015,127 @@ func (m *MongoDB) GetFishByDataSource(ctx context.Context, dataSource string, li
 	return results, nil
 }
 
+// GetSpeciesSummaries returns the ID, name and description of every stored fish
+func (m *MongoDB) GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "description": 1, "variant_of": 1})
+
+	cursor, err := m.collection(fishCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find species: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*FishData
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode species: %v", err)
+	}
+	return speciesSummaries(results), nil
+}
+
+// QueryFish returns fish matching the query in sort order, starting after the query cursor.
+// Up to query.Limit+1 fish are returned so the caller can tell whether another page exists.
+func (m *MongoDB) QueryFish(ctx context.Context, query FishQuery) ([]*FishData, error) {
+	cursor, err := query.after()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"status": bson.M{"$in": query.Statuses}}
+	if len(query.Rarities) > 0 {
+		filter["rarity"] = bson.M{"$in": query.Rarities}
+	}
+	if query.RegionID != "" {
+		filter["region_id"] = query.RegionID
+	}
+	if query.DataSource != "" {
+		filter["data_source"] = query.DataSource
+	}
+	if query.IsAIGenerated != nil {
+		filter["is_ai_generated"] = *query.IsAIGenerated
+	}
+	if query.FavoriteWeather != "" {
+		filter["favorite_weather"] = query.FavoriteWeather
+	}
+	if query.Experiment != "" {
+		filter["experiment"] = query.Experiment
+	}
+	if query.Variant != "" {
+		filter["experiment_variant"] = query.Variant
+	}
+	if generated := mongoRange(query.GeneratedAfter, query.GeneratedBefore); len(generated) > 0 {
+		filter["generated_at"] = generated
+	}
+	if length := mongoFloatRange(query.MinLength, query.MaxLength); len(length) > 0 {
+		filter["length"] = length
+	}
+	if weight := mongoFloatRange(query.MinWeight, query.MaxWeight); len(weight) > 0 {
+		filter["weight"] = weight
+	}
+
+	sortField := mongoFishSortField(query.SortBy)
+	direction, comparison := 1, "$gt"
+	if query.Descending {
+		direction, comparison = -1, "$lt"
+	}
+
+	// Keyset pagination: continue strictly after the (sort key, _id) of the previous page
+	if cursor != nil {
+		lastID, err := primitive.ObjectIDFromHex(cursor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %v", err)
+		}
+		var lastKey interface{} = cursor.Key
+		if query.SortBy == SortByGeneratedAt {
+			lastKey = time.UnixMilli(int64(cursor.Key))
+		}
+		filter["$or"] = bson.A{
+			bson.M{sortField: bson.M{comparison: lastKey}},
+			bson.M{sortField: lastKey, "_id": bson.M{comparison: lastID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(query.Limit + 1))
+
+	results, err := m.collection(fishCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fish: %v", err)
+	}
+	defer results.Close(ctx)
+
+	var fish []*FishData
+	if err := results.All(ctx, &fish); err != nil {
+		return nil, fmt.Errorf("failed to decode fish data: %v", err)
+	}
+
+	return fish, nil
+}
+
+// mongoRange builds a $gte/$lt filter for a time range, skipping zero bounds
+func mongoRange(from, to time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !to.IsZero() {
+		r["$lt"] = to
+	}
+	return r
+}
+
+// mongoFloatRange builds an inclusive $gte/$lte filter, treating zero bounds as unbounded
+func mongoFloatRange(min, max float64) bson.M {
+	r := bson.M{}
+	if min > 0 {
+		r["$gte"] = min
+	}
+	if max > 0 {
+		r["$lte"] = max
+	}
+	return r
+}
+
 // GetDailyFishCount returns the number of fish generated today
 func (m *MongoDB) GetDailyFishCount(ctx context.Context) (int, error) {
 	collection, err := m.ensureCollection(ctx, statsCollection)
@@ -1195,6 +1320,10 @@ func (m *MongoDB) GetGenerationQueue(ctx context.Context) ([]data.GenerationRequ
 func (m *MongoDB) SaveTranslatedFish(ctx context.Context, translatedFish *data.TranslatedFish) error {
 	collection := m.client.Database(m.database).Collection(translatedCollection)
 
+	if translatedFish.Language == "" {
+		return fmt.Errorf("translation language is required")
+	}
+
 	// Convert string ID to ObjectID
 	originalID, err := primitive.ObjectIDFromHex(translatedFish.OriginalID)
 	if err != nil {
@@ -1214,13 +1343,13 @@ func (m *MongoDB) SaveTranslatedFish(ctx context.Context, translatedFish *data.T
 		FavoriteWeather: translatedFish.FavoriteWeather,
 		ExistenceReason: translatedFish.ExistenceReason,
 		TranslatedAt:    translatedFish.TranslatedAt,
-		Language:        "vi", // Vietnamese language code
+		Language:        translatedFish.Language,
 	}
 
-	// Filter for upsert based on original fish ID
+	// Filter for upsert based on original fish ID and language
 	filter := bson.M{
 		"original_id": originalID,
-		"language":    "vi",
+		"language":    translatedFish.Language,
 	}
 
 	// Set up upsert options
@@ -1237,8 +1366,8 @@ func (m *MongoDB) SaveTranslatedFish(ctx context.Context, translatedFish *data.T
 	return nil
 }
 
-// GetTranslatedFish retrieves a translated fish by original ID
-func (m *MongoDB) GetTranslatedFish(ctx context.Context, originalID string) (*data.TranslatedFish, error) {
+// GetTranslatedFish retrieves the translation of a fish into the given language
+func (m *MongoDB) GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error) {
 	collection := m.client.Database(m.database).Collection(translatedCollection)
 
 	// Convert string ID to ObjectID
@@ -1250,7 +1379,7 @@ func (m *MongoDB) GetTranslatedFish(ctx context.Context, originalID string) (*da
 	// Create filter
 	filter := bson.M{
 		"original_id": objID,
-		"language":    "vi",
+		"language":    language,
 	}
 
 	// Perform query
@@ -1275,28 +1404,30 @@ func (m *MongoDB) GetTranslatedFish(ctx context.Context, originalID string) (*da
 		Effect:          result.Effect,
 		FavoriteWeather: result.FavoriteWeather,
 		ExistenceReason: result.ExistenceReason,
+		Language:        result.Language,
 		TranslatedAt:    result.TranslatedAt,
 	}
 
 	return translatedFish, nil
 }
 
-// GetUntranslatedFishIDs retrieves IDs of fish that haven't been translated yet
-func (m *MongoDB) GetUntranslatedFishIDs(ctx context.Context, limit int) ([]string, error) {
+// GetUntranslatedFishIDs retrieves IDs of published fish that have no translation in the given language.
+// A limit of zero or less returns every such fish.
+func (m *MongoDB) GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error) {
 	// Get all fish IDs
 	fishColl := m.client.Database(m.database).Collection(fishCollection)
 	translatedColl := m.client.Database(m.database).Collection(translatedCollection)
 
-	// Find all fish, sort by newest first
-	fishCursor, err := fishColl.Find(ctx, bson.M{},
+	// Find all published fish, sort by newest first
+	fishCursor, err := fishColl.Find(ctx, bson.M{"status": data.FishStatusPublished},
 		options.Find().SetSort(bson.M{"generated_at": -1}).SetProjection(bson.M{"_id": 1}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query fish collection: %v", err)
 	}
 	defer fishCursor.Close(ctx)
 
-	// Get all translated fish ids
-	translatedCursor, err := translatedColl.Find(ctx, bson.M{},
+	// Get the ids of fish already translated into this language
+	translatedCursor, err := translatedColl.Find(ctx, bson.M{"language": language},
 		options.Find().SetProjection(bson.M{"original_id": 1}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query translated fish collection: %v", err)
@@ -1321,7 +1452,7 @@ func (m *MongoDB) GetUntranslatedFishIDs(ctx context.Context, limit int) ([]stri
 		ID primitive.ObjectID `bson:"_id"`
 	}
 
-	untranslatedIDs := make([]string, 0, limit)
+	untranslatedIDs := []string{}
 	for fishCursor.Next(ctx) {
 		if err := fishCursor.Decode(&fishDoc); err != nil {
 			return nil, fmt.Errorf("failed to decode fish document: %v", err)
@@ -1331,7 +1462,7 @@ func (m *MongoDB) GetUntranslatedFishIDs(ctx context.Context, limit int) ([]stri
 		fishIDStr := fishDoc.ID.Hex()
 		if !translatedIDs[fishIDStr] {
 			untranslatedIDs = append(untranslatedIDs, fishIDStr)
-			if len(untranslatedIDs) >= limit {
+			if limit > 0 && len(untranslatedIDs) >= limit {
 				break
 			}
 		}
@@ -1340,227 +1471,252 @@ func (m *MongoDB) GetUntranslatedFishIDs(ctx context.Context, limit int) ([]stri
 	return untranslatedIDs, nil
 }
 
-// GetFishByID retrieves a fish by its ID
-func (m *MongoDB) GetFishByID(ctx context.Context, id string) (map[string]interface{}, error) {
+// GetFishData retrieves a fish by its ID
+func (m *MongoDB) GetFishData(ctx context.Context, id string) (*FishData, error) {
 	collection := m.client.Database(m.database).Collection(fishCollection)
 
 	// Convert string ID to ObjectID
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fish ID: %v", err)
+		return nil, fmt.Errorf("%w: invalid ID %s", ErrFishNotFound, id)
 	}
 
 	// Create filter
 	filter := bson.M{"_id": objID}
 
 	// Perform query
-	var result bson.M
+	var result FishData
 	err = collection.FindOne(ctx, filter).Decode(&result)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("fish not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrFishNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to retrieve fish: %v", err)
 	}
 
-	// Convert primitive.ObjectID fields to strings for easier handling
-	if id, ok := result["_id"].(primitive.ObjectID); ok {
-		result["_id"] = id.Hex()
-	}
-
-	return result, nil
+	return &result, nil
 }
 
-// GetUntranslatedFish retrieves fish that haven't been translated to Vietnamese yet
-func (m *MongoDB) GetUntranslatedFish(ctx context.Context, limit int) ([]map[string]interface{}, error) {
-	// Create a filter for fish that haven't been translated yet
-	filter := bson.M{
-		"is_translated": bson.M{"$ne": true}, // Find fish without the is_translated flag
+// GetLLMCacheEntry retrieves a cached LLM response by key
+func (m *MongoDB) GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error) {
+	var entry data.LLMCacheEntry
+	err := m.collection(llmCacheCollection).FindOne(ctx, bson.M{"key": key}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Not cached
+		}
+		return nil, fmt.Errorf("failed to retrieve LLM cache entry: %v", err)
 	}
+	return &entry, nil
+}
 
-	// Options for sorting and limiting results
-	opts := options.Find().
-		SetSort(bson.M{"generated_at": -1}). // Most recent first
-		SetLimit(int64(limit))
-
-	// Execute the query
-	coll := m.client.Database(m.database).Collection(fishCollection)
-	cursor, err := coll.Find(ctx, filter, opts)
+// SaveLLMCacheEntry inserts or replaces a cached LLM response
+func (m *MongoDB) SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := m.collection(llmCacheCollection).ReplaceOne(ctx, bson.M{"key": entry.Key}, entry, opts)
 	if err != nil {
-		return nil, fmt.Errorf("error finding untranslated fish: %w", err)
+		return fmt.Errorf("failed to save LLM cache entry: %v", err)
 	}
-	defer cursor.Close(ctx)
+	return nil
+}
 
-	// Decode the results
-	var results []map[string]interface{}
-	if err := cursor.All(ctx, &results); err != nil {
-		return nil, fmt.Errorf("error decoding untranslated fish: %w", err)
+// RecordLLMCacheHit increments the hit count of a cached LLM response
+func (m *MongoDB) RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error {
+	_, err := m.collection(llmCacheCollection).UpdateOne(ctx, bson.M{"key": key},
+		bson.M{"$inc": bson.M{"hits": 1}, "$set": bson.M{"last_hit_at": at}})
+	if err != nil {
+		return fmt.Errorf("failed to update LLM cache entry: %v", err)
 	}
+	return nil
+}
 
-	if len(results) == 0 {
-		return []map[string]interface{}{}, nil // Return empty slice, not nil
+// DeleteLLMCacheEntry removes a cached LLM response
+func (m *MongoDB) DeleteLLMCacheEntry(ctx context.Context, key string) error {
+	if _, err := m.collection(llmCacheCollection).DeleteOne(ctx, bson.M{"key": key}); err != nil {
+		return fmt.Errorf("failed to delete LLM cache entry: %v", err)
 	}
-
-	return results, nil
+	return nil
 }
 
-// UpdateFishWithTranslation updates a fish with translated fields
-func (m *MongoDB) UpdateFishWithTranslation(ctx context.Context, fishID interface{}, translatedFish map[string]interface{}) error {
-	collection := m.client.Database(m.database).Collection(fishCollection)
-
-	// Log the translated fields for debugging
-	log.Printf("Updating fish with translation. Fish ID: %v", fishID)
+// PruneLLMCache removes expired LLM responses, then the oldest ones beyond maxEntries.
+// The TTL index also removes expired entries, but only once a minute.
+func (m *MongoDB) PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error) {
+	collection := m.collection(llmCacheCollection)
 
-	// Validate all string fields recursively before saving
-	err := validateAndSanitizeMap(translatedFish)
+	expired, err := collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}})
 	if err != nil {
-		log.Printf("Error validating translated fish: %v", err)
-		return err
+		return 0, fmt.Errorf("failed to delete expired LLM cache entries: %v", err)
 	}
+	removed := int(expired.DeletedCount)
 
-	// Create the filter using the ID
-	var filter bson.M
-	switch id := fishID.(type) {
-	case string:
-		objectID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return fmt.Errorf("invalid fish ID format: %w", err)
-		}
-		filter = bson.M{"_id": objectID}
-	case primitive.ObjectID:
-		filter = bson.M{"_id": id}
-	default:
-		// Try to use the ID as provided
-		filter = bson.M{"_id": fishID}
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return removed, fmt.Errorf("failed to count LLM cache entries: %v", err)
+	}
+	if maxEntries <= 0 || count <= int64(maxEntries) {
+		return removed, nil
 	}
 
-	// Create a set of updates for each field to add
-	update := bson.M{"$set": translatedFish}
+	// Find the creation time of the newest entry that no longer fits
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(int64(maxEntries))
+	var cutoff data.LLMCacheEntry
+	if err := collection.FindOne(ctx, bson.M{}, opts).Decode(&cutoff); err != nil {
+		return removed, fmt.Errorf("failed to find oldest LLM cache entries: %v", err)
+	}
 
-	// Find the fish by ID and update it
-	_, err = collection.UpdateOne(ctx, filter, update)
+	evicted, err := collection.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lte": cutoff.CreatedAt}})
 	if err != nil {
-		return fmt.Errorf("error updating fish with translation: %w", err)
+		return removed, fmt.Errorf("failed to evict LLM cache entries: %v", err)
 	}
-	return nil
+	return removed + int(evicted.DeletedCount), nil
 }
 
-// validateAndSanitizeMap validates and sanitizes all string fields in a map recursively
-func validateAndSanitizeMap(dataMap map[string]interface{}) error {
-	for key, value := range dataMap {
-		// Skip _id and other non-string special fields
-		if key == "_id" {
-			continue
-		}
-
-		switch v := value.(type) {
-		case string:
-			// Check if the string is valid UTF-8
-			if !utf8.ValidString(v) {
-				log.Printf("Found invalid UTF-8 in field '%s', sanitizing it", key)
-
-				// Apply the SanitizeUTF8 function from data package
-				sanitized := data.SanitizeUTF8(v)
-
-				// Update the map with the sanitized value
-				dataMap[key] = sanitized
+// SaveLLMUsage records a single LLM call
+func (m *MongoDB) SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error {
+	if _, err := m.collection(llmUsageCollection).InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("failed to save LLM usage: %v", err)
+	}
+	return nil
+}
 
-				// Log the before and after for debugging
-				log.Printf("Sanitized '%s': '%s' -> '%s'", key, v, sanitized)
-			}
+// GetLLMUsageTotals aggregates LLM calls made in [from, to) per UTC day, task and model
+func (m *MongoDB) GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error) {
+	countOutcomes := func(outcomes ...string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$outcome", outcomes}}, 1, 0}}}
+	}
 
-			// Extra validation for Vietnamese strings (fields ending with _vi)
-			if strings.HasSuffix(key, "_vi") {
-				// Check for problematic Vietnamese characters
-				for _, ch := range v {
-					// If character is outside the normal Vietnamese range and not ASCII
-					if ch > 0x1000 && !isValidVietnameseChar(ch) {
-						log.Printf("Found potentially problematic character %U in Vietnamese field '%s'", ch, key)
-
-						// Replace with sanitized version
-						dataMap[key] = data.SanitizeUTF8(v)
-						break
-					}
-				}
-			}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"timestamp": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"date":  bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$timestamp"}},
+				"task":  "$task",
+				"model": "$model",
+			},
+			"calls":           bson.M{"$sum": 1},
+			"cached_calls":    countOutcomes(data.LLMOutcomeCached),
+			"failed_calls":    countOutcomes(data.LLMOutcomeError, data.LLMOutcomeInvalid),
+			"prompt_tokens":   bson.M{"$sum": "$prompt_tokens"},
+			"response_tokens": bson.M{"$sum": "$response_tokens"},
+			"avg_latency_ms":  bson.M{"$avg": "$latency_ms"},
+			"cost_usd":        bson.M{"$sum": "$cost_usd"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":             0,
+			"date":            "$_id.date",
+			"task":            "$_id.task",
+			"model":           "$_id.model",
+			"calls":           1,
+			"cached_calls":    1,
+			"failed_calls":    1,
+			"prompt_tokens":   1,
+			"response_tokens": 1,
+			"total_tokens":    bson.M{"$add": bson.A{"$prompt_tokens", "$response_tokens"}},
+			"avg_latency_ms":  1,
+			"cost_usd":        1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}, {Key: "task", Value: 1}, {Key: "model", Value: 1}}}},
+	}
+
+	cursor, err := m.collection(llmUsageCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate LLM usage: %v", err)
+	}
+	defer cursor.Close(ctx)
 
-		case []interface{}:
-			// Handle arrays
-			for i, item := range v {
-				if itemMap, ok := item.(map[string]interface{}); ok {
-					err := validateAndSanitizeMap(itemMap)
-					if err != nil {
-						return err
-					}
-					// Update the array item
-					v[i] = itemMap
-				} else if itemStr, ok := item.(string); ok {
-					// Sanitize string array items
-					if !utf8.ValidString(itemStr) {
-						v[i] = data.SanitizeUTF8(itemStr)
-					}
-				}
-			}
+	totals := make([]data.LLMUsageTotal, 0)
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, fmt.Errorf("failed to decode LLM usage: %v", err)
+	}
+	return totals, nil
+}
 
-		case map[string]interface{}:
-			// Recursively validate nested maps
-			err := validateAndSanitizeMap(v)
-			if err != nil {
-				return err
-			}
-		}
+// IncrementExperimentCounter adds one to a counter of an experiment variant
+func (m *MongoDB) IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error {
+	if !data.IsExperimentCounter(counter) {
+		return fmt.Errorf("unknown experiment counter %q", counter)
 	}
 
+	_, err := m.collection(experimentCollection).UpdateOne(ctx,
+		bson.M{"experiment": experiment, "variant": variant},
+		bson.M{"$inc": bson.M{counter: 1}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to update experiment counter: %v", err)
+	}
 	return nil
 }
 
-// isValidVietnameseChar checks if a character is valid for Vietnamese text
-// This covers the Vietnamese-specific characters in Unicode
-func isValidVietnameseChar(r rune) bool {
-	// Basic Latin (includes ASCII)
-	if r <= 0x007F {
-		return true
+// GetExperimentCounts returns the counters of every variant of an experiment, sorted by variant
+func (m *MongoDB) GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error) {
+	cursor, err := m.collection(experimentCollection).Find(ctx, bson.M{"experiment": experiment},
+		options.Find().SetSort(bson.D{{Key: "variant", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query experiment counters: %v", err)
 	}
+	defer cursor.Close(ctx)
 
-	// Latin-1 Supplement (includes à, é, etc.)
-	if r >= 0x00A0 && r <= 0x00FF {
-		return true
+	counts := make([]data.ExperimentVariantCounts, 0)
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode experiment counters: %v", err)
 	}
+	return counts, nil
+}
 
-	// Latin Extended-A (includes đ)
-	if r >= 0x0100 && r <= 0x017F {
-		return true
+// IncrementAPIQuotaUsage adds one call to a source in a quota window
+func (m *MongoDB) IncrementAPIQuotaUsage(ctx context.Context, source, window string) error {
+	_, err := m.collection(apiQuotaCollection).UpdateOne(ctx,
+		bson.M{"source": source, "window": window},
+		bson.M{"$inc": bson.M{"calls": 1}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to update API quota usage: %v", err)
 	}
+	return nil
+}
 
-	// Latin Extended-B
-	if r >= 0x0180 && r <= 0x024F {
-		return true
+// GetAPIQuotaUsage returns the calls made to a source in a quota window
+func (m *MongoDB) GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error) {
+	var usage data.APIQuotaUsage
+	err := m.collection(apiQuotaCollection).FindOne(ctx, bson.M{"source": source, "window": window}).Decode(&usage)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil // No calls yet
+		}
+		return 0, fmt.Errorf("failed to retrieve API quota usage: %v", err)
 	}
+	return usage.Calls, nil
+}
 
-	// Latin Extended Additional (includes Vietnamese combining characters)
-	if r >= 0x1E00 && r <= 0x1EFF {
-		return true
+// SaveFishAuditEntry records an admin action on a fish
+func (m *MongoDB) SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error {
+	if _, err := m.collection(fishAuditCollection).InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to save fish audit entry: %v", err)
 	}
+	return nil
+}
 
-	// General Punctuation
-	if r >= 0x2000 && r <= 0x206F {
-		return true
+// GetFishAuditEntries returns the audit trail of a fish, or of every fish when fishID is empty, newest first
+func (m *MongoDB) GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error) {
+	filter := bson.M{}
+	if fishID != "" {
+		filter["fish_id"] = fishID
 	}
 
-	// Currency Symbols
-	if r >= 0x20A0 && r <= 0x20CF {
-		return true
+	findOptions := options.Find().SetSort(bson.D{{Key: "at", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
 	}
 
-	// Letterlike Symbols
-	if r >= 0x2100 && r <= 0x214F {
-		return true
+	cursor, err := m.collection(fishAuditCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fish audit entries: %v", err)
 	}
+	defer cursor.Close(ctx)
 
-	// Common CJK characters that might be used
-	if r >= 0x3000 && r <= 0x30FF {
-		return true
+	entries := make([]*data.FishAuditEntry, 0)
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode fish audit entries: %v", err)
 	}
-
-	return false
+	return entries, nil
 }
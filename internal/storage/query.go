@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"fish-generate/internal/data"
+)
+
+// FishSortField is a field the fish catalog can be sorted by
+type FishSortField string
+
+const (
+	SortByGeneratedAt FishSortField = "generated_at"
+	SortByValue       FishSortField = "value"
+	SortBySize        FishSortField = "size"
+	SortByRarity      FishSortField = "rarity"
+)
+
+const (
+	defaultFishPageSize = 20
+	maxFishPageSize     = 100
+)
+
+// ErrInvalidFishQuery is returned when a query has an unknown sort field or a malformed cursor
+var ErrInvalidFishQuery = errors.New("invalid fish query")
+
+// FishQuery describes a filtered, sorted page of the fish catalog.
+// Zero values mean "no filter"; range bounds are inclusive except GeneratedBefore.
+type FishQuery struct {
+	Rarities        []string
+	RegionID        string
+	DataSource      string
+	IsAIGenerated   *bool
+	GeneratedAfter  time.Time
+	GeneratedBefore time.Time
+	MinLength       float64
+	MaxLength       float64
+	MinWeight       float64
+	MaxWeight       float64
+	FavoriteWeather string
+	Experiment      string   // Prompt experiment the fish was generated in
+	Variant         string   // Variant of that experiment
+	Statuses        []string // Publish statuses to include; defaults to published fish only
+
+	SortBy     FishSortField // Defaults to SortByGeneratedAt
+	Descending bool
+	Limit      int    // Defaults to 20, capped at 100
+	Cursor     string // NextCursor from the previous page
+}
+
+// FishPage is one page of fish catalog results
+type FishPage struct {
+	Fish       []*data.FishRecord `json:"fish"`
+	NextCursor string             `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// fishCursor marks the last fish of a page. Pages are ordered by the sort key and then by ID,
+// so the next page starts strictly after (Key, ID) even when many fish share a key.
+type fishCursor struct {
+	SortBy     FishSortField `json:"s"`
+	Descending bool          `json:"d"`
+	Key        float64       `json:"k"`
+	ID         string        `json:"id"`
+}
+
+// normalize validates the query and fills in defaults
+func (q FishQuery) normalize() (FishQuery, error) {
+	switch q.SortBy {
+	case "":
+		q.SortBy = SortByGeneratedAt
+	case SortByGeneratedAt, SortByValue, SortBySize, SortByRarity:
+	default:
+		return q, fmt.Errorf("%w: unsupported sort field '%s'", ErrInvalidFishQuery, q.SortBy)
+	}
+
+	if len(q.Statuses) == 0 {
+		q.Statuses = []string{data.FishStatusPublished}
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultFishPageSize
+	} else if q.Limit > maxFishPageSize {
+		q.Limit = maxFishPageSize
+	}
+
+	return q, nil
+}
+
+// after decodes the query cursor, returning nil for the first page
+func (q FishQuery) after() (*fishCursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor: %v", ErrInvalidFishQuery, err)
+	}
+
+	var cursor fishCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor: %v", ErrInvalidFishQuery, err)
+	}
+	if cursor.SortBy != q.SortBy || cursor.Descending != q.Descending {
+		return nil, fmt.Errorf("%w: cursor does not match the requested sort order", ErrInvalidFishQuery)
+	}
+
+	return &cursor, nil
+}
+
+// cursorFor encodes a cursor pointing just past the given fish
+func (q FishQuery) cursorFor(fish *FishData) string {
+	raw, _ := json.Marshal(fishCursor{
+		SortBy:     q.SortBy,
+		Descending: q.Descending,
+		Key:        fishSortKey(fish, q.SortBy),
+		ID:         fish.ID.Hex(),
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// fishSortKey returns the numeric sort key of a fish; dates use Unix milliseconds
+func fishSortKey(fish *FishData, sortBy FishSortField) float64 {
+	switch sortBy {
+	case SortByValue:
+		return fish.Value
+	case SortBySize:
+		return fish.Length
+	case SortByRarity:
+		return float64(data.RarityRank(fish.Rarity))
+	default:
+		return float64(fish.GeneratedAt.UnixMilli())
+	}
+}
+
+// matches reports whether a fish passes every filter of the query
+func (q FishQuery) matches(fish *FishData) bool {
+	if len(q.Rarities) > 0 && !containsString(q.Rarities, fish.Rarity) {
+		return false
+	}
+	if q.RegionID != "" && fish.RegionID != q.RegionID {
+		return false
+	}
+	if q.DataSource != "" && fish.DataSource != q.DataSource {
+		return false
+	}
+	if q.IsAIGenerated != nil && fish.IsAIGenerated != *q.IsAIGenerated {
+		return false
+	}
+	if !q.GeneratedAfter.IsZero() && fish.GeneratedAt.Before(q.GeneratedAfter) {
+		return false
+	}
+	if !q.GeneratedBefore.IsZero() && !fish.GeneratedAt.Before(q.GeneratedBefore) {
+		return false
+	}
+	if q.MinLength > 0 && fish.Length < q.MinLength {
+		return false
+	}
+	if q.MaxLength > 0 && fish.Length > q.MaxLength {
+		return false
+	}
+	if q.MinWeight > 0 && fish.Weight < q.MinWeight {
+		return false
+	}
+	if q.MaxWeight > 0 && fish.Weight > q.MaxWeight {
+		return false
+	}
+	if q.FavoriteWeather != "" && fish.FavoriteWeather != q.FavoriteWeather {
+		return false
+	}
+	if q.Experiment != "" && fish.Experiment != q.Experiment {
+		return false
+	}
+	if q.Variant != "" && fish.Variant != q.Variant {
+		return false
+	}
+	if !containsString(q.Statuses, fish.Status) {
+		return false
+	}
+	return true
+}
+
+// newFishPage trims the limit+1 rows fetched by a backend into a page and its cursor
+func newFishPage(query FishQuery, rows []*FishData) *FishPage {
+	page := &FishPage{Fish: []*data.FishRecord{}}
+	if len(rows) > query.Limit {
+		rows = rows[:query.Limit]
+		page.NextCursor = query.cursorFor(rows[len(rows)-1])
+	}
+	page.Fish = fishRecords(rows)
+	return page
+}
+
+// speciesSummaries reduces stored fish to what the uniqueness guard compares
+func speciesSummaries(rows []*FishData) []data.SpeciesSummary {
+	summaries := make([]data.SpeciesSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, data.SpeciesSummary{
+			ID:          row.ID.Hex(),
+			Name:        row.Name,
+			Description: row.Description,
+			VariantOf:   row.VariantOf,
+		})
+	}
+	return summaries
+}
+
+// fishRecordFields lists the stored document fields that come from data.FishRecord.
+// Updates replace these and leave the rest of a document, like translations, alone.
+var fishRecordFields = bsonFieldNames(reflect.TypeOf(data.FishRecord{}))
+
+// bsonFieldNames returns the BSON names of the fields of a struct type
+func bsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("bson"), ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// containsString reports whether value is in items
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoFishSortField maps a sort field to the stored document field
+func mongoFishSortField(sortBy FishSortField) string {
+	switch sortBy {
+	case SortByValue:
+		return "value"
+	case SortBySize:
+		return "length"
+	case SortByRarity:
+		return "rarity_rank"
+	default:
+		return "generated_at"
+	}
+}
@@ -0,0 +1,1484 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fish-generate/internal/data"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, works with CGO_ENABLED=0
+)
+
+// sqliteTimeLayout is a fixed-width UTC layout so timestamps sort correctly as text
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// sqliteMigration is a single versioned schema change
+type sqliteMigration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+// sqliteMigrations lists all schema changes in order. Never edit an applied
+// migration; append a new one instead.
+var sqliteMigrations = []sqliteMigration{
+	{
+		Version: 1,
+		Name:    "initial_schema",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS weather (
+				id TEXT PRIMARY KEY,
+				region_id TEXT NOT NULL,
+				city_id TEXT NOT NULL,
+				condition TEXT NOT NULL DEFAULT '',
+				temp_c REAL NOT NULL DEFAULT 0,
+				humidity REAL NOT NULL DEFAULT 0,
+				wind_speed REAL NOT NULL DEFAULT 0,
+				rain_mm REAL NOT NULL DEFAULT 0,
+				pressure REAL NOT NULL DEFAULT 0,
+				clouds INTEGER NOT NULL DEFAULT 0,
+				description TEXT NOT NULL DEFAULT '',
+				timestamp TEXT NOT NULL,
+				source TEXT NOT NULL DEFAULT '',
+				UNIQUE (region_id, city_id)
+			)`,
+			`CREATE TABLE IF NOT EXISTS prices (
+				id TEXT PRIMARY KEY,
+				asset_type TEXT NOT NULL UNIQUE,
+				price REAL NOT NULL DEFAULT 0,
+				volume REAL NOT NULL DEFAULT 0,
+				change_percent REAL NOT NULL DEFAULT 0,
+				volume_change REAL NOT NULL DEFAULT 0,
+				timestamp TEXT NOT NULL,
+				source TEXT NOT NULL DEFAULT ''
+			)`,
+			`CREATE TABLE IF NOT EXISTS news (
+				id TEXT PRIMARY KEY,
+				headline TEXT NOT NULL,
+				content TEXT NOT NULL DEFAULT '',
+				source TEXT NOT NULL,
+				url TEXT NOT NULL DEFAULT '',
+				published_at TEXT NOT NULL,
+				sentiment REAL NOT NULL DEFAULT 0,
+				keywords TEXT NOT NULL DEFAULT '[]',
+				timestamp TEXT NOT NULL,
+				UNIQUE (source, headline)
+			)`,
+			`CREATE TABLE IF NOT EXISTS fish (
+				id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				rarity TEXT NOT NULL DEFAULT '',
+				length REAL NOT NULL DEFAULT 0,
+				weight REAL NOT NULL DEFAULT 0,
+				color TEXT NOT NULL DEFAULT '',
+				habitat TEXT NOT NULL DEFAULT '',
+				diet TEXT NOT NULL DEFAULT '',
+				generated_at TEXT NOT NULL,
+				is_ai_generated INTEGER NOT NULL DEFAULT 0,
+				data_source TEXT NOT NULL DEFAULT '',
+				region_id TEXT NOT NULL DEFAULT '',
+				favorite_weather TEXT NOT NULL DEFAULT '',
+				catch_chance REAL NOT NULL DEFAULT 0,
+				existence_reason TEXT NOT NULL DEFAULT '',
+				stat_effects TEXT NOT NULL DEFAULT '[]',
+				generation_reason TEXT NOT NULL DEFAULT '',
+				used_articles TEXT NOT NULL DEFAULT '[]',
+				is_translated INTEGER NOT NULL DEFAULT 0,
+				extra_fields TEXT NOT NULL DEFAULT '{}'
+			)`,
+			`CREATE TABLE IF NOT EXISTS daily_fish_counts (
+				date TEXT PRIMARY KEY,
+				count INTEGER NOT NULL DEFAULT 0,
+				last_updated TEXT NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS used_news (
+				news_id TEXT PRIMARY KEY,
+				used_at TEXT NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS generation_queue (
+				id TEXT PRIMARY KEY,
+				reason TEXT NOT NULL DEFAULT '',
+				added_at TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'pending'
+			)`,
+			`CREATE TABLE IF NOT EXISTS translated_fish (
+				id TEXT PRIMARY KEY,
+				original_id TEXT NOT NULL,
+				language TEXT NOT NULL,
+				name TEXT NOT NULL DEFAULT '',
+				description TEXT NOT NULL DEFAULT '',
+				appearance TEXT NOT NULL DEFAULT '',
+				color TEXT NOT NULL DEFAULT '',
+				diet TEXT NOT NULL DEFAULT '',
+				habitat TEXT NOT NULL DEFAULT '',
+				effect TEXT NOT NULL DEFAULT '',
+				favorite_weather TEXT NOT NULL DEFAULT '',
+				existence_reason TEXT NOT NULL DEFAULT '',
+				translated_at TEXT NOT NULL,
+				UNIQUE (original_id, language)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_weather_region_timestamp ON weather (region_id, timestamp DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_news_timestamp ON news (timestamp DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_generated_region ON fish (generated_at DESC, region_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_source_generated ON fish (data_source, generated_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_translated ON fish (is_translated, generated_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_queue_added_status ON generation_queue (added_at, status)`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "fish_record_fields",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN appearance TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE fish ADD COLUMN value REAL NOT NULL DEFAULT 0`,
+			`ALTER TABLE fish ADD COLUMN effect TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE fish ADD COLUMN origin_context TEXT NOT NULL DEFAULT ''`,
+			// Older rows never stored a value; use the generator's length * 10 * rarity formula
+			`UPDATE fish SET value = ROUND(length * 10 * CASE rarity
+				WHEN 'Uncommon' THEN 3 WHEN 'Rare' THEN 6 WHEN 'Epic' THEN 12 WHEN 'Legendary' THEN 25
+				ELSE 1 END, 2)`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "fish_catalog",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN rarity_rank INTEGER NOT NULL DEFAULT 0`,
+			`UPDATE fish SET rarity_rank = CASE rarity
+				WHEN 'Common' THEN 1 WHEN 'Uncommon' THEN 2 WHEN 'Rare' THEN 3 WHEN 'Epic' THEN 4
+				WHEN 'Legendary' THEN 5 ELSE 0 END`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_generated_id ON fish (generated_at DESC, id DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_value_id ON fish (value DESC, id DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_length_id ON fish (length DESC, id DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_rarity_rank_id ON fish (rarity_rank DESC, id DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_region_rarity ON fish (region_id, rarity, generated_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_source_rarity ON fish (data_source, rarity, generated_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_weather ON fish (favorite_weather, generated_at DESC)`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "llm_cache",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS llm_cache (
+				key TEXT PRIMARY KEY,
+				provider TEXT NOT NULL DEFAULT '',
+				model TEXT NOT NULL DEFAULT '',
+				options TEXT NOT NULL DEFAULT '',
+				prompt TEXT NOT NULL DEFAULT '',
+				response TEXT NOT NULL DEFAULT '',
+				prompt_tokens INTEGER NOT NULL DEFAULT 0,
+				response_tokens INTEGER NOT NULL DEFAULT 0,
+				hits INTEGER NOT NULL DEFAULT 0,
+				created_at TEXT NOT NULL,
+				last_hit_at TEXT NOT NULL DEFAULT '',
+				expires_at TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_llm_cache_expires ON llm_cache (expires_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_llm_cache_created ON llm_cache (created_at DESC)`,
+		},
+	},
+	{
+		Version: 5,
+		Name:    "llm_usage",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS llm_usage (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				timestamp TEXT NOT NULL,
+				task TEXT NOT NULL DEFAULT '',
+				provider TEXT NOT NULL DEFAULT '',
+				model TEXT NOT NULL DEFAULT '',
+				prompt_tokens INTEGER NOT NULL DEFAULT 0,
+				response_tokens INTEGER NOT NULL DEFAULT 0,
+				latency_ms INTEGER NOT NULL DEFAULT 0,
+				outcome TEXT NOT NULL DEFAULT '',
+				error TEXT NOT NULL DEFAULT '',
+				cost_usd REAL NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_llm_usage_timestamp ON llm_usage (timestamp)`,
+		},
+	},
+	{
+		Version: 6,
+		Name:    "fish_prompt_version",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN prompt_version TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_prompt_version ON fish (prompt_version, generated_at DESC)`,
+		},
+	},
+	{
+		Version: 7,
+		Name:    "prompt_experiments",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN experiment TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE fish ADD COLUMN experiment_variant TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_experiment ON fish (experiment, experiment_variant, generated_at DESC)`,
+			`CREATE TABLE IF NOT EXISTS experiment_counters (
+				experiment TEXT NOT NULL,
+				variant TEXT NOT NULL,
+				generations INTEGER NOT NULL DEFAULT 0,
+				parse_failures INTEGER NOT NULL DEFAULT 0,
+				catches INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (experiment, variant)
+			)`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "fish_variant_of",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN variant_of TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_variant_of ON fish (variant_of)`,
+		},
+	},
+	{
+		Version: 9,
+		Name:    "fish_moderation_status",
+		Statements: []string{
+			`ALTER TABLE fish ADD COLUMN status TEXT NOT NULL DEFAULT 'published'`,
+			`ALTER TABLE fish ADD COLUMN moderation_flags TEXT NOT NULL DEFAULT '[]'`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_status_generated ON fish (status, generated_at DESC)`,
+		},
+	},
+	{
+		Version: 10,
+		Name:    "fish_audit",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS fish_audit (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				fish_id TEXT NOT NULL,
+				fish_name TEXT NOT NULL DEFAULT '',
+				admin TEXT NOT NULL DEFAULT '',
+				action TEXT NOT NULL,
+				changes TEXT NOT NULL DEFAULT '[]',
+				reason TEXT NOT NULL DEFAULT '',
+				at TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_audit_fish_at ON fish_audit (fish_id, at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_fish_audit_at ON fish_audit (at DESC)`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "api_quota",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS api_quota (
+				source TEXT NOT NULL,
+				quota_window TEXT NOT NULL,
+				calls INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (source, quota_window)
+			)`,
+		},
+	},
+	{
+		Version: 12,
+		Name:    "region_weather",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS region_weather (
+				region_id TEXT PRIMARY KEY,
+				condition TEXT NOT NULL DEFAULT '',
+				temp_c REAL NOT NULL DEFAULT 0,
+				humidity INTEGER NOT NULL DEFAULT 0,
+				wind_kph REAL NOT NULL DEFAULT 0,
+				is_extreme INTEGER NOT NULL DEFAULT 0,
+				cities INTEGER NOT NULL DEFAULT 0,
+				updated_at TEXT NOT NULL
+			)`,
+		},
+	},
+}
+
+// SQLiteDB implements DatabaseClient using an embedded SQLite database
+type SQLiteDB struct {
+	db   *sql.DB
+	path string
+}
+
+// NewSQLiteDB opens (or creates) the SQLite database at path and applies pending migrations
+func NewSQLiteDB(path string) (*SQLiteDB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create SQLite directory: %v", err)
+		}
+	}
+
+	// WAL lets the API read while collectors write; busy_timeout avoids spurious "database is locked" errors
+	dsn := fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %v", err)
+	}
+
+	// SQLite allows a single writer; serialising connections keeps writes simple and safe
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping SQLite database: %v", err)
+	}
+
+	s := &SQLiteDB{db: db, path: path}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("SQLite database ready at %s", path)
+	return s, nil
+}
+
+// Close closes the database connection
+func (s *SQLiteDB) Close(ctx context.Context) error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("failed to close SQLite database: %v", err)
+	}
+	log.Println("Closed SQLite database")
+	return nil
+}
+
+// migrate applies every migration newer than the recorded schema version.
+// Each migration runs in its own transaction together with its version record,
+// so a failed upgrade leaves the database at the previous version.
+func (s *SQLiteDB) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+
+	var current int
+	row := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`)
+	if err := row.Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %v", err)
+	}
+
+	for _, migration := range sqliteMigrations {
+		if migration.Version <= current {
+			continue
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %v", migration.Version, err)
+		}
+
+		for _, stmt := range migration.Statements {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			migration.Version, migration.Name, formatSQLiteTime(time.Now()))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %v", migration.Version, err)
+		}
+
+		log.Printf("Applied SQLite migration %d: %s", migration.Version, migration.Name)
+	}
+
+	return nil
+}
+
+// SaveWeatherData saves weather data, replacing the previous reading for the same region and city
+func (s *SQLiteDB) SaveWeatherData(ctx context.Context, weatherInfo *data.WeatherInfo, regionID, cityID string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO weather (id, region_id, city_id, condition, temp_c, timestamp, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (region_id, city_id) DO UPDATE SET
+			condition = excluded.condition,
+			temp_c = excluded.temp_c,
+			timestamp = excluded.timestamp,
+			source = excluded.source`,
+		primitive.NewObjectID().Hex(), regionID, cityID, weatherInfo.Condition, weatherInfo.TempC,
+		formatSQLiteTime(time.Now()), "internal")
+	if err != nil {
+		return fmt.Errorf("failed to upsert weather data: %v", err)
+	}
+	return nil
+}
+
+// SavePriceData saves or updates price data for an asset type
+func (s *SQLiteDB) SavePriceData(ctx context.Context, assetType string, price, volume, changePercent, volumeChange float64, source string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO prices (id, asset_type, price, volume, change_percent, volume_change, timestamp, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (asset_type) DO UPDATE SET
+			price = excluded.price,
+			volume = excluded.volume,
+			change_percent = excluded.change_percent,
+			volume_change = excluded.volume_change,
+			timestamp = excluded.timestamp,
+			source = excluded.source`,
+		primitive.NewObjectID().Hex(), assetType, price, volume, changePercent, volumeChange,
+		formatSQLiteTime(time.Now()), source)
+	if err != nil {
+		return fmt.Errorf("failed to upsert price data: %v", err)
+	}
+	return nil
+}
+
+// SaveNewsData saves news data, replacing any item with the same source and headline
+func (s *SQLiteDB) SaveNewsData(ctx context.Context, newsItem *data.NewsItem) error {
+	keywords, err := json.Marshal(newsItem.Keywords)
+	if err != nil {
+		return fmt.Errorf("failed to encode news keywords: %v", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO news (id, headline, content, source, url, published_at, sentiment, keywords, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (source, headline) DO UPDATE SET
+			content = excluded.content,
+			url = excluded.url,
+			published_at = excluded.published_at,
+			sentiment = excluded.sentiment,
+			keywords = excluded.keywords,
+			timestamp = excluded.timestamp`,
+		primitive.NewObjectID().Hex(), newsItem.Headline, newsItem.GetContent(), newsItem.Source, newsItem.URL,
+		formatSQLiteTime(newsItem.PublishedAt), newsItem.Sentiment, string(keywords), formatSQLiteTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to upsert news data: %v", err)
+	}
+
+	log.Printf("News data saved from source '%s': '%s'",
+		newsItem.Source, truncateString(newsItem.Headline, 50))
+	return nil
+}
+
+// SaveFishData saves a fish record and sets its ID
+func (s *SQLiteDB) SaveFishData(ctx context.Context, fish *data.FishRecord) error {
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return err
+	}
+
+	statEffects, usedArticles, moderationFlags, err := encodeFishLists(fishData)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin fish transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO fish (id, name, description, rarity, length, weight, color, habitat, diet,
+			generated_at, is_ai_generated, data_source, region_id, favorite_weather, catch_chance,
+			existence_reason, stat_effects, generation_reason, used_articles, appearance, value,
+			effect, origin_context, rarity_rank, prompt_version, experiment, experiment_variant, variant_of,
+			status, moderation_flags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		fishData.ID.Hex(), fishData.Name, fishData.Description, fishData.Rarity, fishData.Length, fishData.Weight,
+		fishData.Color, fishData.Habitat, fishData.Diet, formatSQLiteTime(fishData.GeneratedAt),
+		fishData.IsAIGenerated, fishData.DataSource, fishData.RegionID, fishData.FavoriteWeather,
+		fishData.CatchChance, fishData.ExistenceReason, statEffects, fishData.GenerationReason,
+		usedArticles, fishData.Appearance, fishData.Value, fishData.Effect, fishData.OriginContext,
+		fishData.RarityRank, fishData.PromptVersion, fishData.Experiment, fishData.Variant, fishData.VariantOf,
+		fishData.Status, moderationFlags)
+	if err != nil {
+		return fmt.Errorf("failed to insert fish data: %v", err)
+	}
+
+	// Increment daily fish count
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO daily_fish_counts (date, count, last_updated) VALUES (?, 1, ?)
+		ON CONFLICT (date) DO UPDATE SET count = count + 1, last_updated = excluded.last_updated`,
+		time.Now().Format("2006-01-02"), formatSQLiteTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to update fish limit record: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fish data: %v", err)
+	}
+
+	fish.ID = fishData.ID.Hex()
+	fish.GeneratedAt = fishData.GeneratedAt
+	fish.Status = fishData.Status
+	log.Printf("Fish data saved: %s (ID: %s)", fishData.Name, fish.ID)
+	return nil
+}
+
+// UpdateFishData replaces the stored fields of an existing fish record. When status is set,
+// the stored fish must still have it, or ErrFishStatusChanged is returned.
+func (s *SQLiteDB) UpdateFishData(ctx context.Context, fish *data.FishRecord, status string) error {
+	if fish == nil || fish.ID == "" {
+		return fmt.Errorf("fish to update has no ID")
+	}
+	fishData, err := newFishData(fish)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFishNotFound, err)
+	}
+
+	statEffects, usedArticles, moderationFlags, err := encodeFishLists(fishData)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE fish SET name = ?, description = ?, rarity = ?, length = ?, weight = ?, color = ?, habitat = ?,
+			diet = ?, generated_at = ?, is_ai_generated = ?, data_source = ?, region_id = ?, favorite_weather = ?,
+			catch_chance = ?, existence_reason = ?, stat_effects = ?, generation_reason = ?, used_articles = ?,
+			appearance = ?, value = ?, effect = ?, origin_context = ?, rarity_rank = ?, prompt_version = ?,
+			experiment = ?, experiment_variant = ?, variant_of = ?, status = ?, moderation_flags = ?
+		WHERE id = ? AND (? = '' OR status = ?)`,
+		fishData.Name, fishData.Description, fishData.Rarity, fishData.Length, fishData.Weight,
+		fishData.Color, fishData.Habitat, fishData.Diet, formatSQLiteTime(fishData.GeneratedAt),
+		fishData.IsAIGenerated, fishData.DataSource, fishData.RegionID, fishData.FavoriteWeather,
+		fishData.CatchChance, fishData.ExistenceReason, statEffects, fishData.GenerationReason,
+		usedArticles, fishData.Appearance, fishData.Value, fishData.Effect, fishData.OriginContext,
+		fishData.RarityRank, fishData.PromptVersion, fishData.Experiment, fishData.Variant, fishData.VariantOf,
+		fishData.Status, moderationFlags, fishData.ID.Hex(), status, status)
+	if err != nil {
+		return fmt.Errorf("failed to update fish data: %v", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		var current string
+		err := s.db.QueryRowContext(ctx, "SELECT status FROM fish WHERE id = ?", fishData.ID.Hex()).Scan(&current)
+		if err == nil && status != "" {
+			return fmt.Errorf("%w: %s is %s, not %s", ErrFishStatusChanged, fish.ID, current, status)
+		}
+		return fmt.Errorf("%w: %s", ErrFishNotFound, fish.ID)
+	}
+
+	fish.Status = fishData.Status
+	return nil
+}
+
+// encodeFishLists encodes the list fields of a fish as JSON for their TEXT columns
+func encodeFishLists(fishData *FishData) (statEffects, usedArticles, moderationFlags string, err error) {
+	for _, field := range []struct {
+		name   string
+		value  interface{}
+		target *string
+	}{
+		{"stat effects", nonNilSlice(fishData.StatEffects), &statEffects},
+		{"used articles", nonNilSlice(fishData.UsedArticles), &usedArticles},
+		{"moderation flags", nonNilSlice(fishData.ModerationFlags), &moderationFlags},
+	} {
+		raw, err := json.Marshal(field.value)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to encode %s: %v", field.name, err)
+		}
+		*field.target = string(raw)
+	}
+	return statEffects, usedArticles, moderationFlags, nil
+}
+
+// GetRecentWeatherData retrieves recent weather data for a specific region
+func (s *SQLiteDB) GetRecentWeatherData(ctx context.Context, regionID string, limit int) ([]*WeatherData, error) {
+	query := `SELECT id, region_id, city_id, condition, temp_c, humidity, wind_speed, rain_mm,
+		pressure, clouds, description, timestamp, source FROM weather`
+	var args []interface{}
+	if regionID != "" {
+		query += ` WHERE region_id = ?`
+		args = append(args, regionID)
+	}
+	query += ` ORDER BY timestamp DESC` + sqliteLimit(limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find weather data: %v", err)
+	}
+	defer rows.Close()
+
+	var results []*WeatherData
+	for rows.Next() {
+		var item WeatherData
+		var id, timestamp string
+		if err := rows.Scan(&id, &item.RegionID, &item.CityID, &item.Condition, &item.TempC, &item.Humidity,
+			&item.WindSpeed, &item.RainMM, &item.Pressure, &item.Clouds, &item.Description, &timestamp,
+			&item.Source); err != nil {
+			return nil, fmt.Errorf("failed to decode weather data: %v", err)
+		}
+		item.ID = parseSQLiteObjectID(id)
+		item.Timestamp = parseSQLiteTime(timestamp)
+		results = append(results, &item)
+	}
+
+	return results, rows.Err()
+}
+
+// SaveRegionWeather replaces the aggregated weather of a region
+func (s *SQLiteDB) SaveRegionWeather(ctx context.Context, weather *data.RegionalWeather) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO region_weather (region_id, condition, temp_c, humidity, wind_kph, is_extreme, cities, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (region_id) DO UPDATE SET
+			condition = excluded.condition, temp_c = excluded.temp_c, humidity = excluded.humidity,
+			wind_kph = excluded.wind_kph, is_extreme = excluded.is_extreme, cities = excluded.cities,
+			updated_at = excluded.updated_at`,
+		weather.RegionID, weather.Condition, weather.TempC, weather.Humidity, weather.WindKph,
+		weather.IsExtreme, weather.Cities, formatSQLiteTime(weather.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save region weather: %v", err)
+	}
+	return nil
+}
+
+// GetRegionWeather returns the aggregated weather of a region, or nil if none was saved
+func (s *SQLiteDB) GetRegionWeather(ctx context.Context, regionID string) (*data.RegionalWeather, error) {
+	var weather data.RegionalWeather
+	var updatedAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT region_id, condition, temp_c, humidity, wind_kph, is_extreme, cities, updated_at
+		FROM region_weather WHERE region_id = ?`, regionID).Scan(&weather.RegionID, &weather.Condition,
+		&weather.TempC, &weather.Humidity, &weather.WindKph, &weather.IsExtreme, &weather.Cities, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // No weather collected yet
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve region weather: %v", err)
+	}
+	weather.UpdatedAt = parseSQLiteTime(updatedAt)
+	return &weather, nil
+}
+
+// GetRecentPriceData retrieves recent price data for a specific asset type
+func (s *SQLiteDB) GetRecentPriceData(ctx context.Context, assetType string, limit int) ([]map[string]interface{}, error) {
+	query := `SELECT id, asset_type, price, volume, change_percent, volume_change, timestamp, source FROM prices`
+	var args []interface{}
+	if assetType != "" {
+		query += ` WHERE asset_type = ?`
+		args = append(args, assetType)
+	}
+	query += ` ORDER BY timestamp DESC` + sqliteLimit(limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find price data: %v", err)
+	}
+	defer rows.Close()
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		var item PriceData
+		var id, timestamp string
+		if err := rows.Scan(&id, &item.AssetType, &item.Price, &item.Volume, &item.ChangePercent,
+			&item.VolumeChange, &timestamp, &item.Source); err != nil {
+			return nil, fmt.Errorf("failed to decode price data: %v", err)
+		}
+
+		// Return the same keys MongoDB would produce for a decoded price document
+		results = append(results, map[string]interface{}{
+			"_id":            id,
+			"asset_type":     item.AssetType,
+			"price":          item.Price,
+			"volume":         item.Volume,
+			"change_percent": item.ChangePercent,
+			"volume_change":  item.VolumeChange,
+			"timestamp":      parseSQLiteTime(timestamp),
+			"source":         item.Source,
+		})
+	}
+
+	return results, rows.Err()
+}
+
+// GetRecentNewsData retrieves recent news data
+func (s *SQLiteDB) GetRecentNewsData(ctx context.Context, limit int) ([]*NewsData, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, headline, content, source, url, published_at,
+		sentiment, keywords, timestamp FROM news ORDER BY timestamp DESC`+sqliteLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find news data: %v", err)
+	}
+	defer rows.Close()
+
+	var results []*NewsData
+	for rows.Next() {
+		var item NewsData
+		var id, publishedAt, keywords, timestamp string
+		if err := rows.Scan(&id, &item.Headline, &item.Content, &item.Source, &item.URL, &publishedAt,
+			&item.Sentiment, &keywords, &timestamp); err != nil {
+			return nil, fmt.Errorf("failed to decode news data: %v", err)
+		}
+		item.ID = parseSQLiteObjectID(id)
+		item.PublishedAt = parseSQLiteTime(publishedAt)
+		item.Timestamp = parseSQLiteTime(timestamp)
+		if err := json.Unmarshal([]byte(keywords), &item.Keywords); err != nil {
+			log.Printf("Warning: invalid keywords for news %s: %v", id, err)
+		}
+		results = append(results, &item)
+	}
+
+	return results, rows.Err()
+}
+
+// sqliteFishColumns lists the fish columns in the order scanFish expects
+const sqliteFishColumns = `id, name, description, rarity, length, weight, color, habitat, diet,
+	generated_at, is_ai_generated, data_source, region_id, favorite_weather, catch_chance,
+	existence_reason, stat_effects, generation_reason, used_articles, is_translated, extra_fields,
+	appearance, value, effect, origin_context, rarity_rank, prompt_version, experiment, experiment_variant,
+	variant_of, status, moderation_flags`
+
+// sqliteFishRow is a fish row together with the fields only SQLite tracks separately
+type sqliteFishRow struct {
+	FishData
+	IsTranslated bool
+	ExtraFields  map[string]interface{}
+}
+
+// scanFish decodes a row selected with sqliteFishColumns
+func scanFish(rows *sql.Rows) (*sqliteFishRow, error) {
+	var row sqliteFishRow
+	var id, generatedAt, statEffects, usedArticles, extraFields, moderationFlags string
+	err := rows.Scan(&id, &row.Name, &row.Description, &row.Rarity, &row.Length, &row.Weight, &row.Color,
+		&row.Habitat, &row.Diet, &generatedAt, &row.IsAIGenerated, &row.DataSource, &row.RegionID,
+		&row.FavoriteWeather, &row.CatchChance, &row.ExistenceReason, &statEffects, &row.GenerationReason,
+		&usedArticles, &row.IsTranslated, &extraFields, &row.Appearance, &row.Value, &row.Effect, &row.OriginContext,
+		&row.RarityRank, &row.PromptVersion, &row.Experiment, &row.Variant, &row.VariantOf, &row.Status, &moderationFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode fish data: %v", err)
+	}
+
+	row.ID = parseSQLiteObjectID(id)
+	row.GeneratedAt = parseSQLiteTime(generatedAt)
+	if err := json.Unmarshal([]byte(statEffects), &row.StatEffects); err != nil {
+		return nil, fmt.Errorf("failed to decode stat effects for fish %s: %v", id, err)
+	}
+	if err := json.Unmarshal([]byte(usedArticles), &row.UsedArticles); err != nil {
+		return nil, fmt.Errorf("failed to decode used articles for fish %s: %v", id, err)
+	}
+	if err := json.Unmarshal([]byte(extraFields), &row.ExtraFields); err != nil {
+		return nil, fmt.Errorf("failed to decode extra fields for fish %s: %v", id, err)
+	}
+	if err := json.Unmarshal([]byte(moderationFlags), &row.ModerationFlags); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation flags for fish %s: %v", id, err)
+	}
+	if len(row.ModerationFlags) == 0 {
+		row.ModerationFlags = nil
+	}
+
+	return &row, nil
+}
+
+// queryFish runs a fish query and decodes every row
+func (s *SQLiteDB) queryFish(ctx context.Context, where string, args []interface{}, orderAndLimit string) ([]*sqliteFishRow, error) {
+	query := `SELECT ` + sqliteFishColumns + ` FROM fish`
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	query += ` ` + orderAndLimit
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fish data: %v", err)
+	}
+	defer rows.Close()
+
+	var results []*sqliteFishRow
+	for rows.Next() {
+		row, err := scanFish(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+
+	return results, rows.Err()
+}
+
+// fishDataSlice unwraps fish rows into FishData pointers
+func fishDataSlice(rows []*sqliteFishRow) []*FishData {
+	results := make([]*FishData, len(rows))
+	for i, row := range rows {
+		results[i] = &row.FishData
+	}
+	return results
+}
+
+// GetSimilarFish retrieves the newest fish matching the data source and rarity
+func (s *SQLiteDB) GetSimilarFish(ctx context.Context, dataSource string, rarityLevel string) (*FishData, error) {
+	conditions := []string{"status = ?"}
+	args := []interface{}{data.FishStatusPublished}
+	if dataSource != "" {
+		conditions = append(conditions, "data_source = ?")
+		args = append(args, dataSource)
+	}
+	if rarityLevel != "" {
+		conditions = append(conditions, "rarity = ?")
+		args = append(args, rarityLevel)
+	}
+
+	// If no filters were added, return error
+	if len(conditions) == 1 {
+		return nil, fmt.Errorf("at least one filter parameter (dataSource or rarityLevel) must be provided")
+	}
+
+	rows, err := s.queryFish(ctx, strings.Join(conditions, " AND "), args, "ORDER BY generated_at DESC LIMIT 1")
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("failed to find similar fish: no matching fish")
+	}
+
+	return &rows[0].FishData, nil
+}
+
+// GetFishByRegion retrieves fish for a specific region, including fish saved without a region
+func (s *SQLiteDB) GetFishByRegion(ctx context.Context, regionID string, limit int) ([]*FishData, error) {
+	// Default limit if not specified
+	if limit <= 0 {
+		limit = 10
+	}
+
+	where := "status = ?"
+	args := []interface{}{data.FishStatusPublished}
+	if regionID != "" {
+		where += " AND region_id IN (?, '')"
+		args = append(args, regionID)
+	}
+
+	rows, err := s.queryFish(ctx, where, args, "ORDER BY generated_at DESC"+sqliteLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return fishDataSlice(rows), nil
+}
+
+// GetFishByDataSource retrieves fish from a specific data source
+func (s *SQLiteDB) GetFishByDataSource(ctx context.Context, dataSource string, limit int) ([]*FishData, error) {
+	rows, err := s.queryFish(ctx, "data_source = ? AND status = ?", []interface{}{dataSource, data.FishStatusPublished},
+		"ORDER BY generated_at DESC"+sqliteLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return fishDataSlice(rows), nil
+}
+
+// GetSpeciesSummaries returns the ID, name and description of every stored fish
+func (s *SQLiteDB) GetSpeciesSummaries(ctx context.Context) ([]data.SpeciesSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, description, variant_of FROM fish`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query species: %v", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]data.SpeciesSummary, 0)
+	for rows.Next() {
+		var summary data.SpeciesSummary
+		if err := rows.Scan(&summary.ID, &summary.Name, &summary.Description, &summary.VariantOf); err != nil {
+			return nil, fmt.Errorf("failed to decode species: %v", err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// QueryFish returns fish matching the query in sort order, starting after the query cursor.
+// Up to query.Limit+1 fish are returned so the caller can tell whether another page exists.
+func (s *SQLiteDB) QueryFish(ctx context.Context, query FishQuery) ([]*FishData, error) {
+	cursor, err := query.after()
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, values ...interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	statuses := make([]interface{}, len(query.Statuses))
+	for i, status := range query.Statuses {
+		statuses[i] = status
+	}
+	add("status IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")+")", statuses...)
+
+	if len(query.Rarities) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(query.Rarities)), ", ")
+		values := make([]interface{}, len(query.Rarities))
+		for i, rarity := range query.Rarities {
+			values[i] = rarity
+		}
+		add("rarity IN ("+placeholders+")", values...)
+	}
+	if query.RegionID != "" {
+		add("region_id = ?", query.RegionID)
+	}
+	if query.DataSource != "" {
+		add("data_source = ?", query.DataSource)
+	}
+	if query.IsAIGenerated != nil {
+		add("is_ai_generated = ?", *query.IsAIGenerated)
+	}
+	if query.FavoriteWeather != "" {
+		add("favorite_weather = ?", query.FavoriteWeather)
+	}
+	if query.Experiment != "" {
+		add("experiment = ?", query.Experiment)
+	}
+	if query.Variant != "" {
+		add("experiment_variant = ?", query.Variant)
+	}
+	if !query.GeneratedAfter.IsZero() {
+		add("generated_at >= ?", formatSQLiteTime(query.GeneratedAfter))
+	}
+	if !query.GeneratedBefore.IsZero() {
+		add("generated_at < ?", formatSQLiteTime(query.GeneratedBefore))
+	}
+	if query.MinLength > 0 {
+		add("length >= ?", query.MinLength)
+	}
+	if query.MaxLength > 0 {
+		add("length <= ?", query.MaxLength)
+	}
+	if query.MinWeight > 0 {
+		add("weight >= ?", query.MinWeight)
+	}
+	if query.MaxWeight > 0 {
+		add("weight <= ?", query.MaxWeight)
+	}
+
+	column := sqliteFishSortColumn(query.SortBy)
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	// Keyset pagination: continue strictly after the (sort key, id) of the previous page
+	if cursor != nil {
+		var lastKey interface{} = cursor.Key
+		if query.SortBy == SortByGeneratedAt {
+			lastKey = formatSQLiteTime(time.UnixMilli(int64(cursor.Key)))
+		}
+		add(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, comparison, column, comparison),
+			lastKey, lastKey, cursor.ID)
+	}
+
+	orderAndLimit := fmt.Sprintf("ORDER BY %s %s, id %s LIMIT %d", column, direction, direction, query.Limit+1)
+	rows, err := s.queryFish(ctx, strings.Join(conditions, " AND "), args, orderAndLimit)
+	if err != nil {
+		return nil, err
+	}
+	return fishDataSlice(rows), nil
+}
+
+// sqliteFishSortColumn maps a sort field to its fish column
+func sqliteFishSortColumn(sortBy FishSortField) string {
+	switch sortBy {
+	case SortByValue:
+		return "value"
+	case SortBySize:
+		return "length"
+	case SortByRarity:
+		return "rarity_rank"
+	default:
+		return "generated_at"
+	}
+}
+
+// GetDailyFishCount returns the number of fish generated today
+func (s *SQLiteDB) GetDailyFishCount(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT count FROM daily_fish_counts WHERE date = ?`,
+		time.Now().Format("2006-01-02")).Scan(&count)
+	if err == sql.ErrNoRows {
+		// No fish generated today yet
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find fish limit record: %v", err)
+	}
+	return count, nil
+}
+
+// SaveUsedNewsIDs marks the given news IDs as used
+func (s *SQLiteDB) SaveUsedNewsIDs(ctx context.Context, usedIDs map[string]bool) error {
+	if len(usedIDs) == 0 {
+		return nil // Nothing to save
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin used news transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := formatSQLiteTime(time.Now())
+	for newsID := range usedIDs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO used_news (news_id, used_at) VALUES (?, ?)
+			ON CONFLICT (news_id) DO UPDATE SET used_at = excluded.used_at`, newsID, now)
+		if err != nil {
+			return fmt.Errorf("failed to save used news IDs: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save used news IDs: %v", err)
+	}
+	return nil
+}
+
+// GetUsedNewsIDs retrieves all used news IDs
+func (s *SQLiteDB) GetUsedNewsIDs(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT news_id FROM used_news`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query used news IDs: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var newsID string
+		if err := rows.Scan(&newsID); err != nil {
+			return nil, fmt.Errorf("failed to decode used news records: %v", err)
+		}
+		result[newsID] = true
+	}
+
+	return result, rows.Err()
+}
+
+// SaveGenerationQueue replaces the pending generation queue
+func (s *SQLiteDB) SaveGenerationQueue(ctx context.Context, queue []data.GenerationRequest) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin queue transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM generation_queue WHERE status = 'pending'`); err != nil {
+		return fmt.Errorf("failed to clear pending generation requests: %v", err)
+	}
+
+	for _, request := range queue {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO generation_queue (id, reason, added_at, status) VALUES (?, ?, ?, 'pending')`,
+			primitive.NewObjectID().Hex(), request.Reason, formatSQLiteTime(request.AddedAt))
+		if err != nil {
+			return fmt.Errorf("failed to save generation queue: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save generation queue: %v", err)
+	}
+	return nil
+}
+
+// GetGenerationQueue retrieves the pending generation requests, oldest first
+func (s *SQLiteDB) GetGenerationQueue(ctx context.Context) ([]data.GenerationRequest, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, reason, added_at FROM generation_queue WHERE status = 'pending' ORDER BY added_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query generation queue: %v", err)
+	}
+	defer rows.Close()
+
+	var result []data.GenerationRequest
+	for rows.Next() {
+		var request data.GenerationRequest
+		var addedAt string
+		if err := rows.Scan(&request.ID, &request.Reason, &addedAt); err != nil {
+			return nil, fmt.Errorf("failed to decode queue records: %v", err)
+		}
+		request.AddedAt = parseSQLiteTime(addedAt)
+		result = append(result, request)
+	}
+
+	return result, rows.Err()
+}
+
+// SaveTranslatedFish saves the translated fish, replacing any earlier translation
+func (s *SQLiteDB) SaveTranslatedFish(ctx context.Context, translatedFish *data.TranslatedFish) error {
+	if translatedFish.Language == "" {
+		return fmt.Errorf("translation language is required")
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO translated_fish (id, original_id, language, name, description, appearance, color,
+			diet, habitat, effect, favorite_weather, existence_reason, translated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (original_id, language) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description,
+			appearance = excluded.appearance,
+			color = excluded.color,
+			diet = excluded.diet,
+			habitat = excluded.habitat,
+			effect = excluded.effect,
+			favorite_weather = excluded.favorite_weather,
+			existence_reason = excluded.existence_reason,
+			translated_at = excluded.translated_at`,
+		primitive.NewObjectID().Hex(), translatedFish.OriginalID, translatedFish.Language, translatedFish.Name,
+		translatedFish.Description, translatedFish.Appearance, translatedFish.Color, translatedFish.Diet,
+		translatedFish.Habitat, translatedFish.Effect, translatedFish.FavoriteWeather,
+		translatedFish.ExistenceReason, formatSQLiteTime(translatedFish.TranslatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save translated fish: %v", err)
+	}
+
+	log.Printf("Saved translated fish for original ID: %s", translatedFish.OriginalID)
+	return nil
+}
+
+// GetTranslatedFish retrieves the translation of a fish into the given language
+func (s *SQLiteDB) GetTranslatedFish(ctx context.Context, originalID, language string) (*data.TranslatedFish, error) {
+	result := &data.TranslatedFish{OriginalID: originalID, Language: language}
+	var translatedAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT name, description, appearance, color, diet, habitat, effect, favorite_weather,
+			existence_reason, translated_at
+		FROM translated_fish WHERE original_id = ? AND language = ?`, originalID, language).Scan(
+		&result.Name, &result.Description, &result.Appearance, &result.Color, &result.Diet,
+		&result.Habitat, &result.Effect, &result.FavoriteWeather, &result.ExistenceReason, &translatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // No translation found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve translated fish: %v", err)
+	}
+
+	result.TranslatedAt = parseSQLiteTime(translatedAt)
+	return result, nil
+}
+
+// GetUntranslatedFishIDs retrieves IDs of published fish that have no translation in the given language, newest first.
+// A limit of zero or less returns every such fish.
+func (s *SQLiteDB) GetUntranslatedFishIDs(ctx context.Context, language string, limit int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.id FROM fish f
+		WHERE f.status = ?
+		AND NOT EXISTS (SELECT 1 FROM translated_fish t WHERE t.original_id = f.id AND t.language = ?)
+		ORDER BY f.generated_at DESC`+sqliteLimit(limit), data.FishStatusPublished, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fish collection: %v", err)
+	}
+	defer rows.Close()
+
+	untranslatedIDs := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to decode fish document: %v", err)
+		}
+		untranslatedIDs = append(untranslatedIDs, id)
+	}
+
+	return untranslatedIDs, rows.Err()
+}
+
+// GetFishData retrieves a fish by its ID
+func (s *SQLiteDB) GetFishData(ctx context.Context, id string) (*FishData, error) {
+	rows, err := s.queryFish(ctx, "id = ?", []interface{}{id}, "LIMIT 1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve fish: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrFishNotFound, id)
+	}
+
+	return &rows[0].FishData, nil
+}
+
+// GetLLMCacheEntry retrieves a cached LLM response by key
+func (s *SQLiteDB) GetLLMCacheEntry(ctx context.Context, key string) (*data.LLMCacheEntry, error) {
+	entry := &data.LLMCacheEntry{Key: key}
+	var createdAt, lastHitAt, expiresAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT provider, model, options, prompt, response, prompt_tokens, response_tokens, hits,
+			created_at, last_hit_at, expires_at
+		FROM llm_cache WHERE key = ?`, key).Scan(
+		&entry.Provider, &entry.Model, &entry.Options, &entry.Prompt, &entry.Response, &entry.PromptTokens,
+		&entry.ResponseTokens, &entry.Hits, &createdAt, &lastHitAt, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // Not cached
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve LLM cache entry: %v", err)
+	}
+
+	entry.CreatedAt = parseSQLiteTime(createdAt)
+	entry.LastHitAt = parseSQLiteTime(lastHitAt)
+	entry.ExpiresAt = parseSQLiteTime(expiresAt)
+	return entry, nil
+}
+
+// SaveLLMCacheEntry inserts or replaces a cached LLM response
+func (s *SQLiteDB) SaveLLMCacheEntry(ctx context.Context, entry *data.LLMCacheEntry) error {
+	lastHitAt := ""
+	if !entry.LastHitAt.IsZero() {
+		lastHitAt = formatSQLiteTime(entry.LastHitAt)
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO llm_cache (key, provider, model, options, prompt, response, prompt_tokens,
+			response_tokens, hits, created_at, last_hit_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Key, entry.Provider, entry.Model, entry.Options, entry.Prompt, entry.Response, entry.PromptTokens,
+		entry.ResponseTokens, entry.Hits, formatSQLiteTime(entry.CreatedAt), lastHitAt,
+		formatSQLiteTime(entry.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to save LLM cache entry: %v", err)
+	}
+	return nil
+}
+
+// RecordLLMCacheHit increments the hit count of a cached LLM response
+func (s *SQLiteDB) RecordLLMCacheHit(ctx context.Context, key string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE llm_cache SET hits = hits + 1, last_hit_at = ? WHERE key = ?`,
+		formatSQLiteTime(at), key)
+	if err != nil {
+		return fmt.Errorf("failed to update LLM cache entry: %v", err)
+	}
+	return nil
+}
+
+// DeleteLLMCacheEntry removes a cached LLM response
+func (s *SQLiteDB) DeleteLLMCacheEntry(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM llm_cache WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("failed to delete LLM cache entry: %v", err)
+	}
+	return nil
+}
+
+// PruneLLMCache removes expired LLM responses, then the oldest ones beyond maxEntries
+func (s *SQLiteDB) PruneLLMCache(ctx context.Context, now time.Time, maxEntries int) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM llm_cache WHERE expires_at <= ?`, formatSQLiteTime(now))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired LLM cache entries: %v", err)
+	}
+	expired, _ := result.RowsAffected()
+	if maxEntries <= 0 {
+		return int(expired), nil
+	}
+
+	result, err = s.db.ExecContext(ctx, `
+		DELETE FROM llm_cache WHERE key IN (
+			SELECT key FROM llm_cache ORDER BY created_at DESC LIMIT -1 OFFSET ?
+		)`, maxEntries)
+	if err != nil {
+		return int(expired), fmt.Errorf("failed to evict LLM cache entries: %v", err)
+	}
+	evicted, _ := result.RowsAffected()
+	return int(expired + evicted), nil
+}
+
+// SaveLLMUsage records a single LLM call
+func (s *SQLiteDB) SaveLLMUsage(ctx context.Context, record *data.LLMUsageRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO llm_usage (timestamp, task, provider, model, prompt_tokens, response_tokens,
+			latency_ms, outcome, error, cost_usd)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		formatSQLiteTime(record.Timestamp), record.Task, record.Provider, record.Model, record.PromptTokens,
+		record.ResponseTokens, record.LatencyMs, record.Outcome, record.Error, record.CostUSD)
+	if err != nil {
+		return fmt.Errorf("failed to save LLM usage: %v", err)
+	}
+	return nil
+}
+
+// GetLLMUsageTotals aggregates LLM calls made in [from, to) per UTC day, task and model
+func (s *SQLiteDB) GetLLMUsageTotals(ctx context.Context, from, to time.Time) ([]data.LLMUsageTotal, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT substr(timestamp, 1, 10) AS day, task, model, COUNT(*),
+			SUM(CASE WHEN outcome = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN outcome IN (?, ?) THEN 1 ELSE 0 END),
+			SUM(prompt_tokens), SUM(response_tokens), AVG(latency_ms), SUM(cost_usd)
+		FROM llm_usage WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY day, task, model
+		ORDER BY day, task, model`,
+		data.LLMOutcomeCached, data.LLMOutcomeError, data.LLMOutcomeInvalid,
+		formatSQLiteTime(from), formatSQLiteTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query LLM usage: %v", err)
+	}
+	defer rows.Close()
+
+	totals := make([]data.LLMUsageTotal, 0)
+	for rows.Next() {
+		var total data.LLMUsageTotal
+		if err := rows.Scan(&total.Date, &total.Task, &total.Model, &total.Calls, &total.CachedCalls,
+			&total.FailedCalls, &total.PromptTokens, &total.ResponseTokens, &total.AvgLatencyMs, &total.CostUSD); err != nil {
+			return nil, fmt.Errorf("failed to decode LLM usage: %v", err)
+		}
+		total.TotalTokens = total.PromptTokens + total.ResponseTokens
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// IncrementExperimentCounter adds one to a counter of an experiment variant
+func (s *SQLiteDB) IncrementExperimentCounter(ctx context.Context, experiment, variant, counter string) error {
+	// The counter is interpolated as a column name, so only known counters are accepted
+	if !data.IsExperimentCounter(counter) {
+		return fmt.Errorf("unknown experiment counter %q", counter)
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO experiment_counters (experiment, variant, %[1]s) VALUES (?, ?, 1)
+		ON CONFLICT (experiment, variant) DO UPDATE SET %[1]s = %[1]s + 1`, counter),
+		experiment, variant)
+	if err != nil {
+		return fmt.Errorf("failed to update experiment counter: %v", err)
+	}
+	return nil
+}
+
+// GetExperimentCounts returns the counters of every variant of an experiment, sorted by variant
+func (s *SQLiteDB) GetExperimentCounts(ctx context.Context, experiment string) ([]data.ExperimentVariantCounts, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT experiment, variant, generations, parse_failures, catches
+		FROM experiment_counters WHERE experiment = ? ORDER BY variant`, experiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query experiment counters: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make([]data.ExperimentVariantCounts, 0)
+	for rows.Next() {
+		var variant data.ExperimentVariantCounts
+		if err := rows.Scan(&variant.Experiment, &variant.Variant, &variant.Generations,
+			&variant.ParseFailures, &variant.Catches); err != nil {
+			return nil, fmt.Errorf("failed to decode experiment counters: %v", err)
+		}
+		counts = append(counts, variant)
+	}
+
+	return counts, rows.Err()
+}
+
+// IncrementAPIQuotaUsage adds one call to a source in a quota window
+func (s *SQLiteDB) IncrementAPIQuotaUsage(ctx context.Context, source, window string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_quota (source, quota_window, calls) VALUES (?, ?, 1)
+		ON CONFLICT (source, quota_window) DO UPDATE SET calls = calls + 1`,
+		source, window)
+	if err != nil {
+		return fmt.Errorf("failed to update API quota usage: %v", err)
+	}
+	return nil
+}
+
+// GetAPIQuotaUsage returns the calls made to a source in a quota window
+func (s *SQLiteDB) GetAPIQuotaUsage(ctx context.Context, source, window string) (int, error) {
+	var calls int
+	err := s.db.QueryRowContext(ctx, `SELECT calls FROM api_quota WHERE source = ? AND quota_window = ?`,
+		source, window).Scan(&calls)
+	if err == sql.ErrNoRows {
+		return 0, nil // No calls yet
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve API quota usage: %v", err)
+	}
+	return calls, nil
+}
+
+// SaveFishAuditEntry records an admin action on a fish
+func (s *SQLiteDB) SaveFishAuditEntry(ctx context.Context, entry *data.FishAuditEntry) error {
+	changes, err := json.Marshal(nonNilSlice(entry.Changes))
+	if err != nil {
+		return fmt.Errorf("failed to encode fish audit changes: %v", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO fish_audit (fish_id, fish_name, admin, action, changes, reason, at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.FishID, entry.FishName, entry.Admin, entry.Action, string(changes), entry.Reason, formatSQLiteTime(entry.At))
+	if err != nil {
+		return fmt.Errorf("failed to save fish audit entry: %v", err)
+	}
+	return nil
+}
+
+// GetFishAuditEntries returns the audit trail of a fish, or of every fish when fishID is empty, newest first
+func (s *SQLiteDB) GetFishAuditEntries(ctx context.Context, fishID string, limit int) ([]*data.FishAuditEntry, error) {
+	query := `SELECT fish_id, fish_name, admin, action, changes, reason, at FROM fish_audit`
+	var args []interface{}
+	if fishID != "" {
+		query += ` WHERE fish_id = ?`
+		args = append(args, fishID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY at DESC, id DESC`+sqliteLimit(limit), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fish audit entries: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*data.FishAuditEntry, 0)
+	for rows.Next() {
+		var entry data.FishAuditEntry
+		var changes, at string
+		if err := rows.Scan(&entry.FishID, &entry.FishName, &entry.Admin, &entry.Action, &changes, &entry.Reason, &at); err != nil {
+			return nil, fmt.Errorf("failed to decode fish audit entry: %v", err)
+		}
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode fish audit changes: %v", err)
+		}
+		entry.At = parseSQLiteTime(at)
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// formatSQLiteTime formats a time for storage
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+// parseSQLiteTime parses a stored time, returning the zero time for invalid values
+func parseSQLiteTime(value string) time.Time {
+	t, err := time.Parse(sqliteTimeLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseSQLiteObjectID converts a stored hex ID back to an ObjectID
+func parseSQLiteObjectID(value string) primitive.ObjectID {
+	id, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		return primitive.NilObjectID
+	}
+	return id
+}
+
+// sqliteLimit returns a LIMIT clause for positive limits
+func sqliteLimit(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+// nonNilSlice makes sure nil slices are encoded as empty JSON arrays
+func nonNilSlice[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
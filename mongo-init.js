@@ -4,12 +4,8 @@
 // Create the fish_generator database
 db = db.getSiblingDB('fish_generator');
 
-// Create collections
-db.createCollection('fish');
-db.createCollection('news');
-db.createCollection('weather');
-db.createCollection('prices');
-db.createCollection('state');
+// Collections and indexes are created by the service's schema migrations
+// (see internal/storage/migrations.go), so nothing else is needed here.
 
 // Create a specific user for the fish_generator database if needed
 // Note: We're already using the root user (fishuser) with the connection string